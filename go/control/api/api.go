@@ -10,7 +10,9 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
@@ -30,8 +32,25 @@ import (
 // ModuleName is the module name for the controller service.
 const ModuleName = "control"
 
-// ErrNotImplemented is the error raised when the node does not support the required functionality.
-var ErrNotImplemented = errors.New(ModuleName, 1, "control: not implemented")
+var (
+	// ErrNotImplemented is the error raised when the node does not support the required functionality.
+	ErrNotImplemented = errors.New(ModuleName, 1, "control: not implemented")
+
+	// ErrNoSuchRuntime is the error raised when the requested runtime is not supported by the node.
+	ErrNoSuchRuntime = errors.New(ModuleName, 2, "control: no such runtime")
+
+	// ErrNoSuchTransaction is the error raised when the requested transaction is not in the
+	// transaction pool.
+	ErrNoSuchTransaction = errors.New(ModuleName, 3, "control: no such transaction")
+
+	// ErrNoSuchComponent is the error raised when the requested runtime component is not hosted
+	// by the node.
+	ErrNoSuchComponent = errors.New(ModuleName, 4, "control: no such runtime component")
+
+	// ErrEvictionDisabled is the error raised when transaction eviction has not been enabled in
+	// the node configuration.
+	ErrEvictionDisabled = errors.New(ModuleName, 5, "control: transaction eviction is disabled")
+)
 
 // NodeController is a node controller interface.
 type NodeController interface {
@@ -69,6 +88,69 @@ type NodeController interface {
 	// If the bundle upgrades an existing ROFL component, the latter will
 	// be upgraded to the new version.
 	AddBundle(ctx context.Context, path string) error
+
+	// GetTxPoolStatus returns the transaction pool status of the given runtime, including
+	// per-queue sizes, per-sender sequence number state and metadata of queued transactions.
+	GetTxPoolStatus(ctx context.Context, runtimeID common.Namespace) (*TxPoolStatus, error)
+
+	// EvictTransaction removes the given transaction from the transaction pool of the given
+	// runtime.
+	//
+	// As eviction modifies the transaction pool, it is refused with ErrEvictionDisabled unless it
+	// has been explicitly enabled via the runtime.tx_pool.allow_eviction configuration option.
+	EvictTransaction(ctx context.Context, req *EvictTransactionRequest) error
+
+	// RestartRuntime forcibly restarts the given component of a hosted runtime.
+	//
+	// This also clears the crash looping state of the component so that it is automatically
+	// restarted again in case it terminates.
+	RestartRuntime(ctx context.Context, req *RestartRuntimeRequest) error
+
+	// GetRuntimeBundles returns the bundles installed for the given runtime together with their
+	// disk usage and whether they are in use.
+	GetRuntimeBundles(ctx context.Context, runtimeID common.Namespace) (*RuntimeBundles, error)
+
+	// WatchRuntimeLogs returns a channel that produces structured log entries of the given
+	// runtime component matching the query.
+	//
+	// Unless the query requests following the log, the channel is closed after all existing
+	// matching entries have been produced.
+	WatchRuntimeLogs(ctx context.Context, query *RuntimeLogsQuery) (<-chan *RuntimeLogEntry, pubsub.ClosableSubscription, error)
+}
+
+// RuntimeLogsQuery is a WatchRuntimeLogs query.
+type RuntimeLogsQuery struct {
+	// RuntimeID is the identifier of the runtime.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// ComponentID is the identifier of the runtime component.
+	ComponentID component.ID `json:"component_id"`
+	// Follow specifies whether to follow the log for new entries.
+	Follow bool `json:"follow,omitempty"`
+	// Since specifies a time offset where to start from (excluding the offset itself).
+	Since time.Time `json:"since,omitempty"`
+	// Until specifies a time offset where to stop (excluding the offset itself). A zero value
+	// means that there is no upper bound.
+	Until time.Time `json:"until,omitempty"`
+	// Level is the minimum log level of returned entries.
+	Level logging.Level `json:"level,omitempty"`
+	// Contains is a substring that returned log lines must contain.
+	Contains string `json:"contains,omitempty"`
+}
+
+// EvictTransactionRequest is an EvictTransaction request.
+type EvictTransactionRequest struct {
+	// RuntimeID is the identifier of the runtime.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// Hash is the hash of the transaction to evict.
+	Hash hash.Hash `json:"hash"`
+}
+
+// RestartRuntimeRequest is a RestartRuntime request.
+type RestartRuntimeRequest struct {
+	// RuntimeID is the identifier of the runtime.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// ComponentID is the identifier of the runtime component to restart.
+	ComponentID component.ID `json:"component_id"`
 }
 
 // Status is the current status overview.
@@ -202,6 +284,9 @@ type RuntimeStatus struct {
 
 	// Components contains statuses of the runtime components.
 	Components []ComponentStatus `json:"components,omitempty"`
+
+	// Downloads contains statuses of pending runtime bundle downloads.
+	Downloads []BundleDownloadStatus `json:"downloads,omitempty"`
 }
 
 // ComponentStatus is the runtime component status overview.
@@ -221,6 +306,10 @@ type ComponentStatus struct {
 	// Disabled specifies whether the component is disabled by default
 	// and needs to be explicitly enabled via node configuration to be used.
 	Disabled bool `json:"disabled,omitempty"`
+
+	// Status is the status of the component in case this version of the component is hosted
+	// and active.
+	Status *HostedComponentStatus `json:"status,omitempty"`
 }
 
 // SeedStatus is the status of the seed node.
@@ -5,7 +5,9 @@ import (
 
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	upgradeApi "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
@@ -31,6 +33,17 @@ var (
 	methodGetStatus = serviceName.NewMethod("GetStatus", nil)
 	// methodAddBundle is the AddBundle method.
 	methodAddBundle = serviceName.NewMethod("AddBundle", nil)
+	// methodGetTxPoolStatus is the GetTxPoolStatus method.
+	methodGetTxPoolStatus = serviceName.NewMethod("GetTxPoolStatus", common.Namespace{})
+	// methodEvictTransaction is the EvictTransaction method.
+	methodEvictTransaction = serviceName.NewMethod("EvictTransaction", EvictTransactionRequest{})
+	// methodRestartRuntime is the RestartRuntime method.
+	methodRestartRuntime = serviceName.NewMethod("RestartRuntime", RestartRuntimeRequest{})
+	// methodGetRuntimeBundles is the GetRuntimeBundles method.
+	methodGetRuntimeBundles = serviceName.NewMethod("GetRuntimeBundles", common.Namespace{})
+
+	// methodWatchRuntimeLogs is the WatchRuntimeLogs method.
+	methodWatchRuntimeLogs = serviceName.NewMethod("WatchRuntimeLogs", RuntimeLogsQuery{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -73,8 +86,30 @@ var (
 				MethodName: methodAddBundle.ShortName(),
 				Handler:    handlerAddBundle,
 			},
+			{
+				MethodName: methodGetTxPoolStatus.ShortName(),
+				Handler:    handlerGetTxPoolStatus,
+			},
+			{
+				MethodName: methodEvictTransaction.ShortName(),
+				Handler:    handlerEvictTransaction,
+			},
+			{
+				MethodName: methodRestartRuntime.ShortName(),
+				Handler:    handlerRestartRuntime,
+			},
+			{
+				MethodName: methodGetRuntimeBundles.ShortName(),
+				Handler:    handlerGetRuntimeBundles,
+			},
+		},
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    methodWatchRuntimeLogs.ShortName(),
+				Handler:       handlerWatchRuntimeLogs,
+				ServerStreams: true,
+			},
 		},
-		Streams: []grpc.StreamDesc{},
 	}
 )
 
@@ -265,6 +300,127 @@ func handlerAddBundle(
 	return interceptor(ctx, &path, info, handler)
 }
 
+func handlerGetTxPoolStatus(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var runtimeID common.Namespace
+	if err := dec(&runtimeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeController).GetTxPoolStatus(ctx, runtimeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetTxPoolStatus.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(NodeController).GetTxPoolStatus(ctx, *req.(*common.Namespace))
+	}
+	return interceptor(ctx, &runtimeID, info, handler)
+}
+
+func handlerEvictTransaction(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var req EvictTransactionRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).EvictTransaction(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodEvictTransaction.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(NodeController).EvictTransaction(ctx, req.(*EvictTransactionRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func handlerRestartRuntime(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var req RestartRuntimeRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).RestartRuntime(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodRestartRuntime.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(NodeController).RestartRuntime(ctx, req.(*RestartRuntimeRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func handlerGetRuntimeBundles(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var runtimeID common.Namespace
+	if err := dec(&runtimeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeController).GetRuntimeBundles(ctx, runtimeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetRuntimeBundles.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(NodeController).GetRuntimeBundles(ctx, *req.(*common.Namespace))
+	}
+	return interceptor(ctx, &runtimeID, info, handler)
+}
+
+func handlerWatchRuntimeLogs(srv any, stream grpc.ServerStream) error {
+	var query RuntimeLogsQuery
+	if err := stream.RecvMsg(&query); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(NodeController).WatchRuntimeLogs(ctx, &query)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case entry, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(entry); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // RegisterService registers a new node controller service with the given gRPC server.
 func RegisterService(server *grpc.Server, service NodeController) {
 	server.RegisterService(&serviceDesc, service)
@@ -333,3 +489,62 @@ func (c *NodeControllerClient) AddBundle(ctx context.Context, path string) error
 	}
 	return nil
 }
+
+func (c *NodeControllerClient) GetTxPoolStatus(ctx context.Context, runtimeID common.Namespace) (*TxPoolStatus, error) {
+	var rsp TxPoolStatus
+	if err := c.conn.Invoke(ctx, methodGetTxPoolStatus.FullName(), runtimeID, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *NodeControllerClient) EvictTransaction(ctx context.Context, req *EvictTransactionRequest) error {
+	return c.conn.Invoke(ctx, methodEvictTransaction.FullName(), req, nil)
+}
+
+func (c *NodeControllerClient) RestartRuntime(ctx context.Context, req *RestartRuntimeRequest) error {
+	return c.conn.Invoke(ctx, methodRestartRuntime.FullName(), req, nil)
+}
+
+func (c *NodeControllerClient) GetRuntimeBundles(ctx context.Context, runtimeID common.Namespace) (*RuntimeBundles, error) {
+	var rsp RuntimeBundles
+	if err := c.conn.Invoke(ctx, methodGetRuntimeBundles.FullName(), runtimeID, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *NodeControllerClient) WatchRuntimeLogs(ctx context.Context, query *RuntimeLogsQuery) (<-chan *RuntimeLogEntry, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[0], methodWatchRuntimeLogs.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(query); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *RuntimeLogEntry)
+	go func() {
+		defer close(ch)
+
+		for {
+			var entry RuntimeLogEntry
+			if serr := stream.RecvMsg(&entry); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &entry:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+// HostedComponentStatus is the status of a hosted runtime component.
+type HostedComponentStatus struct {
+	// State is the state of the component (one of starting, running, stopped or crash_looping).
+	State string `json:"state"`
+
+	// Crashes is the number of times the component terminated unexpectedly or failed to start
+	// since it has been provisioned.
+	Crashes uint64 `json:"crashes"`
+
+	// StartedAt is the time when the component was last started. It is zero in case the
+	// component is not running.
+	StartedAt time.Time `json:"started_at"`
+
+	// Resources is the resource usage of the component. It may be nil in case the component is
+	// not running or the provisioner does not support reporting resource usage.
+	Resources *HostedComponentResources `json:"resources,omitempty"`
+
+	// AttestedAt is the time of the last successful attestation of the component. It is zero in
+	// case the component is not running inside a TEE or has not yet been attested.
+	AttestedAt time.Time `json:"attested_at"`
+
+	// RAK is the runtime attestation key of the running component. It may be nil in case the
+	// component is not running inside a TEE.
+	RAK *signature.PublicKey `json:"rak,omitempty"`
+}
+
+// HostedComponentResources is the resource usage of a hosted runtime component.
+type HostedComponentResources struct {
+	// CPUTime is the total CPU time consumed by the component since it was last started.
+	CPUTime time.Duration `json:"cpu_time"`
+
+	// Memory is the amount of resident memory used by the component in bytes.
+	Memory uint64 `json:"memory"`
+}
+
+// BundleDownloadStatus is the status of a pending runtime bundle download.
+type BundleDownloadStatus struct {
+	// ManifestHash is the hash of the manifest of the bundle being downloaded.
+	ManifestHash hash.Hash `json:"manifest_hash"`
+
+	// Mirrors are the URLs from which the bundle is being downloaded.
+	Mirrors []string `json:"mirrors,omitempty"`
+
+	// Size is the size of the bundle in bytes, if known.
+	Size uint64 `json:"size,omitempty"`
+
+	// Downloaded is the number of bytes downloaded so far, including resumed data.
+	Downloaded uint64 `json:"downloaded"`
+
+	// StartedAt is the time of the first download attempt.
+	StartedAt time.Time `json:"started_at"`
+
+	// LastError is the error of the last failed download attempt.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// TxPoolStatus is the transaction pool status of a runtime.
+type TxPoolStatus struct {
+	// CheckQueueSize is the number of transactions waiting to be checked.
+	CheckQueueSize int `json:"check_queue_size"`
+	// MainQueueSize is the number of checked transactions in the main queue.
+	MainQueueSize int `json:"main_queue_size"`
+	// RimQueueSize is the number of transactions from roothash incoming messages.
+	RimQueueSize int `json:"rim_queue_size"`
+	// ProposedSize is the number of transactions in the current proposal.
+	ProposedSize int `json:"proposed_size"`
+
+	// Senders is the sequence number state of each sender with transactions in the main queue.
+	Senders []*TxPoolSenderStatus `json:"senders,omitempty"`
+	// Transactions are the transactions in the main queue.
+	Transactions []*TxPoolTransactionStatus `json:"transactions,omitempty"`
+}
+
+// TxPoolSenderStatus is the sequence number state of a transaction sender.
+type TxPoolSenderStatus struct {
+	// Sender is the sender identifier as specified by the runtime.
+	Sender []byte `json:"sender"`
+	// StateSeq is the sender's sequence number as of the latest checked state.
+	StateSeq uint64 `json:"state_seq"`
+	// Transactions is the number of the sender's transactions in the main queue.
+	Transactions int `json:"transactions"`
+	// Schedulable is the number of the sender's transactions that have consecutive sequence
+	// numbers starting at the state sequence number and can therefore be scheduled.
+	Schedulable int `json:"schedulable"`
+	// MissingSeq is the first missing sequence number in case some of the sender's transactions
+	// cannot be scheduled because of a sequence number gap.
+	MissingSeq *uint64 `json:"missing_seq,omitempty"`
+}
+
+// TxPoolTransactionStatus is the status of a transaction in the main queue.
+type TxPoolTransactionStatus struct {
+	// Hash is the transaction hash.
+	Hash hash.Hash `json:"hash"`
+	// Size is the size of the raw transaction (bytes).
+	Size int `json:"size"`
+	// FirstSeen is the time the transaction was first seen.
+	FirstSeen time.Time `json:"first_seen"`
+	// Local indicates whether the transaction was submitted by our own node.
+	Local bool `json:"local,omitempty"`
+	// Rechecks is the number of times the transaction has been rechecked.
+	Rechecks uint64 `json:"rechecks"`
+
+	// Sender is the sender identifier as specified by the runtime.
+	Sender []byte `json:"sender"`
+	// Seq is the sender's sequence number as specified by the runtime.
+	Seq uint64 `json:"seq"`
+	// Priority is the transaction priority as specified by the runtime.
+	Priority uint64 `json:"priority"`
+}
+
+// RuntimeBundles are the bundles installed for a runtime.
+type RuntimeBundles struct {
+	// ActiveVersion is the active runtime version, if known.
+	ActiveVersion *version.Version `json:"active_version,omitempty"`
+
+	// Size is the total disk usage of all installed bundles of the runtime in bytes.
+	Size uint64 `json:"size"`
+
+	// Bundles are the installed bundles.
+	Bundles []*InstalledBundle `json:"bundles,omitempty"`
+}
+
+// InstalledBundle is an installed runtime bundle.
+type InstalledBundle struct {
+	// ManifestHash is the hash of the bundle manifest.
+	ManifestHash hash.Hash `json:"manifest_hash"`
+
+	// Name is the optional human readable runtime name.
+	Name string `json:"name,omitempty"`
+
+	// Labels are the labels attached to the bundle.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Detached specifies whether the bundle is detached.
+	Detached bool `json:"detached,omitempty"`
+
+	// Size is the disk usage of the exploded bundle in bytes.
+	Size uint64 `json:"size"`
+
+	// InUse specifies whether the bundle provides the active runtime version or the latest
+	// version of any of its components.
+	InUse bool `json:"in_use"`
+
+	// Components are the components provided by the bundle.
+	Components []*InstalledComponent `json:"components"`
+}
+
+// InstalledComponent is a component of an installed runtime bundle.
+type InstalledComponent struct {
+	// ID is the component identifier.
+	ID component.ID `json:"id"`
+
+	// Version is the component version.
+	Version version.Version `json:"version"`
+
+	// Size is the total size of the component files in bytes.
+	Size uint64 `json:"size"`
+}
+
+// RuntimeLogEntry is a structured log entry of a runtime component.
+type RuntimeLogEntry struct {
+	// Timestamp is the time at which the entry was logged.
+	Timestamp time.Time `json:"ts"`
+
+	// Level is the log level of the entry.
+	Level string `json:"level"`
+
+	// Component is the identifier of the component that emitted the entry.
+	Component component.ID `json:"component"`
+
+	// Module is the module within the component that emitted the entry.
+	Module string `json:"module,omitempty"`
+
+	// Message is the log message.
+	Message string `json:"msg"`
+
+	// Fields are any additional fields of the entry in their JSON encoding.
+	Fields map[string]json.RawMessage `json:"fields,omitempty"`
+}
+
+// runtimeLogEntryWire is the serialized form of a runtime log entry which preserves the
+// sub-second precision of the timestamp.
+type runtimeLogEntryWire struct {
+	*plainRuntimeLogEntry
+
+	// Timestamp is the time at which the entry was logged in nanoseconds since the Unix epoch.
+	Timestamp int64 `json:"ts"`
+}
+
+// plainRuntimeLogEntry is a runtime log entry without the custom serialization methods.
+type plainRuntimeLogEntry RuntimeLogEntry
+
+// MarshalCBOR encodes the runtime log entry into CBOR.
+func (e *RuntimeLogEntry) MarshalCBOR() ([]byte, error) {
+	wire := runtimeLogEntryWire{
+		plainRuntimeLogEntry: (*plainRuntimeLogEntry)(e),
+	}
+	if !e.Timestamp.IsZero() {
+		wire.Timestamp = e.Timestamp.UnixNano()
+	}
+	return cbor.Marshal(wire), nil
+}
+
+// UnmarshalCBOR decodes the runtime log entry from CBOR.
+func (e *RuntimeLogEntry) UnmarshalCBOR(data []byte) error {
+	wire := runtimeLogEntryWire{
+		plainRuntimeLogEntry: (*plainRuntimeLogEntry)(e),
+	}
+	if err := cbor.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	e.Timestamp = time.Time{}
+	if wire.Timestamp != 0 {
+		e.Timestamp = time.Unix(0, wire.Timestamp).UTC()
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+// Package bundle implements the runtime bundle sub-commands.
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+)
+
+var logger = logging.GetLogger("cmd/bundle")
+
+func newCreateCmd() *cobra.Command {
+	var (
+		assetsDir string
+		output    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create <manifest-template>",
+		Args:  cobra.ExactArgs(1),
+		Short: "create a runtime bundle from a manifest template",
+		Long: "Creates a runtime bundle from a JSON manifest template. All files referenced by the " +
+			"components in the template are added to the bundle and their digests are computed " +
+			"automatically.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			if output == "" {
+				return fmt.Errorf("output file must be specified")
+			}
+			if assetsDir == "" {
+				assetsDir = filepath.Dir(args[0])
+			}
+
+			bnd, err := createBundle(args[0], assetsDir)
+			if err != nil {
+				return err
+			}
+			if err = bnd.Write(output); err != nil {
+				return err
+			}
+
+			fmt.Printf("Created bundle %s with manifest hash %s.\n", output, bnd.Manifest.Hash())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&assetsDir, "assets", "", "directory containing the bundle assets (default: manifest template directory)")
+	cmd.Flags().StringVar(&output, "output", "", "path to the output bundle file")
+
+	return cmd
+}
+
+// createBundle creates a new bundle from the given manifest template, loading all referenced
+// assets from the given directory.
+func createBundle(templatePath string, assetsDir string) (*bundle.Bundle, error) {
+	b, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest template: %w", err)
+	}
+
+	var manifest bundle.Manifest
+	if err = json.Unmarshal(b, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest template: %w", err)
+	}
+	// Digests are always computed from the assets.
+	manifest.Digests = nil
+
+	bnd := &bundle.Bundle{
+		Manifest: &manifest,
+	}
+	for _, comp := range manifest.Components {
+		for _, fn := range comp.Files() {
+			if _, ok := bnd.Data[fn]; ok {
+				continue
+			}
+
+			path := filepath.Join(assetsDir, fn)
+			if _, err = os.Stat(path); err != nil {
+				return nil, fmt.Errorf("missing asset for component '%s': %w", comp.ID(), err)
+			}
+			if err = bnd.Add(fn, bundle.NewFileData(path)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = bnd.Validate(); err != nil {
+		return nil, err
+	}
+
+	return bnd, nil
+}
+
+func newInspectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect <bundle>",
+		Args:  cobra.ExactArgs(1),
+		Short: "display information about a runtime bundle",
+		RunE: func(_ *cobra.Command, args []string) error {
+			info, err := inspectFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			prettyInfo, err := cmdCommon.PrettyJSONMarshal(info)
+			if err != nil {
+				return fmt.Errorf("failed to get pretty JSON of bundle information: %w", err)
+			}
+			fmt.Println(string(prettyInfo))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newVerifyCmd() *cobra.Command {
+	var (
+		publishers []string
+		threshold  uint64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "verify <bundle>",
+		Args:  cobra.ExactArgs(1),
+		Short: "verify integrity of a runtime bundle",
+		Long: "Verifies that the manifest is well-formed, that the digests of all assets match the " +
+			"manifest and that the SGX signatures are valid. When publishers are given, it also " +
+			"verifies that the manifest is signed by at least threshold of them.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			bnd, err := bundle.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("bundle verification failed: %w", err)
+			}
+			defer bnd.Close()
+
+			if len(publishers) > 0 {
+				keys := make([]signature.PublicKey, 0, len(publishers))
+				for _, publisher := range publishers {
+					var pk signature.PublicKey
+					if err = pk.UnmarshalText([]byte(publisher)); err != nil {
+						return fmt.Errorf("malformed publisher key '%s': %w", publisher, err)
+					}
+					keys = append(keys, pk)
+				}
+				if err = bnd.VerifySignatures(keys, max(threshold, 1)); err != nil {
+					return fmt.Errorf("bundle verification failed: %w", err)
+				}
+			}
+
+			fmt.Printf("Bundle is valid (manifest hash: %s).\n", bnd.Manifest.Hash())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&publishers, "publisher", nil, "public key of a trusted publisher (can be repeated)")
+	cmd.Flags().Uint64Var(&threshold, "threshold", 1, "minimum number of trusted publishers that must sign the manifest")
+
+	return cmd
+}
+
+func newSignCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "sign <bundle>",
+		Args:  cobra.ExactArgs(1),
+		Short: "sign the manifest of a runtime bundle with the entity signer",
+		Long: "Adds a detached signature over the bundle manifest made by the entity signer. " +
+			"Existing signatures made by other publishers are preserved.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := cmdCommon.Init(); err != nil {
+				cmdCommon.EarlyLogAndExit(err)
+			}
+			if output == "" {
+				output = args[0]
+			}
+
+			_, signer, err := cmdCommon.LoadEntitySigner()
+			if err != nil {
+				return fmt.Errorf("failed to load entity signer: %w", err)
+			}
+			defer signer.Reset()
+
+			bnd, err := bundle.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer bnd.Close()
+
+			if err = bnd.Sign(signer); err != nil {
+				return err
+			}
+			bnd.ResetManifest()
+			if err = bnd.Write(output); err != nil {
+				return err
+			}
+
+			logger.Info("signed bundle",
+				"manifest_hash", bnd.Manifest.Hash(),
+				"publisher", signer.Public(),
+				"output", output,
+			)
+			fmt.Printf("Signed bundle %s as publisher %s.\n", output, signer.Public())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&output, "output", "", "path to the output bundle file (default: overwrite the bundle)")
+	cmd.Flags().AddFlagSet(cmdSigner.Flags)
+	cmd.Flags().AddFlagSet(cmdSigner.CLIFlags)
+
+	return cmd
+}
+
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <old-bundle> <new-bundle>",
+		Args:  cobra.ExactArgs(2),
+		Short: "show differences between two runtime bundles",
+		RunE: func(_ *cobra.Command, args []string) error {
+			oldInfo, err := inspectFile(args[0])
+			if err != nil {
+				return err
+			}
+			newInfo, err := inspectFile(args[1])
+			if err != nil {
+				return err
+			}
+
+			prettyDiff, err := cmdCommon.PrettyJSONMarshal(diffBundles(oldInfo, newInfo))
+			if err != nil {
+				return fmt.Errorf("failed to get pretty JSON of bundle differences: %w", err)
+			}
+			fmt.Println(string(prettyDiff))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// Register registers the bundle sub-command and all of its children.
+func Register(parentCmd *cobra.Command) {
+	bundleCmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "runtime bundle utilities",
+	}
+
+	bundleCmd.AddCommand(newCreateCmd())
+	bundleCmd.AddCommand(newInspectCmd())
+	bundleCmd.AddCommand(newVerifyCmd())
+	bundleCmd.AddCommand(newSignCmd())
+	bundleCmd.AddCommand(newDiffCmd())
+
+	parentCmd.AddCommand(bundleCmd)
+}
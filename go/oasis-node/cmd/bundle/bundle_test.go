@@ -0,0 +1,120 @@
+package bundle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+func TestCreateInspectDiff(t *testing.T) {
+	require := require.New(t)
+
+	tmpDir := t.TempDir()
+	assetsDir := filepath.Join(tmpDir, "assets")
+	err := common.Mkdir(assetsDir)
+	require.NoError(err)
+	for fn, data := range map[string]string{
+		"runtime.bin":  "runtime",
+		"runtime.sgx":  "runtime sgx",
+		"runtime2.bin": "runtime v2",
+	} {
+		err = os.WriteFile(filepath.Join(assetsDir, fn), []byte(data), 0o600)
+		require.NoError(err)
+	}
+
+	var id common.Namespace
+	err = id.UnmarshalHex("c000000000000000ffffffffffffffffffffffffffffffffffffffffffffffff")
+	require.NoError(err)
+	manifest := &bundle.Manifest{
+		Name: "test-runtime",
+		ID:   id,
+		Components: []*bundle.Component{
+			{
+				Kind:    component.RONL,
+				Version: version.Version{Major: 1},
+				ELF: &bundle.ELFMetadata{
+					Executable: "runtime.bin",
+				},
+				SGX: &bundle.SGXMetadata{
+					Executable: "runtime.sgx",
+				},
+				Identities: []bundle.Identity{
+					{
+						Enclave: sgx.EnclaveIdentity{
+							MrSigner:  sgx.MrSigner{0x01},
+							MrEnclave: sgx.MrEnclave{0x02},
+						},
+					},
+				},
+			},
+		},
+	}
+	writeTemplate := func(fn string) string {
+		path := filepath.Join(tmpDir, fn)
+		raw, err := json.Marshal(manifest)
+		require.NoError(err)
+		err = os.WriteFile(path, raw, 0o600)
+		require.NoError(err)
+		return path
+	}
+
+	oldBnd, err := createBundle(writeTemplate("old.json"), assetsDir)
+	require.NoError(err)
+	oldFn := filepath.Join(tmpDir, "old.orc")
+	err = oldBnd.Write(oldFn)
+	require.NoError(err)
+
+	oldInfo, err := inspectFile(oldFn)
+	require.NoError(err)
+	require.Equal("test-runtime", oldInfo.Name)
+	require.Equal(oldBnd.Manifest.Hash(), oldInfo.ManifestHash)
+	require.Len(oldInfo.Components, 1)
+	comp := oldInfo.Components[0]
+	require.Equal("1.0.0", comp.Version)
+	require.Equal(component.TEEKindSGX.String(), comp.TEE)
+	require.Equal([]identityInfo{{
+		MrEnclave: sgx.MrEnclave{0x02}.String(),
+		MrSigner:  sgx.MrSigner{0x01}.String(),
+	}}, comp.Identities)
+	require.Len(comp.Files, 2)
+
+	// Missing assets should be rejected.
+	manifest.Components[0].ELF.Executable = "missing.bin"
+	_, err = createBundle(writeTemplate("missing.json"), assetsDir)
+	require.ErrorContains(err, "missing asset")
+
+	manifest.Components[0].Version = version.Version{Major: 2}
+	manifest.Components[0].ELF.Executable = "runtime2.bin"
+	manifest.Components[0].Identities[0].Enclave.MrEnclave = sgx.MrEnclave{0x03}
+	newBnd, err := createBundle(writeTemplate("new.json"), assetsDir)
+	require.NoError(err)
+	newInfo, err := inspectBundle(newBnd)
+	require.NoError(err)
+
+	diff := diffBundles(oldInfo, oldInfo)
+	require.Nil(diff.ManifestHash)
+	require.Empty(diff.ChangedComponents)
+
+	diff = diffBundles(oldInfo, newInfo)
+	require.Nil(diff.Name)
+	require.Nil(diff.ID)
+	require.NotNil(diff.ManifestHash)
+	require.Empty(diff.AddedComponents)
+	require.Empty(diff.RemovedComponents)
+	require.Len(diff.ChangedComponents, 1)
+	compDiff := diff.ChangedComponents[0]
+	require.Equal(&valueChange{Old: "1.0.0", New: "2.0.0"}, compDiff.Version)
+	require.Nil(compDiff.TEE)
+	require.Len(compDiff.AddedIdentities, 1)
+	require.Len(compDiff.RemovedIdentities, 1)
+	require.Equal([]string{"runtime.bin", "runtime2.bin"}, compDiff.ChangedFiles)
+}
@@ -0,0 +1,124 @@
+package bundle
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// bundleDiff are the differences between two runtime bundles.
+type bundleDiff struct {
+	Name              *valueChange     `json:"name,omitempty"`
+	ID                *valueChange     `json:"id,omitempty"`
+	ManifestHash      *valueChange     `json:"manifest_hash,omitempty"`
+	AddedComponents   []string         `json:"added_components,omitempty"`
+	RemovedComponents []string         `json:"removed_components,omitempty"`
+	ChangedComponents []*componentDiff `json:"changed_components,omitempty"`
+}
+
+// componentDiff are the differences between two versions of the same component.
+type componentDiff struct {
+	ID                string         `json:"id"`
+	Version           *valueChange   `json:"version,omitempty"`
+	TEE               *valueChange   `json:"tee,omitempty"`
+	Disabled          *valueChange   `json:"disabled,omitempty"`
+	TDXResources      *valueChange   `json:"tdx_resources,omitempty"`
+	AddedIdentities   []identityInfo `json:"added_identities,omitempty"`
+	RemovedIdentities []identityInfo `json:"removed_identities,omitempty"`
+	ChangedFiles      []string       `json:"changed_files,omitempty"`
+}
+
+// valueChange is a change of a single value.
+type valueChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// newValueChange returns a change between the given values or nil in case they are equal.
+func newValueChange(oldValue, newValue any) *valueChange {
+	if reflect.DeepEqual(oldValue, newValue) {
+		return nil
+	}
+	return &valueChange{Old: oldValue, New: newValue}
+}
+
+// IsEmpty returns true iff there are no differences between the components.
+func (d *componentDiff) IsEmpty() bool {
+	return d.Version == nil && d.TEE == nil && d.Disabled == nil && d.TDXResources == nil &&
+		len(d.AddedIdentities) == 0 && len(d.RemovedIdentities) == 0 && len(d.ChangedFiles) == 0
+}
+
+// diffBundles returns the differences between the two given bundles.
+func diffBundles(oldInfo, newInfo *bundleInfo) *bundleDiff {
+	diff := &bundleDiff{
+		Name:         newValueChange(oldInfo.Name, newInfo.Name),
+		ID:           newValueChange(oldInfo.ID, newInfo.ID),
+		ManifestHash: newValueChange(oldInfo.ManifestHash, newInfo.ManifestHash),
+	}
+
+	oldComps := make(map[string]*componentInfo)
+	for _, comp := range oldInfo.Components {
+		oldComps[comp.ID] = comp
+	}
+	newComps := make(map[string]*componentInfo)
+	for _, comp := range newInfo.Components {
+		newComps[comp.ID] = comp
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(oldComps)) {
+		if _, ok := newComps[id]; !ok {
+			diff.RemovedComponents = append(diff.RemovedComponents, id)
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(newComps)) {
+		oldComp, ok := oldComps[id]
+		if !ok {
+			diff.AddedComponents = append(diff.AddedComponents, id)
+			continue
+		}
+		if compDiff := diffComponents(oldComp, newComps[id]); !compDiff.IsEmpty() {
+			diff.ChangedComponents = append(diff.ChangedComponents, compDiff)
+		}
+	}
+
+	return diff
+}
+
+// diffComponents returns the differences between two versions of the same component.
+func diffComponents(oldComp, newComp *componentInfo) *componentDiff {
+	diff := &componentDiff{
+		ID:           newComp.ID,
+		Version:      newValueChange(oldComp.Version, newComp.Version),
+		TEE:          newValueChange(oldComp.TEE, newComp.TEE),
+		Disabled:     newValueChange(oldComp.Disabled, newComp.Disabled),
+		TDXResources: newValueChange(oldComp.TDXResources, newComp.TDXResources),
+	}
+
+	for _, id := range oldComp.Identities {
+		if !slices.Contains(newComp.Identities, id) {
+			diff.RemovedIdentities = append(diff.RemovedIdentities, id)
+		}
+	}
+	for _, id := range newComp.Identities {
+		if !slices.Contains(oldComp.Identities, id) {
+			diff.AddedIdentities = append(diff.AddedIdentities, id)
+		}
+	}
+
+	fns := make(map[string]struct{})
+	for fn := range oldComp.Files {
+		fns[fn] = struct{}{}
+	}
+	for fn := range newComp.Files {
+		fns[fn] = struct{}{}
+	}
+	for _, fn := range slices.Sorted(maps.Keys(fns)) {
+		oldDigest, oldOk := oldComp.Files[fn]
+		newDigest, newOk := newComp.Files[fn]
+		if oldOk != newOk || !oldDigest.Equal(&newDigest) {
+			diff.ChangedFiles = append(diff.ChangedFiles, fn)
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,104 @@
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+// bundleInfo is the information about a runtime bundle.
+type bundleInfo struct {
+	Name         string                `json:"name,omitempty"`
+	ID           common.Namespace      `json:"id"`
+	ManifestHash hash.Hash             `json:"manifest_hash"`
+	Detached     bool                  `json:"detached,omitempty"`
+	Components   []*componentInfo      `json:"components"`
+	Signers      []signature.PublicKey `json:"signers,omitempty"`
+}
+
+// componentInfo is the information about a runtime bundle component.
+type componentInfo struct {
+	ID           string               `json:"id"`
+	Version      string               `json:"version"`
+	TEE          string               `json:"tee"`
+	Disabled     bool                 `json:"disabled,omitempty"`
+	Identities   []identityInfo       `json:"identities,omitempty"`
+	TDXResources *bundle.TDXResources `json:"tdx_resources,omitempty"`
+	Files        map[string]hash.Hash `json:"files"`
+}
+
+// identityInfo is the information about an expected enclave identity.
+type identityInfo struct {
+	MrEnclave string `json:"mr_enclave"`
+	MrSigner  string `json:"mr_signer"`
+}
+
+// inspectFile opens the given bundle file and returns information about it.
+func inspectFile(fn string) (*bundleInfo, error) {
+	bnd, err := bundle.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer bnd.Close()
+
+	return inspectBundle(bnd)
+}
+
+// inspectBundle returns information about the given bundle.
+func inspectBundle(bnd *bundle.Bundle) (*bundleInfo, error) {
+	manifest := bnd.Manifest
+	info := &bundleInfo{
+		Name:         manifest.Name,
+		ID:           manifest.ID,
+		ManifestHash: manifest.Hash(),
+		Detached:     manifest.IsDetached(),
+	}
+
+	for _, comp := range manifest.Components {
+		ci := &componentInfo{
+			ID:       comp.ID().String(),
+			Version:  comp.Version.String(),
+			TEE:      comp.TEEKind().String(),
+			Disabled: comp.Disabled,
+			Files:    make(map[string]hash.Hash),
+		}
+		if comp.ID().IsRONL() && manifest.Version.ToU64() > 0 {
+			// Legacy manifests define the runtime version at the top level.
+			ci.Version = manifest.Version.String()
+		}
+		if comp.TDX != nil {
+			ci.TDXResources = &comp.TDX.Resources
+		}
+		if comp.TEEKind() != component.TEEKindNone {
+			ids, err := bnd.EnclaveIdentities(comp.ID())
+			if err != nil {
+				return nil, fmt.Errorf("failed to determine enclave identities of '%s': %w", comp.ID(), err)
+			}
+			for _, id := range ids {
+				ci.Identities = append(ci.Identities, identityInfo{
+					MrEnclave: id.MrEnclave.String(),
+					MrSigner:  id.MrSigner.String(),
+				})
+			}
+		}
+		for _, fn := range comp.Files() {
+			ci.Files[fn] = manifest.Digests[fn]
+		}
+
+		info.Components = append(info.Components, ci)
+	}
+
+	sigs, err := bnd.Signatures()
+	if err != nil {
+		return nil, err
+	}
+	for _, sig := range sigs {
+		info.Signers = append(info.Signers, sig.PublicKey)
+	}
+
+	return info, nil
+}
@@ -15,6 +15,7 @@ import (
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
 )
@@ -40,6 +41,10 @@ const (
 	CmdRuntimeStats = "runtime-stats"
 	// CmdAddBundle is the add-bundle sub-command.
 	CmdAddBundle = "add-bundle"
+	// CmdRuntimeRestart is the runtime-restart sub-command.
+	CmdRuntimeRestart = "runtime-restart"
+	// CmdRuntimeBundles is the runtime-bundles sub-command.
+	CmdRuntimeBundles = "runtime-bundles"
 )
 
 var (
@@ -106,6 +111,20 @@ var (
 		Run:   doAddBundle,
 	}
 
+	controlRuntimeRestartCmd = &cobra.Command{
+		Use:   CmdRuntimeRestart + " <runtime-id> [<component-id>]",
+		Short: "restart a hosted runtime component, clearing its crash looping state",
+		Args:  cobra.RangeArgs(1, 2),
+		Run:   doRuntimeRestart,
+	}
+
+	controlRuntimeBundlesCmd = &cobra.Command{
+		Use:   CmdRuntimeBundles + " <runtime-id>",
+		Short: "list installed runtime bundles with their disk usage",
+		Args:  cobra.ExactArgs(1),
+		Run:   doRuntimeBundles,
+	}
+
 	logger = logging.GetLogger("cmd/control")
 )
 
@@ -343,6 +362,56 @@ func doAddBundle(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doRuntimeRestart(cmd *cobra.Command, args []string) {
+	req := control.RestartRuntimeRequest{
+		RuntimeID:   parseRuntimeID(args[0]),
+		ComponentID: component.ID_RONL,
+	}
+	if len(args) > 1 {
+		if err := req.ComponentID.UnmarshalText([]byte(args[1])); err != nil {
+			logger.Error("malformed component ID",
+				"err", err,
+				"arg", args[1],
+			)
+			os.Exit(1)
+		}
+	}
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.RestartRuntime(context.Background(), &req); err != nil {
+		logger.Error("failed to restart runtime",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doRuntimeBundles(cmd *cobra.Command, args []string) {
+	runtimeID := parseRuntimeID(args[0])
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	bundles, err := client.GetRuntimeBundles(context.Background(), runtimeID)
+	if err != nil {
+		logger.Error("failed to query runtime bundles",
+			"err", err,
+		)
+		os.Exit(128)
+	}
+
+	prettyBundles, err := cmdCommon.PrettyJSONMarshal(bundles)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of runtime bundles",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyBundles))
+}
+
 // Register registers the client sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	controlCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
@@ -358,5 +427,9 @@ func Register(parentCmd *cobra.Command) {
 	controlCmd.AddCommand(controlStatusCmd)
 	controlCmd.AddCommand(controlRuntimeStatsCmd)
 	controlCmd.AddCommand(controlAddBundleCmd)
+	controlCmd.AddCommand(controlRuntimeRestartCmd)
+	controlCmd.AddCommand(controlRuntimeBundlesCmd)
+	registerTxPoolCmd(controlCmd)
+	registerRuntimeLogsCmd(controlCmd)
 	parentCmd.AddCommand(controlCmd)
 }
@@ -0,0 +1,130 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+)
+
+// CmdRuntimeLogs is the runtime-logs sub-command.
+const CmdRuntimeLogs = "runtime-logs"
+
+var (
+	runtimeLogsFollow   bool
+	runtimeLogsSince    string
+	runtimeLogsUntil    string
+	runtimeLogsLevel    logging.Level
+	runtimeLogsContains string
+	runtimeLogsJSON     bool
+
+	controlRuntimeLogsCmd = &cobra.Command{
+		Use:   CmdRuntimeLogs + " <runtime-id> <component-id>",
+		Short: "show logs of a hosted runtime component",
+		Args:  cobra.ExactArgs(2),
+		Run:   doRuntimeLogs,
+	}
+)
+
+// parseTimeOffset parses a time offset given either as an RFC 3339 timestamp or as a duration
+// relative to the current time.
+func parseTimeOffset(arg string) (time.Time, error) {
+	if arg == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(arg); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339Nano, arg)
+}
+
+func doRuntimeLogs(cmd *cobra.Command, args []string) {
+	query := control.RuntimeLogsQuery{
+		RuntimeID: parseRuntimeID(args[0]),
+		Follow:    runtimeLogsFollow,
+		Level:     runtimeLogsLevel,
+		Contains:  runtimeLogsContains,
+	}
+	if err := query.ComponentID.UnmarshalText([]byte(args[1])); err != nil {
+		logger.Error("malformed component ID",
+			"err", err,
+			"arg", args[1],
+		)
+		os.Exit(1)
+	}
+
+	var err error
+	if query.Since, err = parseTimeOffset(runtimeLogsSince); err != nil {
+		logger.Error("malformed since offset",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if query.Until, err = parseTimeOffset(runtimeLogsUntil); err != nil {
+		logger.Error("malformed until offset",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	ch, sub, err := client.WatchRuntimeLogs(context.Background(), &query)
+	if err != nil {
+		logger.Error("failed to watch runtime logs",
+			"err", err,
+		)
+		os.Exit(128)
+	}
+	defer sub.Close()
+
+	for entry := range ch {
+		if runtimeLogsJSON {
+			raw, _ := json.Marshal(entry)
+			fmt.Println(string(raw))
+			continue
+		}
+		fmt.Println(formatLogEntry(entry))
+	}
+}
+
+// formatLogEntry formats the given log entry in a human readable form.
+func formatLogEntry(entry *control.RuntimeLogEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-5s", entry.Timestamp.Format(time.RFC3339Nano), strings.ToUpper(entry.Level))
+	if entry.Module != "" {
+		fmt.Fprintf(&b, " [%s]", entry.Module)
+	}
+	fmt.Fprintf(&b, " %s", entry.Message)
+
+	keys := make([]string, 0, len(entry.Fields))
+	for k := range entry.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%s", k, entry.Fields[k])
+	}
+
+	return b.String()
+}
+
+func registerRuntimeLogsCmd(parentCmd *cobra.Command) {
+	controlRuntimeLogsCmd.Flags().BoolVarP(&runtimeLogsFollow, "follow", "f", false, "follow the log for new entries")
+	controlRuntimeLogsCmd.Flags().StringVar(&runtimeLogsSince, "since", "", "only show entries after the given RFC 3339 timestamp or duration ago (e.g. 10m)")
+	controlRuntimeLogsCmd.Flags().StringVar(&runtimeLogsUntil, "until", "", "only show entries before the given RFC 3339 timestamp or duration ago (e.g. 10m)")
+	controlRuntimeLogsCmd.Flags().Var(&runtimeLogsLevel, "level", "minimum log level of shown entries")
+	controlRuntimeLogsCmd.Flags().StringVar(&runtimeLogsContains, "grep", "", "only show entries containing the given substring")
+	controlRuntimeLogsCmd.Flags().BoolVar(&runtimeLogsJSON, "json", false, "output entries as JSON")
+
+	parentCmd.AddCommand(controlRuntimeLogsCmd)
+}
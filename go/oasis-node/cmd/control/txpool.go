@@ -0,0 +1,113 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+)
+
+const (
+	// CmdTxPool is the txpool sub-command.
+	CmdTxPool = "txpool"
+	// CmdTxPoolStatus is the txpool status sub-command.
+	CmdTxPoolStatus = "status"
+	// CmdTxPoolEvict is the txpool evict sub-command.
+	CmdTxPoolEvict = "evict"
+)
+
+var (
+	controlTxPoolCmd = &cobra.Command{
+		Use:   CmdTxPool,
+		Short: "runtime transaction pool inspection utilities",
+	}
+
+	controlTxPoolStatusCmd = &cobra.Command{
+		Use:   CmdTxPoolStatus + " <runtime-id>",
+		Short: "show runtime transaction pool status",
+		Args:  cobra.ExactArgs(1),
+		Run:   doTxPoolStatus,
+	}
+
+	controlTxPoolEvictCmd = &cobra.Command{
+		Use:   CmdTxPoolEvict + " <runtime-id> <tx-hash>",
+		Short: "evict a transaction from the runtime transaction pool",
+		Args:  cobra.ExactArgs(2),
+		Run:   doTxPoolEvict,
+	}
+)
+
+func parseRuntimeID(arg string) common.Namespace {
+	var runtimeID common.Namespace
+	if err := runtimeID.UnmarshalText([]byte(arg)); err != nil {
+		logger.Error("malformed runtime ID",
+			"err", err,
+			"arg", arg,
+		)
+		os.Exit(1)
+	}
+	return runtimeID
+}
+
+func doTxPoolStatus(cmd *cobra.Command, args []string) {
+	runtimeID := parseRuntimeID(args[0])
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	status, err := client.GetTxPoolStatus(context.Background(), runtimeID)
+	if err != nil {
+		logger.Error("failed to query transaction pool status",
+			"err", err,
+		)
+		os.Exit(128)
+	}
+
+	prettyStatus, err := cmdCommon.PrettyJSONMarshal(status)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of transaction pool status",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyStatus))
+}
+
+func doTxPoolEvict(cmd *cobra.Command, args []string) {
+	runtimeID := parseRuntimeID(args[0])
+
+	var txHash hash.Hash
+	if err := txHash.UnmarshalHex(args[1]); err != nil {
+		logger.Error("malformed transaction hash",
+			"err", err,
+			"arg", args[1],
+		)
+		os.Exit(1)
+	}
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	err := client.EvictTransaction(context.Background(), &control.EvictTransactionRequest{
+		RuntimeID: runtimeID,
+		Hash:      txHash,
+	})
+	if err != nil {
+		logger.Error("failed to evict transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func registerTxPoolCmd(parentCmd *cobra.Command) {
+	controlTxPoolCmd.AddCommand(controlTxPoolStatusCmd)
+	controlTxPoolCmd.AddCommand(controlTxPoolEvictCmd)
+	parentCmd.AddCommand(controlTxPoolCmd)
+}
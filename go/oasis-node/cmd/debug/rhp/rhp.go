@@ -0,0 +1,141 @@
+// Package rhp implements the Runtime Host Protocol debug sub-commands.
+package rhp
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox/process"
+)
+
+const cfgReplayPreserveTiming = "replay.preserve_timing"
+
+var (
+	rhpCmd = &cobra.Command{
+		Use:   "rhp",
+		Short: "debug the runtime host protocol",
+	}
+
+	replayCmd = &cobra.Command{
+		Use:   "replay <runtime-binary> <trace-file>...",
+		Short: "replay a recorded runtime host protocol session against a runtime binary",
+		Long: "Replays the requests from the given trace files against an unsandboxed runtime binary " +
+			"and reports any responses that differ from the recorded ones. Requests sent by the " +
+			"runtime are answered with the recorded responses. All trace files must belong to the " +
+			"same session and must be given in order.",
+		Args: cobra.MinimumNArgs(2),
+		Run:  doReplay,
+	}
+
+	replayFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	logger = logging.GetLogger("cmd/debug/rhp")
+)
+
+func doReplay(_ *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	result, err := replay(args[0], args[1:], viper.GetBool(cfgReplayPreserveTiming))
+	if err != nil {
+		logger.Error("failed to replay trace",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	prettyResult, err := cmdCommon.PrettyJSONMarshal(result)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of replay result",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyResult))
+
+	if len(result.Mismatches) > 0 {
+		os.Exit(1)
+	}
+}
+
+func replay(binary string, traceFiles []string, preserveTiming bool) (*protocol.ReplayResult, error) {
+	records, err := protocol.ReadTrace(traceFiles...)
+	if err != nil {
+		return nil, err
+	}
+	replayer, err := protocol.NewReplayer(records)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeDir, err := os.MkdirTemp("", "oasis-rhp-replay")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(runtimeDir)
+
+	connector, err := sandbox.NewUnixSocketConnector(logger, runtimeDir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer connector.Close()
+
+	cfg := process.Config{
+		Path: binary,
+	}
+	if err = connector.Configure(&host.Config{}, &cfg); err != nil {
+		return nil, err
+	}
+
+	p, err := process.NewNaked(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to spawn runtime: %w", err)
+	}
+	defer p.Kill()
+
+	conn, err := connector.Connect(p)
+	if err != nil {
+		return nil, err
+	}
+
+	pc, err := protocol.NewConnection(logger, replayer.RuntimeID(), replayer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection: %w", err)
+	}
+	defer pc.Close()
+
+	ctx := context.Background()
+	if _, err = pc.InitHost(ctx, conn, replayer.HostInfo()); err != nil {
+		return nil, fmt.Errorf("failed to initialize connection: %w", err)
+	}
+
+	logger.Info("replaying trace",
+		"runtime_id", replayer.RuntimeID(),
+		"records", len(records),
+	)
+
+	return replayer.Replay(ctx, pc, preserveTiming)
+}
+
+// Register registers the rhp sub-command and all of its children.
+func Register(parentCmd *cobra.Command) {
+	replayCmd.Flags().AddFlagSet(replayFlags)
+	rhpCmd.AddCommand(replayCmd)
+	parentCmd.AddCommand(rhpCmd)
+}
+
+func init() {
+	replayFlags.Bool(cfgReplayPreserveTiming, false, "send requests concurrently with the recorded timing")
+	_ = viper.BindPFlags(replayFlags)
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
@@ -37,6 +38,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/provisioner"
 	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	tpConfig "github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	"github.com/oasisprotocol/oasis-core/go/sentry"
 	sentryAPI "github.com/oasisprotocol/oasis-core/go/sentry/api"
@@ -221,7 +223,11 @@ func (n *Node) initRuntimeWorkers(genesisDoc *genesisAPI.Document) error {
 
 	// Initialize the common worker.
 	commonCfg := workerCommon.Config{
-		TxPool: config.GlobalConfig.Runtime.TxPool,
+		TxPool:        config.GlobalConfig.Runtime.TxPool,
+		RuntimeTxPool: make(map[common.Namespace]tpConfig.Config),
+	}
+	for _, rt := range config.GlobalConfig.Runtime.Runtimes {
+		commonCfg.RuntimeTxPool[rt.ID] = config.GlobalConfig.Runtime.GetTxPoolConfig(rt.ID)
 	}
 	n.CommonWorker, err = workerCommon.New(
 		commonCfg,
@@ -2,10 +2,12 @@ package node
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
@@ -13,6 +15,10 @@ import (
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	p2p "github.com/oasisprotocol/oasis-core/go/p2p/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	"github.com/oasisprotocol/oasis-core/go/runtime/log"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 	keymanagerWorker "github.com/oasisprotocol/oasis-core/go/worker/keymanager/api"
@@ -177,6 +183,110 @@ func (n *Node) AddBundle(_ context.Context, path string) error {
 	return n.RuntimeRegistry.GetBundleManager().Add(path)
 }
 
+// GetTxPoolStatus implements control.NodeController.
+func (n *Node) GetTxPoolStatus(_ context.Context, runtimeID common.Namespace) (*control.TxPoolStatus, error) {
+	txPool, err := n.getTxPool(runtimeID)
+	if err != nil {
+		return nil, err
+	}
+	return newTxPoolStatus(txPool.GetStatus()), nil
+}
+
+// EvictTransaction implements control.NodeController.
+func (n *Node) EvictTransaction(_ context.Context, req *control.EvictTransactionRequest) error {
+	txPool, err := n.getTxPool(req.RuntimeID)
+	if err != nil {
+		return err
+	}
+	switch err = txPool.EvictTx(req.Hash); {
+	case err == nil:
+		return nil
+	case errors.Is(err, txpool.ErrEvictionDisabled):
+		return control.ErrEvictionDisabled
+	case errors.Is(err, txpool.ErrNoSuchTransaction):
+		return control.ErrNoSuchTransaction
+	default:
+		return err
+	}
+}
+
+// RestartRuntime implements control.NodeController.
+func (n *Node) RestartRuntime(ctx context.Context, req *control.RestartRuntimeRequest) error {
+	rtNode := n.CommonWorker.GetRuntime(req.RuntimeID)
+	if rtNode == nil {
+		return control.ErrNoSuchRuntime
+	}
+	comp, ok := rtNode.GetHostedRuntime().Component(req.ComponentID)
+	if !ok {
+		return control.ErrNoSuchComponent
+	}
+	return comp.Abort(ctx, true)
+}
+
+// GetRuntimeBundles implements control.NodeController.
+func (n *Node) GetRuntimeBundles(_ context.Context, runtimeID common.Namespace) (*control.RuntimeBundles, error) {
+	if _, err := n.RuntimeRegistry.GetRuntime(runtimeID); err != nil {
+		return nil, control.ErrNoSuchRuntime
+	}
+	return newRuntimeBundles(n.RuntimeRegistry.GetBundleManager().Bundles(runtimeID)), nil
+}
+
+// WatchRuntimeLogs implements control.NodeController.
+func (n *Node) WatchRuntimeLogs(ctx context.Context, query *control.RuntimeLogsQuery) (<-chan *control.RuntimeLogEntry, pubsub.ClosableSubscription, error) {
+	if _, err := n.RuntimeRegistry.GetRuntime(query.RuntimeID); err != nil {
+		return nil, nil, control.ErrNoSuchRuntime
+	}
+	rtLog, ok := n.RuntimeRegistry.GetLogManager().Lookup(query.RuntimeID, query.ComponentID)
+	if !ok {
+		return nil, nil, control.ErrNoSuchComponent
+	}
+
+	opts := log.WatchOptions{
+		Follow:   query.Follow,
+		Since:    query.Since,
+		Until:    query.Until,
+		Level:    query.Level,
+		Contains: query.Contains,
+	}
+
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+	entryCh := make(chan *log.Entry)
+	go func() {
+		defer close(entryCh)
+
+		if err := rtLog.WatchEntries(ctx, entryCh, opts); err != nil && ctx.Err() == nil {
+			n.logger.Warn("failed to watch runtime logs",
+				"err", err,
+				"runtime_id", query.RuntimeID,
+				"component_id", query.ComponentID,
+			)
+		}
+	}()
+
+	ch := make(chan *control.RuntimeLogEntry)
+	go func() {
+		defer close(ch)
+
+		for entry := range entryCh {
+			select {
+			case ch <- (*control.RuntimeLogEntry)(entry):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
+func (n *Node) getTxPool(runtimeID common.Namespace) (txpool.TransactionPool, error) {
+	rtNode := n.CommonWorker.GetRuntime(runtimeID)
+	if rtNode == nil || rtNode.TxPool == nil {
+		return nil, control.ErrNoSuchRuntime
+	}
+	return rtNode.TxPool, nil
+}
+
 func (n *Node) getIdentityStatus() control.IdentityStatus {
 	return control.IdentityStatus{
 		Node:      n.Identity.NodeSigner.Public(),
@@ -328,14 +438,24 @@ func (n *Node) getRuntimeStatus(ctx context.Context) (map[common.Namespace]contr
 		status.Provisioner = n.Provisioner.Name()
 
 		// Fetch the status of all components associated with the runtime.
+		rtNode := n.CommonWorker.GetRuntime(rt.ID())
 		for _, comp := range n.RuntimeRegistry.GetBundleRegistry().Components(rt.ID()) {
-			status.Components = append(status.Components, control.ComponentStatus{
+			compStatus := control.ComponentStatus{
 				Kind:     comp.Kind,
 				Name:     comp.Name,
 				Version:  comp.Version,
 				Detached: comp.Detached,
 				Disabled: comp.Disabled,
-			})
+			}
+			if rtNode != nil {
+				compStatus.Status = newHostedComponentStatus(rtNode.GetHostedRuntimeComponentStatus(comp.ID(), comp.Version))
+			}
+			status.Components = append(status.Components, compStatus)
+		}
+
+		// Fetch the progress of pending bundle downloads.
+		for _, ds := range n.RuntimeRegistry.GetBundleManager().DownloadStatus(rt.ID()) {
+			status.Downloads = append(status.Downloads, control.BundleDownloadStatus(ds))
 		}
 
 		// Store the runtime status.
@@ -358,3 +478,55 @@ func (n *Node) getPendingUpgrades() ([]*upgrade.PendingUpgrade, error) {
 func (n *Node) getP2PStatus() *p2p.Status {
 	return n.P2P.GetStatus()
 }
+
+func newTxPoolStatus(status *txpool.Status) *control.TxPoolStatus {
+	rsp := control.TxPoolStatus{
+		CheckQueueSize: status.CheckQueueSize,
+		MainQueueSize:  status.MainQueueSize,
+		RimQueueSize:   status.RimQueueSize,
+		ProposedSize:   status.ProposedSize,
+	}
+	for _, s := range status.Senders {
+		rsp.Senders = append(rsp.Senders, (*control.TxPoolSenderStatus)(s))
+	}
+	for _, tx := range status.Transactions {
+		rsp.Transactions = append(rsp.Transactions, (*control.TxPoolTransactionStatus)(tx))
+	}
+	return &rsp
+}
+
+func newRuntimeBundles(bundles *bundle.RuntimeBundles) *control.RuntimeBundles {
+	rsp := control.RuntimeBundles{
+		ActiveVersion: bundles.ActiveVersion,
+		Size:          bundles.Size,
+	}
+	for _, b := range bundles.Bundles {
+		ib := control.InstalledBundle{
+			ManifestHash: b.ManifestHash,
+			Name:         b.Name,
+			Labels:       b.Labels,
+			Detached:     b.Detached,
+			Size:         b.Size,
+			InUse:        b.InUse,
+		}
+		for _, comp := range b.Components {
+			ib.Components = append(ib.Components, (*control.InstalledComponent)(comp))
+		}
+		rsp.Bundles = append(rsp.Bundles, &ib)
+	}
+	return &rsp
+}
+
+func newHostedComponentStatus(status *host.Status) *control.HostedComponentStatus {
+	if status == nil {
+		return nil
+	}
+	return &control.HostedComponentStatus{
+		State:      string(status.State),
+		Crashes:    status.Crashes,
+		StartedAt:  status.StartedAt,
+		Resources:  (*control.HostedComponentResources)(status.Resources),
+		AttestedAt: status.AttestedAt,
+		RAK:        status.RAK,
+	}
+}
@@ -3,6 +3,8 @@ package node
 import (
 	"context"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
@@ -86,3 +88,28 @@ func (n *SeedNode) GetStatus(context.Context) (*control.Status, error) {
 func (n *SeedNode) AddBundle(context.Context, string) error {
 	return control.ErrNotImplemented
 }
+
+// GetTxPoolStatus implements control.NodeController.
+func (n *SeedNode) GetTxPoolStatus(context.Context, common.Namespace) (*control.TxPoolStatus, error) {
+	return nil, control.ErrNotImplemented
+}
+
+// EvictTransaction implements control.NodeController.
+func (n *SeedNode) EvictTransaction(context.Context, *control.EvictTransactionRequest) error {
+	return control.ErrNotImplemented
+}
+
+// RestartRuntime implements control.NodeController.
+func (n *SeedNode) RestartRuntime(context.Context, *control.RestartRuntimeRequest) error {
+	return control.ErrNotImplemented
+}
+
+// GetRuntimeBundles implements control.NodeController.
+func (n *SeedNode) GetRuntimeBundles(context.Context, common.Namespace) (*control.RuntimeBundles, error) {
+	return nil, control.ErrNotImplemented
+}
+
+// WatchRuntimeLogs implements control.NodeController.
+func (n *SeedNode) WatchRuntimeLogs(context.Context, *control.RuntimeLogsQuery) (<-chan *control.RuntimeLogEntry, pubsub.ClosableSubscription, error) {
+	return nil, nil, control.ErrNotImplemented
+}
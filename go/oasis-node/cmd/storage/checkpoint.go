@@ -0,0 +1,295 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	runtimeConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+	"github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	workerStorage "github.com/oasisprotocol/oasis-core/go/worker/storage"
+)
+
+func newCheckpointCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "checkpoint",
+		Short: "runtime storage checkpoint utilities",
+	}
+
+	cmd.AddCommand(newCheckpointExportCmd())
+	cmd.AddCommand(newCheckpointImportCmd())
+	cmd.AddCommand(newCheckpointVerifyCmd())
+
+	return cmd
+}
+
+func ensureNodeNotRunning(_ *cobra.Command, _ []string) error {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	running, err := cmdCommon.IsNodeRunning()
+	if err != nil {
+		return fmt.Errorf("failed to ensure the node is not running: %w", err)
+	}
+	if running {
+		return fmt.Errorf("node is running")
+	}
+
+	return nil
+}
+
+func openRuntimeLocalBackend(dataDir string, runtimeID common.Namespace) (api.LocalBackend, error) {
+	rtDir := runtimeConfig.GetRuntimeStateDir(dataDir, runtimeID)
+	backend, err := workerStorage.NewLocalBackend(rtDir, runtimeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage backend (runtimeID: %s): %w", runtimeID, err)
+	}
+	return backend, nil
+}
+
+func newCheckpointExportCmd() *cobra.Command {
+	var (
+		round  uint64
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:     "export <runtime-id>",
+		Args:    cobra.ExactArgs(1),
+		Short:   "export runtime storage checkpoints into an archive file",
+		PreRunE: ensureNodeNotRunning,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runtimes, err := parseRuntimes(args)
+			if err != nil {
+				return err
+			}
+			if output == "" {
+				return fmt.Errorf("output file must be specified")
+			}
+
+			backend, err := openRuntimeLocalBackend(cmdCommon.DataDir(), runtimes[0])
+			if err != nil {
+				return err
+			}
+			defer backend.Cleanup()
+
+			cps, err := selectCheckpoints(cmd.Context(), backend.Checkpointer(), runtimes[0], round)
+			if err != nil {
+				return err
+			}
+
+			f, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failed to create archive file: %w", err)
+			}
+			defer f.Close()
+
+			if err = checkpoint.ExportArchive(cmd.Context(), backend.Checkpointer(), cps, f); err != nil {
+				_ = os.Remove(output)
+				return fmt.Errorf("failed to export checkpoints: %w", err)
+			}
+			if err = f.Sync(); err != nil {
+				return fmt.Errorf("failed to sync archive file: %w", err)
+			}
+
+			logger.Info("exported checkpoints",
+				"runtime_id", runtimes[0],
+				"round", cps[0].Root.Version,
+				"num_checkpoints", len(cps),
+				"output", output,
+			)
+			if pretty {
+				fmt.Printf("Exported %d checkpoint(s) for round %d to %s.\n", len(cps), cps[0].Root.Version, output)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint64Var(&round, "round", 0, "round of the checkpoints to export (default: latest)")
+	cmd.Flags().StringVar(&output, "output", "", "path to the output archive file")
+
+	return cmd
+}
+
+// selectCheckpoints returns the full checkpoints of all roots for the given round. In case round
+// is zero, the latest round with checkpoints is used.
+//
+// In case checkpoints of multiple versions exist for the same root, only the checkpoint of the
+// most preferred version is returned.
+func selectCheckpoints(ctx context.Context, provider checkpoint.ChunkProvider, runtimeID common.Namespace, round uint64) ([]*checkpoint.Metadata, error) {
+	var cps []*checkpoint.Metadata
+	seen := make(map[node.Root]struct{})
+	for _, version := range checkpoint.SupportedVersions {
+		request := &checkpoint.GetCheckpointsRequest{
+			Version:   version,
+			Namespace: runtimeID,
+		}
+		if round != 0 {
+			request.RootVersion = &round
+		}
+		vcps, err := provider.GetCheckpoints(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get checkpoints: %w", err)
+		}
+		for _, cp := range vcps {
+			if cp.IsDelta() {
+				continue
+			}
+			if _, ok := seen[cp.Root]; ok {
+				continue
+			}
+			seen[cp.Root] = struct{}{}
+			cps = append(cps, cp)
+		}
+	}
+
+	if round == 0 {
+		for _, cp := range cps {
+			round = max(round, cp.Root.Version)
+		}
+	}
+
+	var selected []*checkpoint.Metadata
+	for _, cp := range cps {
+		if cp.Root.Version == round {
+			selected = append(selected, cp)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no checkpoints found")
+	}
+	return selected, nil
+}
+
+func newCheckpointImportCmd() *cobra.Command {
+	var (
+		round     uint64
+		stateRoot string
+		ioRoot    string
+	)
+
+	cmd := &cobra.Command{
+		Use:     "import <runtime-id> <archive>",
+		Args:    cobra.ExactArgs(2),
+		Short:   "restore runtime storage from a checkpoint archive file",
+		PreRunE: ensureNodeNotRunning,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runtimes, err := parseRuntimes(args[:1])
+			if err != nil {
+				return err
+			}
+
+			roots, err := parseExpectedRoots(runtimes[0], round, stateRoot, ioRoot)
+			if err != nil {
+				return err
+			}
+
+			f, err := os.Open(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to open archive file: %w", err)
+			}
+			defer f.Close()
+
+			backend, err := openRuntimeLocalBackend(cmdCommon.DataDir(), runtimes[0])
+			if err != nil {
+				return err
+			}
+			defer backend.Cleanup()
+
+			manifest, err := checkpoint.ImportArchive(cmd.Context(), backend.NodeDB(), roots, f)
+			if err != nil {
+				return fmt.Errorf("failed to import checkpoints: %w", err)
+			}
+
+			logger.Info("imported checkpoints",
+				"runtime_id", runtimes[0],
+				"roots", manifest.Roots(),
+			)
+			if pretty {
+				fmt.Printf("Imported %d checkpoint(s) for round %d.\n", len(manifest.Checkpoints), manifest.Checkpoints[0].Root.Version)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint64Var(&round, "round", 0, "round of the checkpoints to import")
+	cmd.Flags().StringVar(&stateRoot, "state-root", "", "expected state root hash of the round")
+	cmd.Flags().StringVar(&ioRoot, "io-root", "", "expected I/O root hash of the round, if the archive contains it")
+
+	return cmd
+}
+
+// parseExpectedRoots returns the roots the imported archive must contain, as obtained by the
+// operator from a trusted source (e.g., the runtime block header of the given round).
+func parseExpectedRoots(runtimeID common.Namespace, round uint64, stateRoot, ioRoot string) ([]node.Root, error) {
+	if round == 0 {
+		return nil, fmt.Errorf("round must be specified")
+	}
+	if stateRoot == "" {
+		return nil, fmt.Errorf("expected state root must be specified")
+	}
+
+	var roots []node.Root
+	for _, r := range []struct {
+		rootType node.RootType
+		hash     string
+	}{
+		{node.RootTypeState, stateRoot},
+		{node.RootTypeIO, ioRoot},
+	} {
+		if r.hash == "" {
+			continue
+		}
+		root := node.Root{
+			Namespace: runtimeID,
+			Version:   round,
+			Type:      r.rootType,
+		}
+		if err := root.Hash.UnmarshalHex(r.hash); err != nil {
+			return nil, fmt.Errorf("malformed %s hash: %w", r.rootType, err)
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+func newCheckpointVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <archive>",
+		Args:  cobra.ExactArgs(1),
+		Short: "verify integrity of a checkpoint archive file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open archive file: %w", err)
+			}
+			defer f.Close()
+
+			manifest, err := checkpoint.VerifyArchive(cmd.Context(), f)
+			if err != nil {
+				return fmt.Errorf("archive verification failed: %w", err)
+			}
+
+			fmt.Println("Archive is valid.")
+			fmt.Println("Checkpoints:")
+			for _, cp := range manifest.Checkpoints {
+				fmt.Println(" ", cp.Root)
+				fmt.Println("    Hash: ", cp.EncodedHash())
+				fmt.Println("    Chunks: ", len(cp.Chunks))
+				if cp.IsDelta() {
+					fmt.Println("    Base root: ", cp.BaseRoot)
+				}
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
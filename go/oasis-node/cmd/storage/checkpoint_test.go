@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+type testChunkProvider struct {
+	checkpoint.ChunkProvider
+
+	cps map[uint16][]*checkpoint.Metadata
+}
+
+func (p *testChunkProvider) GetCheckpoints(_ context.Context, request *checkpoint.GetCheckpointsRequest) ([]*checkpoint.Metadata, error) {
+	var cps []*checkpoint.Metadata
+	for _, cp := range p.cps[request.Version] {
+		if request.RootVersion != nil && cp.Root.Version != *request.RootVersion {
+			continue
+		}
+		cps = append(cps, cp)
+	}
+	return cps, nil
+}
+
+func TestSelectCheckpoints(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	ns := common.NewTestNamespaceFromSeed([]byte("storage checkpoint test ns"), 0)
+
+	newCheckpoint := func(version uint16, round uint64, rootType node.RootType) *checkpoint.Metadata {
+		return &checkpoint.Metadata{
+			Version: version,
+			Root: node.Root{
+				Namespace: ns,
+				Version:   round,
+				Type:      rootType,
+				Hash:      hash.NewFromBytes([]byte{byte(round), byte(rootType)}),
+			},
+		}
+	}
+
+	zstdState := newCheckpoint(checkpoint.VersionZstd, 10, node.RootTypeState)
+	snappyState := newCheckpoint(checkpoint.VersionSnappy, 10, node.RootTypeState)
+	snappyIO := newCheckpoint(checkpoint.VersionSnappy, 10, node.RootTypeIO)
+	older := newCheckpoint(checkpoint.VersionSnappy, 5, node.RootTypeState)
+	deltaIO := newCheckpoint(checkpoint.VersionZstd, 10, node.RootTypeIO)
+	deltaIO.BaseRoot = &snappyIO.Root
+	provider := &testChunkProvider{
+		cps: map[uint16][]*checkpoint.Metadata{
+			checkpoint.VersionZstd:   {zstdState, deltaIO},
+			checkpoint.VersionSnappy: {older, snappyState, snappyIO},
+		},
+	}
+
+	// Only a single full checkpoint of the most preferred version should be selected for each root.
+	cps, err := selectCheckpoints(ctx, provider, ns, 0)
+	require.NoError(err, "selectCheckpoints")
+	require.Equal([]*checkpoint.Metadata{zstdState, snappyIO}, cps)
+
+	cps, err = selectCheckpoints(ctx, provider, ns, 5)
+	require.NoError(err, "selectCheckpoints")
+	require.Equal([]*checkpoint.Metadata{older}, cps)
+
+	_, err = selectCheckpoints(ctx, provider, ns, 7)
+	require.Error(err, "selectCheckpoints should fail without checkpoints")
+}
+
+func TestParseExpectedRoots(t *testing.T) {
+	require := require.New(t)
+	ns := common.NewTestNamespaceFromSeed([]byte("storage checkpoint test ns"), 0)
+	stateRoot := hash.NewFromBytes([]byte("state"))
+	ioRoot := hash.NewFromBytes([]byte("io"))
+
+	_, err := parseExpectedRoots(ns, 0, stateRoot.Hex(), "")
+	require.ErrorContains(err, "round must be specified")
+
+	_, err = parseExpectedRoots(ns, 10, "", ioRoot.Hex())
+	require.ErrorContains(err, "expected state root must be specified")
+
+	_, err = parseExpectedRoots(ns, 10, "invalid", "")
+	require.ErrorContains(err, "malformed state-root hash")
+
+	roots, err := parseExpectedRoots(ns, 10, stateRoot.Hex(), ioRoot.Hex())
+	require.NoError(err, "parseExpectedRoots")
+	require.Equal([]node.Root{
+		{Namespace: ns, Version: 10, Type: node.RootTypeState, Hash: stateRoot},
+		{Namespace: ns, Version: 10, Type: node.RootTypeIO, Hash: ioRoot},
+	}, roots)
+}
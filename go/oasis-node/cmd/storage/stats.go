@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+func newStatsCmd() *cobra.Command {
+	var (
+		round        uint64
+		rootType     string
+		prefixLength int
+		top          int
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:     "stats <runtime-id>",
+		Args:    cobra.ExactArgs(1),
+		Short:   "report runtime state tree statistics and per-prefix key space usage",
+		PreRunE: ensureNodeNotRunning,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runtimes, err := parseRuntimes(args)
+			if err != nil {
+				return err
+			}
+
+			var typ node.RootType
+			switch rootType {
+			case "state":
+				typ = node.RootTypeState
+			case "io":
+				typ = node.RootTypeIO
+			default:
+				return fmt.Errorf("unsupported root type: %s (supported: state, io)", rootType)
+			}
+
+			switch outputFormat {
+			case "text", "json":
+			default:
+				return fmt.Errorf("unsupported output format: %s (supported: text, json)", outputFormat)
+			}
+
+			ndb, err := openRuntimeStateDB(cmdCommon.DataDir(), runtimes[0])
+			if err != nil {
+				return err
+			}
+			defer ndb.Close()
+
+			if round == 0 {
+				var ok bool
+				if round, ok = ndb.GetLatestVersion(); !ok {
+					return fmt.Errorf("state database is empty")
+				}
+			}
+
+			roots, err := ndb.GetRootsForVersion(round)
+			if err != nil {
+				return fmt.Errorf("failed to get roots for round %d: %w", round, err)
+			}
+			var root *node.Root
+			for i := range roots {
+				if roots[i].Type == typ {
+					root = &roots[i]
+					break
+				}
+			}
+			if root == nil {
+				return fmt.Errorf("no %s root found for round %d", rootType, round)
+			}
+
+			tree := mkvs.NewWithRoot(nil, ndb, *root)
+			defer tree.Close()
+
+			stats, err := mkvs.ComputeStats(cmd.Context(), tree, mkvs.WithStatsPrefixLength(prefixLength))
+			if err != nil {
+				return fmt.Errorf("failed to compute tree statistics: %w", err)
+			}
+			if top > 0 && len(stats.Prefixes) > top {
+				stats.Prefixes = stats.Prefixes[:top]
+			}
+
+			if outputFormat == "json" {
+				prettyStats, err := cmdCommon.PrettyJSONMarshal(stats)
+				if err != nil {
+					return fmt.Errorf("failed to marshal statistics as JSON: %w", err)
+				}
+				fmt.Println(string(prettyStats))
+				return nil
+			}
+
+			fmt.Println("Root:", root)
+			fmt.Println("Nodes:")
+			fmt.Println("  Internal: ", stats.InternalNodes)
+			fmt.Println("  Leaf: ", stats.LeafNodes)
+			fmt.Println("Key bytes: ", stats.KeyBytes)
+			fmt.Println("Value bytes: ", stats.ValueBytes)
+			fmt.Println("Leaf depths:")
+			for depth, n := range stats.LeafDepths {
+				if n == 0 {
+					continue
+				}
+				fmt.Printf("  %3d: %d\n", depth, n)
+			}
+			fmt.Println("Prefixes:")
+			for _, ps := range stats.Prefixes {
+				fmt.Printf("  %s: keys=%d key_bytes=%d value_bytes=%d\n",
+					hex.EncodeToString(ps.Prefix), ps.Keys, ps.KeyBytes, ps.ValueBytes,
+				)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint64Var(&round, "round", 0, "round of the root to inspect (default: latest)")
+	cmd.Flags().StringVar(&rootType, "root-type", "state", "type of the root to inspect (state, io)")
+	cmd.Flags().IntVar(&prefixLength, "prefix-length", mkvs.DefaultStatsPrefixLength, "length of key prefixes to aggregate statistics by (bytes)")
+	cmd.Flags().IntVar(&top, "top", 20, "number of largest prefixes to report (0 for all)")
+	cmd.Flags().StringVar(&outputFormat, "output", "text", "output format (text, json)")
+
+	return cmd
+}
@@ -289,5 +289,7 @@ func Register(parentCmd *cobra.Command) {
 	storageCmd.AddCommand(newCompactCmd())
 	storageCmd.AddCommand(newPruneCmd())
 	storageCmd.AddCommand(newInspectCmd())
+	storageCmd.AddCommand(newCheckpointCmd())
+	storageCmd.AddCommand(newStatsCmd())
 	parentCmd.AddCommand(storageCmd)
 }
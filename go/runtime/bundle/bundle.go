@@ -132,8 +132,8 @@ func (bnd *Bundle) Validate() error {
 		if !ok {
 			// Ignore the manifest not having a digest entry, though
 			// it having one and being valid (while quite a feat) is
-			// also ok.
-			if fn == manifestName {
+			// also ok. The same goes for the detached signatures.
+			if fn == manifestName || fn == signaturesName || fn == signedManifestName {
 				continue
 			}
 			return fmt.Errorf("runtime/bundle: missing digest: '%s'", fn)
@@ -143,6 +143,11 @@ func (bnd *Bundle) Validate() error {
 		}
 	}
 
+	// Ensure the detached signatures are well-formed.
+	if _, err := bnd.Signatures(); err != nil {
+		return err
+	}
+
 	for _, comp := range bnd.Manifest.GetAvailableComponents() {
 		// Make sure the SGX signature is valid if it exists.
 		if err := bnd.verifySgxSignature(comp); err != nil {
@@ -495,6 +500,15 @@ func (bnd *Bundle) Rewrite(manifestRewriter ManifestRewriterFunc) {
 	if manifestRewriter == nil {
 		return
 	}
+	// Rewriting the manifest invalidates any detached signatures, so keep the original manifest
+	// in order for the signatures to remain verifiable. The signatures must be verified before
+	// rewriting the manifest.
+	if _, ok := bnd.Data[signaturesName]; ok {
+		if _, ok = bnd.Data[signedManifestName]; !ok {
+			bnd.Data[signedManifestName] = bnd.Data[manifestName]
+		}
+	}
+
 	manifestRewriter(bnd.Manifest)
 
 	// Recompute the manifest hash and change the underlying serialized manifest.
@@ -542,7 +556,12 @@ func Open(fn string, opts ...OpenOption) (_ *Bundle, err error) {
 				return nil, fmt.Errorf("runtime/bundle: invalid manifest file name: '%s'", v.Name)
 			}
 		default:
-			if filepath.Dir(v.Name) != "." {
+			// The original signed manifest is only ever written by the node itself when
+			// rewriting the manifest, so it must not be trusted when included in a bundle.
+			if v.Name == signedManifestName {
+				return nil, fmt.Errorf("runtime/bundle: unexpected signed manifest")
+			}
+			if v.Name != signaturesName && filepath.Dir(v.Name) != "." {
 				return nil, fmt.Errorf("runtime/bundle: failed to sanitize path '%s'", v.Name)
 			}
 		}
@@ -97,6 +97,32 @@ func (c *Component) Matches(id component.ID) bool {
 	return c.ID() == id
 }
 
+// Files returns the names of all bundle files used by this component.
+func (c *Component) Files() []string {
+	var files []string
+	add := func(fns ...string) {
+		for _, fn := range fns {
+			if fn != "" {
+				files = append(files, fn)
+			}
+		}
+	}
+
+	switch c.ELF {
+	case nil:
+		add(c.Executable)
+	default:
+		add(c.ELF.Executable)
+	}
+	if sgx := c.SGX; sgx != nil {
+		add(sgx.Executable, sgx.Signature)
+	}
+	if tdx := c.TDX; tdx != nil {
+		add(tdx.Firmware, tdx.Kernel, tdx.InitRD, tdx.Stage2Image)
+	}
+	return files
+}
+
 // Validate validates the component structure for well-formedness.
 func (c *Component) Validate() error {
 	if !common.AtMostOneTrue(
@@ -0,0 +1,697 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+const (
+	// defaultDownloadSegments is the default maximum number of segments of a single bundle
+	// that are downloaded in parallel.
+	defaultDownloadSegments = 4
+
+	// defaultStallTimeout is the default maximum amount of time a download may make
+	// no progress before it is aborted.
+	defaultStallTimeout = time.Minute
+
+	// defaultMinSegmentSize is the default minimum size of a single download segment.
+	defaultMinSegmentSize = 4 * 1024 * 1024 // 4 MB
+
+	// downloadChunkSize is the maximum amount of data processed at once during downloads.
+	downloadChunkSize = 32 * 1024 // 32 KB
+
+	// downloadStateInterval is the interval at which the state of partial downloads
+	// is persisted.
+	downloadStateInterval = 5 * time.Second
+
+	// partialSuffix is the file name suffix of partially downloaded bundles.
+	partialSuffix = ".part"
+
+	// stateSuffix is the file name suffix of the persisted state of partial downloads.
+	stateSuffix = ".state"
+
+	// fileScheme is the URL scheme of local registries.
+	fileScheme = "file"
+)
+
+// errDownloadStalled is the error returned when a download makes no progress.
+var errDownloadStalled = errors.New("download stalled")
+
+// DownloadStatus is the status of a pending bundle download.
+type DownloadStatus struct {
+	// ManifestHash is the hash of the manifest of the bundle being downloaded.
+	ManifestHash hash.Hash `json:"manifest_hash"`
+
+	// Mirrors are the URLs from which the bundle is being downloaded.
+	Mirrors []string `json:"mirrors,omitempty"`
+
+	// Size is the size of the bundle in bytes, if known.
+	Size uint64 `json:"size,omitempty"`
+
+	// Downloaded is the number of bytes downloaded so far, including resumed data.
+	Downloaded uint64 `json:"downloaded"`
+
+	// StartedAt is the time of the first download attempt.
+	StartedAt time.Time `json:"started_at"`
+
+	// LastError is the error of the last failed download attempt.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// downloadProgress tracks the progress of a pending bundle download.
+type downloadProgress struct {
+	mu     sync.Mutex
+	status DownloadStatus
+}
+
+func newDownloadProgress(manifestHash hash.Hash) *downloadProgress {
+	return &downloadProgress{
+		status: DownloadStatus{
+			ManifestHash: manifestHash,
+			StartedAt:    time.Now(),
+		},
+	}
+}
+
+func (p *downloadProgress) start(mirrors []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.status.Mirrors = slices.Clone(mirrors)
+	p.status.Size = 0
+	p.status.Downloaded = 0
+	p.status.LastError = ""
+}
+
+func (p *downloadProgress) reset(size uint64, downloaded uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.status.Size = size
+	p.status.Downloaded = downloaded
+}
+
+func (p *downloadProgress) add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.status.Downloaded += uint64(n)
+}
+
+func (p *downloadProgress) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.status.LastError = err.Error()
+}
+
+func (p *downloadProgress) Status() DownloadStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := p.status
+	status.Mirrors = slices.Clone(p.status.Mirrors)
+	return status
+}
+
+// downloadState is the persisted state of a partial bundle download.
+type downloadState struct {
+	mu sync.Mutex
+
+	// Size is the size of the bundle in bytes.
+	Size uint64 `json:"size"`
+
+	// Segments are the segments of the bundle.
+	Segments []*downloadSegment `json:"segments"`
+}
+
+// downloadSegment is a segment of a bundle download.
+type downloadSegment struct {
+	// Offset is the offset of the next byte of the segment that needs to be downloaded.
+	Offset uint64 `json:"offset"`
+
+	// End is the offset of the end of the segment (exclusive).
+	End uint64 `json:"end"`
+}
+
+func newDownloadState(size uint64, maxSegments uint64, minSegmentSize uint64) *downloadState {
+	n := max(min(maxSegments, size/minSegmentSize), 1)
+	segmentSize := size / n
+
+	segments := make([]*downloadSegment, 0, n)
+	for i := range n {
+		start := i * segmentSize
+		end := start + segmentSize
+		if i == n-1 {
+			end = size
+		}
+		segments = append(segments, &downloadSegment{
+			Offset: start,
+			End:    end,
+		})
+	}
+
+	return &downloadState{
+		Size:     size,
+		Segments: segments,
+	}
+}
+
+// loadDownloadState loads the persisted state of a partial download of a bundle of the given
+// size. It returns nil if the state doesn't exist or can't be used to resume the download.
+func loadDownloadState(fn string, size uint64) *downloadState {
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		return nil
+	}
+
+	var state downloadState
+	if err = json.Unmarshal(b, &state); err != nil {
+		return nil
+	}
+	if state.Size != size || len(state.Segments) == 0 {
+		return nil
+	}
+	for _, seg := range state.Segments {
+		if seg.Offset > seg.End || seg.End > size {
+			return nil
+		}
+	}
+
+	return &state
+}
+
+func (s *downloadState) save(fn string) error {
+	s.mu.Lock()
+	b, err := json.Marshal(s)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmpFn := fn + ".tmp"
+	if err = os.WriteFile(tmpFn, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmpFn, fn)
+}
+
+func (s *downloadState) downloaded() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	remaining := uint64(0)
+	for _, seg := range s.Segments {
+		remaining += seg.End - seg.Offset
+	}
+	return s.Size - remaining
+}
+
+func (s *downloadState) remaining(seg *downloadSegment) (uint64, uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return seg.Offset, seg.End
+}
+
+func (s *downloadState) advance(seg *downloadSegment, offset uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seg.Offset = offset
+}
+
+// resolveMirrors fetches the bundle metadata from all configured registries and returns
+// the distinct URLs from which the bundle can be downloaded.
+func (m *Manager) resolveMirrors(ctx context.Context, runtimeID common.Namespace, manifestHash hash.Hash) ([]string, error) {
+	var (
+		errs    error
+		mirrors []string
+	)
+
+	for _, baseURLs := range [][]string{m.runtimeBaseURLs[runtimeID], m.globalBaseURLs} {
+		for _, baseURL := range baseURLs {
+			bundleURL, err := m.resolveBundleURL(ctx, manifestHash, baseURL)
+			if err != nil {
+				errs = errors.Join(errs, err)
+				continue
+			}
+			if !slices.Contains(mirrors, bundleURL) {
+				mirrors = append(mirrors, bundleURL)
+			}
+		}
+	}
+
+	if len(mirrors) == 0 {
+		return nil, errs
+	}
+	return mirrors, nil
+}
+
+func (m *Manager) resolveBundleURL(ctx context.Context, manifestHash hash.Hash, baseURL string) (string, error) {
+	metaURL, err := url.JoinPath(baseURL, manifestHash.Hex())
+	if err != nil {
+		m.logger.Error("failed to construct metadata URL",
+			"err", err,
+		)
+		return "", fmt.Errorf("failed to construct metadata URL: %w", err)
+	}
+
+	metadata, err := m.fetchMetadata(ctx, metaURL)
+	if err != nil {
+		m.logger.Error("failed to download metadata",
+			"err", err,
+			"url", metaURL,
+		)
+		return "", fmt.Errorf("failed to download metadata: %w", err)
+	}
+
+	return resolveURL(metaURL, metadata)
+}
+
+// fetchBundle downloads the bundle from the given mirrors and returns the path to the
+// downloaded file.
+//
+// If all mirrors support range requests, the bundle is downloaded in segments which are spread
+// across the mirrors and the download is resumed in case it was previously interrupted.
+func (m *Manager) fetchBundle(ctx context.Context, manifestHash hash.Hash, mirrors []string, progress *downloadProgress) (string, error) {
+	progress.start(mirrors)
+
+	fn := filepath.Join(m.downloadDir, manifestHash.Hex()+FileExtension+partialSuffix)
+
+	size, rangeMirrors := m.probeMirrors(ctx, mirrors)
+	if len(rangeMirrors) == 0 {
+		return m.fetchWhole(ctx, fn, mirrors, progress)
+	}
+	if size >= uint64(m.maxBundleSizeBytes) {
+		return "", fmt.Errorf("bundle exceeds size limit of %d bytes", m.maxBundleSizeBytes)
+	}
+	return m.fetchSegments(ctx, fn, size, rangeMirrors, progress)
+}
+
+// probeMirrors returns the size of the bundle and the mirrors that support range requests.
+func (m *Manager) probeMirrors(ctx context.Context, mirrors []string) (uint64, []string) {
+	var (
+		size         uint64
+		rangeMirrors []string
+	)
+
+	for _, mirror := range mirrors {
+		mirrorSize, err := m.probeMirror(ctx, mirror)
+		if err != nil {
+			m.logger.Debug("mirror does not support resumable downloads",
+				"err", err,
+				"url", mirror,
+			)
+			continue
+		}
+
+		switch size {
+		case 0:
+			size = mirrorSize
+		case mirrorSize:
+		default:
+			m.logger.Warn("ignoring mirror with mismatched bundle size",
+				"url", mirror,
+				"size", mirrorSize,
+				"expected_size", size,
+			)
+			continue
+		}
+		rangeMirrors = append(rangeMirrors, mirror)
+	}
+
+	return size, rangeMirrors
+}
+
+func (m *Manager) probeMirror(ctx context.Context, mirror string) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, mirror, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode != http.StatusOK:
+		return 0, fmt.Errorf("invalid status code %d", resp.StatusCode)
+	case resp.Header.Get("Accept-Ranges") != "bytes":
+		return 0, fmt.Errorf("range requests not supported")
+	case resp.ContentLength <= 0:
+		return 0, fmt.Errorf("unknown content length")
+	}
+
+	return uint64(resp.ContentLength), nil
+}
+
+// fetchWhole downloads the whole bundle from the first mirror that succeeds.
+func (m *Manager) fetchWhole(ctx context.Context, fn string, mirrors []string, progress *downloadProgress) (string, error) {
+	var errs error
+	for _, mirror := range mirrors {
+		err := m.fetchWholeFrom(ctx, fn, mirror, progress)
+		if err == nil {
+			return fn, nil
+		}
+		errs = errors.Join(errs, err)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return "", errs
+}
+
+func (m *Manager) fetchWholeFrom(ctx context.Context, fn string, mirror string, progress *downloadProgress) (err error) {
+	m.logger.Info("downloading bundle",
+		"url", mirror,
+	)
+
+	file, err := os.Create(fn)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		file.Close()
+		if err != nil {
+			_ = os.Remove(fn)
+		}
+	}()
+
+	var written int64
+	err = m.stream(ctx, mirror, "",
+		func(resp *http.Response) error {
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("failed to fetch bundle: invalid status code %d", resp.StatusCode)
+			}
+			progress.reset(uint64(max(resp.ContentLength, 0)), 0)
+			return nil
+		},
+		func(b []byte) error {
+			if written += int64(len(b)); written >= m.maxBundleSizeBytes {
+				return fmt.Errorf("bundle exceeds size limit of %d bytes", m.maxBundleSizeBytes)
+			}
+			if _, err := file.Write(b); err != nil {
+				return fmt.Errorf("failed to save bundle: %w", err)
+			}
+			progress.add(len(b))
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	m.logger.Info("bundle downloaded",
+		"url", mirror,
+	)
+
+	return nil
+}
+
+// fetchSegments downloads the bundle of the given size in segments, resuming any previously
+// interrupted download.
+func (m *Manager) fetchSegments(ctx context.Context, fn string, size uint64, mirrors []string, progress *downloadProgress) (string, error) {
+	stateFn := fn + stateSuffix
+
+	state := loadDownloadState(stateFn, size)
+	if _, err := os.Stat(fn); err != nil || state == nil {
+		state = newDownloadState(size, m.downloadSegments, m.minSegmentSize)
+	}
+	progress.reset(size, state.downloaded())
+
+	m.logger.Info("downloading bundle",
+		"mirrors", mirrors,
+		"size", size,
+		"segments", len(state.Segments),
+		"resumed_bytes", state.downloaded(),
+	)
+
+	file, err := os.OpenFile(fn, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("failed to open partial bundle: %w", err)
+	}
+	defer file.Close()
+
+	if err = file.Truncate(int64(size)); err != nil {
+		return "", fmt.Errorf("failed to allocate partial bundle: %w", err)
+	}
+
+	saveState := func() {
+		if err := state.save(stateFn); err != nil {
+			m.logger.Warn("failed to persist download state",
+				"err", err,
+				"path", stateFn,
+			)
+		}
+	}
+	saveState()
+
+	// Persist the download state periodically so that downloads can be resumed even if the node
+	// is restarted.
+	stopCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+
+		ticker := time.NewTicker(downloadStateInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				saveState()
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	for i, seg := range state.Segments {
+		if seg.Offset == seg.End {
+			continue
+		}
+		group.Go(func() error {
+			return m.fetchSegment(groupCtx, file, mirrors, i, state, seg, progress)
+		})
+	}
+	err = group.Wait()
+
+	close(stopCh)
+	<-doneCh
+
+	if err != nil {
+		saveState()
+		return "", err
+	}
+	if err = file.Sync(); err != nil {
+		return "", fmt.Errorf("failed to save bundle: %w", err)
+	}
+	_ = os.Remove(stateFn)
+
+	m.logger.Info("bundle downloaded",
+		"mirrors", mirrors,
+	)
+
+	return fn, nil
+}
+
+// fetchSegment downloads the given segment, starting with the mirror assigned to the segment
+// and falling back to the other mirrors in case of failures.
+func (m *Manager) fetchSegment(ctx context.Context, file *os.File, mirrors []string, idx int, state *downloadState, seg *downloadSegment, progress *downloadProgress) error {
+	var errs error
+	for i := range mirrors {
+		mirror := mirrors[(idx+i)%len(mirrors)]
+
+		err := m.fetchSegmentFrom(ctx, file, mirror, state, seg, progress)
+		if err == nil {
+			return nil
+		}
+		errs = errors.Join(errs, err)
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		m.logger.Warn("failed to download bundle segment",
+			"err", err,
+			"url", mirror,
+		)
+	}
+	return errs
+}
+
+func (m *Manager) fetchSegmentFrom(ctx context.Context, file *os.File, mirror string, state *downloadState, seg *downloadSegment, progress *downloadProgress) error {
+	offset, end := state.remaining(seg)
+	if offset == end {
+		return nil
+	}
+
+	err := m.stream(ctx, mirror, fmt.Sprintf("bytes=%d-%d", offset, end-1),
+		func(resp *http.Response) error {
+			if resp.StatusCode != http.StatusPartialContent {
+				return fmt.Errorf("failed to fetch bundle segment: invalid status code %d", resp.StatusCode)
+			}
+			return nil
+		},
+		func(b []byte) error {
+			if uint64(len(b)) > end-offset {
+				return fmt.Errorf("failed to fetch bundle segment: too much data")
+			}
+			if _, err := file.WriteAt(b, int64(offset)); err != nil {
+				return fmt.Errorf("failed to save bundle segment: %w", err)
+			}
+			offset += uint64(len(b))
+			state.advance(seg, offset)
+			progress.add(len(b))
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if offset != end {
+		return fmt.Errorf("failed to fetch bundle segment: %w", io.ErrUnexpectedEOF)
+	}
+	return nil
+}
+
+// stream performs a GET request for the given URL and passes the response body to the given
+// function in chunks. Downloads are throttled according to the configured bandwidth limit and
+// aborted in case no data is received within the stall timeout.
+func (m *Manager) stream(ctx context.Context, rawURL string, byteRange string, onResponse func(*http.Response) error, onData func([]byte) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	timer := time.AfterFunc(m.stallTimeout, func() {
+		cancel(errDownloadStalled)
+	})
+	defer timer.Stop()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch bundle: %w", err)
+	}
+	if byteRange != "" {
+		req.Header.Set("Range", byteRange)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch bundle: %w", downloadError(ctx, err))
+	}
+	defer resp.Body.Close()
+
+	if err = onResponse(resp); err != nil {
+		return err
+	}
+
+	buf := make([]byte, downloadChunkSize)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			timer.Reset(m.stallTimeout)
+			if m.limiter != nil {
+				if err := m.limiter.WaitN(ctx, n); err != nil {
+					return fmt.Errorf("failed to fetch bundle: %w", downloadError(ctx, err))
+				}
+				timer.Reset(m.stallTimeout)
+			}
+			if err := onData(buf[:n]); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case err == io.EOF:
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to read bundle: %w", downloadError(ctx, err))
+		}
+	}
+}
+
+// downloadError returns the reason why the download was aborted, if any.
+func downloadError(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, errDownloadStalled) {
+		return cause
+	}
+	return err
+}
+
+// cleanDownloads removes partial downloads of bundles which are no longer queued for download.
+func (m *Manager) cleanDownloads() {
+	entries, err := os.ReadDir(m.downloadDir)
+	if err != nil {
+		m.logger.Error("failed to read download directory",
+			"err", err,
+		)
+		return
+	}
+
+	m.mu.RLock()
+	queued := make(map[string]struct{})
+	for _, hashes := range m.downloadQueue {
+		for _, h := range hashes {
+			queued[h.Hex()] = struct{}{}
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, entry := range entries {
+		name, _, _ := strings.Cut(entry.Name(), ".")
+		if _, ok := queued[name]; ok {
+			continue
+		}
+
+		fn := filepath.Join(m.downloadDir, entry.Name())
+		if err := os.RemoveAll(fn); err != nil {
+			m.logger.Error("failed to remove partial download",
+				"err", err,
+				"path", fn,
+			)
+		}
+	}
+}
+
+// resolveURL resolves the given bundle URL reference relative to the metadata URL.
+//
+// Local bundle URLs are only allowed for local registries.
+func resolveURL(metaURL string, rawURL string) (string, error) {
+	base, err := url.Parse(metaURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL '%s': %w", metaURL, err)
+	}
+	ref, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL '%s': %w", rawURL, err)
+	}
+
+	resolved := base.ResolveReference(ref)
+	if resolved.Scheme == fileScheme && base.Scheme != fileScheme {
+		return "", fmt.Errorf("invalid URL '%s': local bundles are only allowed in local registries", rawURL)
+	}
+	return resolved.String(), nil
+}
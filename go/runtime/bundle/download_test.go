@@ -0,0 +1,203 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+func TestValidateAndNormalizeURL(t *testing.T) {
+	for _, tc := range []struct {
+		url      string
+		expected string
+		err      string
+	}{
+		{"https://example.com/metadata/", "https://example.com/metadata/", ""},
+		{"http://127.0.0.1:8080", "http://127.0.0.1:8080", ""},
+		{"file:///var/lib/registry", "file:///var/lib/registry", ""},
+		{"/var/lib/registry/", "file:///var/lib/registry", ""},
+		{"file://example.com/registry", "", "remote file URLs are not supported"},
+		{"ftp://example.com/registry", "", "unsupported scheme"},
+		{"registry", "", "unsupported scheme"},
+	} {
+		normalized, err := validateAndNormalizeURL(tc.url)
+		if tc.err != "" {
+			require.ErrorContains(t, err, tc.err, tc.url)
+			continue
+		}
+		require.NoError(t, err, tc.url)
+		require.Equal(t, tc.expected, normalized)
+	}
+}
+
+func TestResolveURL(t *testing.T) {
+	resolved, err := resolveURL("file:///registry/abcd", "bundle.orc")
+	require.NoError(t, err)
+	require.Equal(t, "file:///registry/bundle.orc", resolved)
+
+	resolved, err = resolveURL("https://example.com/metadata/abcd", "https://mirror.example.com/bundle.orc")
+	require.NoError(t, err)
+	require.Equal(t, "https://mirror.example.com/bundle.orc", resolved)
+
+	_, err = resolveURL("https://example.com/metadata/abcd", "file:///etc/passwd")
+	require.ErrorContains(t, err, "local bundles are only allowed in local registries")
+}
+
+func TestDownloadBundle(t *testing.T) {
+	require := require.New(t)
+
+	tmpDir := t.TempDir()
+	store := newMockStore()
+	manager, err := NewManager(tmpDir, nil, store, newMockVolumeManager())
+	require.NoError(err)
+	err = common.Mkdir(manager.downloadDir)
+	require.NoError(err)
+
+	var runtimeID common.Namespace
+	err = runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	require.NoError(err)
+
+	// Prepare a local registry.
+	registryDir := filepath.Join(tmpDir, "registry")
+	err = common.Mkdir(registryDir)
+	require.NoError(err)
+
+	bnd := &Bundle{
+		Manifest: &Manifest{
+			Name: "test-runtime",
+			ID:   runtimeID,
+		},
+	}
+	err = bnd.Write(filepath.Join(registryDir, "bundle.orc"))
+	require.NoError(err)
+	manifestHash := bnd.Manifest.Hash()
+	err = os.WriteFile(filepath.Join(registryDir, manifestHash.Hex()), []byte("bundle.orc\n"), 0o600)
+	require.NoError(err)
+
+	registryURL, err := validateAndNormalizeURL(registryDir)
+	require.NoError(err)
+	manager.runtimeIDs[runtimeID] = struct{}{}
+	manager.globalBaseURLs = []string{registryURL}
+
+	manager.Download(runtimeID, []hash.Hash{manifestHash})
+	manager.download(context.Background())
+
+	require.Len(store.manifestHashes, 1)
+	require.Empty(manager.DownloadStatus(runtimeID))
+
+	entries, err := os.ReadDir(manager.downloadDir)
+	require.NoError(err)
+	require.Empty(entries, "partial downloads should be removed")
+}
+
+func TestResumeDownload(t *testing.T) {
+	require := require.New(t)
+
+	tmpDir := t.TempDir()
+	manager, err := NewManager(tmpDir, nil, newMockStore(), newMockVolumeManager())
+	require.NoError(err)
+	err = common.Mkdir(manager.downloadDir)
+	require.NoError(err)
+	manager.minSegmentSize = 100
+	manager.downloadSegments = 4
+	manager.stallTimeout = 10 * time.Second
+
+	data := make([]byte, 1000)
+	_, err = rand.Read(data)
+	require.NoError(err)
+
+	var (
+		mu     sync.Mutex
+		ranges []string
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rng := r.Header.Get("Range"); rng != "" {
+			mu.Lock()
+			ranges = append(ranges, rng)
+			mu.Unlock()
+		}
+		http.ServeContent(w, r, "bundle.orc", time.Time{}, bytes.NewReader(data))
+	})
+	mirror1 := httptest.NewServer(handler)
+	defer mirror1.Close()
+	mirror2 := httptest.NewServer(handler)
+	defer mirror2.Close()
+
+	// Simulate an interrupted download where part of the first segment was already downloaded.
+	var manifestHash hash.Hash
+	fn := filepath.Join(manager.downloadDir, manifestHash.Hex()+FileExtension+partialSuffix)
+	err = os.WriteFile(fn, data[:100], 0o600)
+	require.NoError(err)
+	state := newDownloadState(uint64(len(data)), manager.downloadSegments, manager.minSegmentSize)
+	require.Len(state.Segments, 4)
+	state.Segments[0].Offset = 100
+	err = state.save(fn + stateSuffix)
+	require.NoError(err)
+
+	progress := newDownloadProgress(manifestHash)
+	mirrors := []string{mirror1.URL + "/bundle.orc", mirror2.URL + "/bundle.orc"}
+	src, err := manager.fetchBundle(context.Background(), manifestHash, mirrors, progress)
+	require.NoError(err)
+	require.Equal(fn, src)
+
+	downloaded, err := os.ReadFile(src)
+	require.NoError(err)
+	require.Equal(data, downloaded)
+	_, err = os.Stat(fn + stateSuffix)
+	require.ErrorIs(err, os.ErrNotExist, "download state should be removed")
+
+	require.ElementsMatch([]string{"bytes=100-249", "bytes=250-499", "bytes=500-749", "bytes=750-999"}, ranges)
+
+	status := progress.Status()
+	require.EqualValues(len(data), status.Size)
+	require.EqualValues(len(data), status.Downloaded)
+	require.Equal(mirrors, status.Mirrors)
+
+	// Bundles that exceed the size limit should be rejected.
+	manager.maxBundleSizeBytes = 500
+	_, err = manager.fetchBundle(context.Background(), manifestHash, mirrors, progress)
+	require.ErrorContains(err, "exceeds size limit")
+}
+
+func TestStalledDownload(t *testing.T) {
+	require := require.New(t)
+
+	tmpDir := t.TempDir()
+	manager, err := NewManager(tmpDir, nil, newMockStore(), newMockVolumeManager())
+	require.NoError(err)
+	err = common.Mkdir(manager.downloadDir)
+	require.NoError(err)
+	manager.stallTimeout = 100 * time.Millisecond
+
+	doneCh := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			return
+		}
+
+		// Send some data and then stall without support for range requests.
+		_, _ = w.Write([]byte(strings.Repeat("a", 10)))
+		w.(http.Flusher).Flush()
+		<-doneCh
+	}))
+	defer server.Close()
+	defer close(doneCh)
+
+	progress := newDownloadProgress(hash.Hash{})
+	_, err = manager.fetchBundle(context.Background(), hash.Hash{}, []string{server.URL}, progress)
+	require.ErrorIs(err, errDownloadStalled)
+	require.EqualValues(10, progress.Status().Downloaded)
+}
@@ -4,24 +4,26 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	cmSync "github.com/oasisprotocol/oasis-core/go/common/sync"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/config"
-	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
 	"github.com/oasisprotocol/oasis-core/go/runtime/volume"
 )
 
@@ -147,16 +149,30 @@ type Manager struct {
 	dataDir            string
 	bundleDir          string
 	tmpBundleDir       string
+	downloadDir        string
 	maxBundleSizeBytes int64
 
+	downloadSegments uint64
+	minSegmentSize   uint64
+	stallTimeout     time.Duration
+	limiter          *rate.Limiter
+
+	keepVersions    uint64
+	maxTotalSize    uint64
+	temporaryMaxAge time.Duration
+
 	runtimeIDs map[common.Namespace]struct{}
 
 	runtimeBaseURLs map[common.Namespace][]string
 	globalBaseURLs  []string
 
+	publishers map[common.Namespace]*rtConfig.PublishersConfig
+
 	triggerCh     chan struct{}
 	downloadQueue map[common.Namespace][]hash.Hash
-	cleanupQueue  map[common.Namespace]version.Version
+	downloads     map[common.Namespace]map[hash.Hash]*downloadProgress
+	// activeVersions are the active runtime versions used by the retention policy.
+	activeVersions map[common.Namespace]version.Version
 
 	client        *http.Client
 	store         ManifestStore
@@ -169,9 +185,14 @@ type Manager struct {
 func NewManager(dataDir string, runtimeIDs []common.Namespace, store ManifestStore, volumeManager VolumeManager) (*Manager, error) {
 	logger := logging.GetLogger("runtime/bundle/manager")
 
-	// Configure the HTTP client with a reasonable timeout.
+	// Configure the HTTP client with a reasonable timeout for response headers. Response bodies
+	// are not limited as large bundles may take a long time to download. Local registries are
+	// accessed via the file scheme.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = requestTimeout
+	transport.RegisterProtocol(fileScheme, http.NewFileTransport(http.Dir("/")))
 	client := http.Client{
-		Timeout: requestTimeout,
+		Transport: transport,
 	}
 
 	// Define a limit on the maximum allowed bundle size.
@@ -180,15 +201,52 @@ func NewManager(dataDir string, runtimeIDs []common.Namespace, store ManifestSto
 		bundleSize = int64(config.ParseSizeInBytes(size))
 	}
 
+	// Configure bundle downloads.
+	downloadCfg := config.GlobalConfig.Runtime.Download
+	downloadSegments := uint64(defaultDownloadSegments)
+	if downloadCfg.Segments > 0 {
+		downloadSegments = downloadCfg.Segments
+	}
+	stallTimeout := defaultStallTimeout
+	if downloadCfg.StallTimeout > 0 {
+		stallTimeout = downloadCfg.StallTimeout
+	}
+	var limiter *rate.Limiter
+	if bandwidth := downloadCfg.MaxBandwidth; bandwidth != "" {
+		limit := config.ParseSizeInBytes(bandwidth)
+		if limit == 0 {
+			return nil, fmt.Errorf("malformed maximum download bandwidth: %s", bandwidth)
+		}
+		limiter = rate.NewLimiter(rate.Limit(limit), downloadChunkSize)
+	}
+
+	// Configure the bundle retention policy.
+	retentionCfg := config.GlobalConfig.Runtime.Retention
+	var maxTotalSize uint64
+	if size := retentionCfg.MaxTotalSize; size != "" {
+		if maxTotalSize = uint64(config.ParseSizeInBytes(size)); maxTotalSize == 0 {
+			return nil, fmt.Errorf("malformed maximum total bundle size: %s", size)
+		}
+	}
+	temporaryMaxAge := defaultTemporaryMaxAge
+	if retentionCfg.TemporaryMaxAge > 0 {
+		temporaryMaxAge = retentionCfg.TemporaryMaxAge
+	}
+
 	// Validate global repository URLs.
 	globalBaseURLs, err := validateAndNormalizeURLs(config.GlobalConfig.Runtime.Registries)
 	if err != nil {
 		return nil, err
 	}
 
-	// Validate each runtime's registry URLs.
+	// Validate each runtime's registry URLs and remember trusted publishers.
 	runtimeBaseURLs := make(map[common.Namespace][]string)
+	publishers := make(map[common.Namespace]*rtConfig.PublishersConfig)
 	for _, runtime := range config.GlobalConfig.Runtime.Runtimes {
+		if runtime.Publishers != nil {
+			publishers[runtime.ID] = runtime.Publishers
+		}
+
 		urls, err := validateAndNormalizeURLs(runtime.Registries)
 		if err != nil {
 			return nil, err
@@ -210,13 +268,23 @@ func NewManager(dataDir string, runtimeIDs []common.Namespace, store ManifestSto
 		dataDir:            dataDir,
 		bundleDir:          ExplodedPath(dataDir),
 		tmpBundleDir:       TmpBundlePath(dataDir),
+		downloadDir:        DownloadsPath(dataDir),
 		maxBundleSizeBytes: bundleSize,
+		downloadSegments:   downloadSegments,
+		minSegmentSize:     defaultMinSegmentSize,
+		stallTimeout:       stallTimeout,
+		limiter:            limiter,
+		keepVersions:       retentionCfg.KeepVersions,
+		maxTotalSize:       maxTotalSize,
+		temporaryMaxAge:    temporaryMaxAge,
 		runtimeIDs:         runtimes,
 		globalBaseURLs:     globalBaseURLs,
 		runtimeBaseURLs:    runtimeBaseURLs,
+		publishers:         publishers,
 		triggerCh:          make(chan struct{}, 1),
 		downloadQueue:      make(map[common.Namespace][]hash.Hash),
-		cleanupQueue:       make(map[common.Namespace]version.Version),
+		downloads:          make(map[common.Namespace]map[hash.Hash]*downloadProgress),
+		activeVersions:     make(map[common.Namespace]version.Version),
 		client:             &client,
 		store:              store,
 		volumeManager:      volumeManager,
@@ -251,6 +319,16 @@ func (m *Manager) run(ctx context.Context) {
 		return
 	}
 
+	// Ensure the download directory exists. Partial downloads are kept so that they can be
+	// resumed.
+	if err := common.Mkdir(m.downloadDir); err != nil {
+		m.logger.Error("failed to create download directory",
+			"err", err,
+			"dir", m.downloadDir,
+		)
+		return
+	}
+
 	// Ensure the bundle directory exists.
 	if err := common.Mkdir(m.bundleDir); err != nil {
 		m.logger.Error("failed to create bundle directory",
@@ -309,7 +387,7 @@ func (m *Manager) run(ctx context.Context) {
 			return
 		}
 
-		m.download()
+		m.download(ctx)
 		m.clean()
 	}
 }
@@ -363,13 +441,18 @@ func (m *Manager) AddTemporary(tmpPath string, opts ...AddOption) error {
 	return m.Add(path, opts...)
 }
 
-// Remove removes bundles matching the given labels.
+// Remove removes bundles matching the given labels, including their exploded data.
 func (m *Manager) Remove(runtimeID common.Namespace, labels map[string]string) {
 	if err := validateLabels(labels); err != nil {
 		return
 	}
 
-	m.store.RemoveManifestsWithLabels(runtimeID, labels)
+	for _, manifest := range m.store.Manifests() {
+		if manifest.ID != runtimeID || !manifest.HasLabels(labels) {
+			continue
+		}
+		m.cleanBundle(manifest)
+	}
 }
 
 // WriteTemporary writes the given data to a temporary file that can later be referenced as a
@@ -451,12 +534,11 @@ func (m *Manager) Download(runtimeID common.Namespace, manifestHashes []hash.Has
 	}
 }
 
-// Cleanup updates the runtime's maximum bundle version for pending clean-up.
-//
-// If the specified runtime already exists in the cleanup queue,
-// its version is updated only if the provided versions is greater.
+// Cleanup updates the runtime's active version used by the bundle retention policy.
 //
-// Warning: If clean-up fails it's not retried.
+// Bundles of versions preceding the active version are removed, except for those retained
+// by the retention policy. The active version is updated only if the provided version
+// is greater.
 func (m *Manager) Cleanup(runtimeID common.Namespace, version version.Version) {
 	// Key managers are allowed to run obsolete runtime versions.
 	if runtimeID.IsKeyManager() {
@@ -466,10 +548,10 @@ func (m *Manager) Cleanup(runtimeID common.Namespace, version version.Version) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if v, ok := m.cleanupQueue[runtimeID]; ok && !v.Less(version) {
+	if v, ok := m.activeVersions[runtimeID]; ok && !v.Less(version) {
 		return
 	}
-	m.cleanupQueue[runtimeID] = version
+	m.activeVersions[runtimeID] = version
 
 	// Trigger immediate download and clean-up of bundles.
 	select {
@@ -478,14 +560,31 @@ func (m *Manager) Cleanup(runtimeID common.Namespace, version version.Version) {
 	}
 }
 
-func (m *Manager) download() {
+// DownloadStatus returns the status of pending bundle downloads for the given runtime.
+func (m *Manager) DownloadStatus(runtimeID common.Namespace) []DownloadStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var statuses []DownloadStatus
+	for _, hash := range m.downloadQueue[runtimeID] {
+		progress, ok := m.downloads[runtimeID][hash]
+		if !ok {
+			continue
+		}
+		statuses = append(statuses, progress.Status())
+	}
+	return statuses
+}
+
+func (m *Manager) download(ctx context.Context) {
 	m.logger.Info("downloading bundles")
 	for runtimeID := range m.runtimeIDs {
-		m.downloadBundles(runtimeID)
+		m.downloadBundles(ctx, runtimeID)
 	}
+	m.cleanDownloads()
 }
 
-func (m *Manager) downloadBundles(runtimeID common.Namespace) {
+func (m *Manager) downloadBundles(ctx context.Context, runtimeID common.Namespace) {
 	// Try to download queued bundles.
 	m.mu.RLock()
 	hashes := m.downloadQueue[runtimeID]
@@ -493,12 +592,14 @@ func (m *Manager) downloadBundles(runtimeID common.Namespace) {
 
 	downloaded := make(map[hash.Hash]struct{})
 	for _, hash := range hashes {
-		if err := m.downloadBundle(runtimeID, hash); err != nil {
+		progress := m.trackDownload(runtimeID, hash)
+		if err := m.downloadBundle(ctx, runtimeID, hash, progress); err != nil {
 			m.logger.Error("failed to download bundle",
 				"err", err,
 				"runtime_id", runtimeID,
 				"manifest_hash", hash.Hex(),
 			)
+			progress.fail(err)
 			continue
 		}
 		downloaded[hash] = struct{}{}
@@ -515,62 +616,54 @@ func (m *Manager) downloadBundles(runtimeID common.Namespace) {
 		}
 		pending = append(pending, hash)
 	}
+
+	// Stop tracking downloads which are no longer pending.
+	for hash := range m.downloads[runtimeID] {
+		if !slices.Contains(pending, hash) {
+			delete(m.downloads[runtimeID], hash)
+		}
+	}
+
 	if len(pending) == 0 {
 		delete(m.downloadQueue, runtimeID)
+		delete(m.downloads, runtimeID)
 		return
 	}
 	m.downloadQueue[runtimeID] = pending
 }
 
-func (m *Manager) downloadBundle(runtimeID common.Namespace, manifestHash hash.Hash) error {
-	var errs error
+func (m *Manager) trackDownload(runtimeID common.Namespace, manifestHash hash.Hash) *downloadProgress {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	if m.store.HasManifest(manifestHash) {
-		return nil
+	downloads, ok := m.downloads[runtimeID]
+	if !ok {
+		downloads = make(map[hash.Hash]*downloadProgress)
+		m.downloads[runtimeID] = downloads
 	}
-
-	for _, baseURLs := range [][]string{m.runtimeBaseURLs[runtimeID], m.globalBaseURLs} {
-		for _, baseURL := range baseURLs {
-			if err := m.tryDownloadBundle(manifestHash, baseURL); err != nil {
-				errs = errors.Join(errs, err)
-				continue
-			}
-
-			return nil
-		}
+	progress, ok := downloads[manifestHash]
+	if !ok {
+		progress = newDownloadProgress(manifestHash)
+		downloads[manifestHash] = progress
 	}
-
-	return errs
+	return progress
 }
 
-func (m *Manager) tryDownloadBundle(manifestHash hash.Hash, baseURL string) error {
-	metaURL, err := url.JoinPath(baseURL, manifestHash.Hex())
-	if err != nil {
-		m.logger.Error("failed to construct metadata URL",
-			"err", err,
-		)
-		return fmt.Errorf("failed to construct metadata URL: %w", err)
-	}
-
-	bundleURL, err := m.fetchMetadata(metaURL)
-	if err != nil {
-		m.logger.Error("failed to download metadata",
-			"err", err,
-			"url", metaURL,
-		)
-		return fmt.Errorf("failed to download metadata: %w", err)
+func (m *Manager) downloadBundle(ctx context.Context, runtimeID common.Namespace, manifestHash hash.Hash, progress *downloadProgress) error {
+	if m.store.HasManifest(manifestHash) {
+		return nil
 	}
 
-	bundleURL, err = validateAndNormalizeURL(bundleURL)
+	mirrors, err := m.resolveMirrors(ctx, runtimeID, manifestHash)
 	if err != nil {
 		return err
 	}
 
-	src, err := m.fetchBundle(bundleURL)
+	src, err := m.fetchBundle(ctx, manifestHash, mirrors, progress)
 	if err != nil {
 		m.logger.Error("failed to download bundle",
 			"err", err,
-			"url", metaURL,
+			"mirrors", mirrors,
 		)
 		return fmt.Errorf("failed to download bundle: %w", err)
 	}
@@ -595,12 +688,19 @@ func (m *Manager) tryDownloadBundle(manifestHash hash.Hash, baseURL string) erro
 	return nil
 }
 
-func (m *Manager) fetchMetadata(url string) (string, error) {
+func (m *Manager) fetchMetadata(ctx context.Context, url string) (string, error) {
 	m.logger.Info("downloading metadata",
 		"url", url,
 	)
 
-	resp, err := m.client.Get(url)
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+	resp, err := m.client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch metadata: %w", err)
 	}
@@ -629,53 +729,6 @@ func (m *Manager) fetchMetadata(url string) (string, error) {
 	return metadata, nil
 }
 
-func (m *Manager) fetchBundle(url string) (string, error) {
-	m.logger.Info("downloading bundle",
-		"url", url,
-	)
-
-	resp, err := m.client.Get(url)
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch bundle: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to fetch bundle: invalid status code %d", resp.StatusCode)
-	}
-
-	// Copy to a temporary file as downloaded bundles are unverified.
-	file, err := os.CreateTemp(m.tmpBundleDir, fmt.Sprintf("oasis-bundle-*%s", FileExtension))
-	if err != nil {
-		return "", fmt.Errorf("failed to create temporary file: %w", err)
-	}
-	defer func() {
-		file.Close()
-		if err != nil {
-			_ = os.Remove(file.Name()) //nolint:gosec
-		}
-	}()
-
-	limitedReader := io.LimitedReader{
-		R: resp.Body,
-		N: m.maxBundleSizeBytes,
-	}
-
-	if _, err = io.Copy(file, &limitedReader); err != nil {
-		return "", fmt.Errorf("failed to save bundle: %w", err)
-	}
-
-	if limitedReader.N <= 0 {
-		return "", fmt.Errorf("bundle exceeds size limit of %d bytes", m.maxBundleSizeBytes)
-	}
-
-	m.logger.Info("bundle downloaded",
-		"url", url,
-	)
-
-	return file.Name(), nil
-}
-
 func (m *Manager) loadManifests() ([]*ExplodedManifest, error) {
 	m.logger.Info("loading manifests")
 
@@ -710,15 +763,32 @@ func (m *Manager) loadManifests() ([]*ExplodedManifest, error) {
 			continue
 		}
 
+		exploded := &ExplodedManifest{
+			Manifest:        &manifest,
+			ExplodedDataDir: dir,
+		}
+
+		// Trusted publishers may have changed since the bundle was exploded.
+		if err = m.verifyExplodedPublishers(exploded); err != nil {
+			m.logger.Warn("removing bundle not signed by trusted publishers",
+				"path", dir,
+				"err", err,
+			)
+			if err = m.removeBundle(dir); err != nil {
+				m.logger.Error("failed to remove bundle",
+					"err", err,
+					"path", dir,
+				)
+			}
+			continue
+		}
+
 		m.logger.Info("manifest loaded",
 			"name", manifest.Name,
 			"hash", manifest.Hash(),
 		)
 
-		manifests = append(manifests, &ExplodedManifest{
-			Manifest:        &manifest,
-			ExplodedDataDir: dir,
-		})
+		manifests = append(manifests, exploded)
 	}
 
 	return manifests, nil
@@ -764,42 +834,31 @@ func (m *Manager) clean() {
 	for runtimeID := range m.runtimeIDs {
 		m.cleanBundles(runtimeID)
 	}
+	m.enforceSizeLimit()
+	m.cleanTemporary()
 }
 
 func (m *Manager) cleanBundles(runtimeID common.Namespace) {
-	maxVersion, ok := func() (version.Version, bool) {
-		m.mu.Lock()
-		defer m.mu.Unlock()
-
-		maxVersion, ok := m.cleanupQueue[runtimeID]
-		if !ok {
-			return version.Version{}, false
-		}
-		delete(m.cleanupQueue, runtimeID)
-		return maxVersion, true
-	}()
+	m.mu.RLock()
+	active, ok := m.activeVersions[runtimeID]
+	m.mu.RUnlock()
 	if !ok {
 		return
 	}
 
+	// Retain the configured number of versions preceding the active version.
+	previous := m.previousBundles(runtimeID, active)
+	if uint64(len(previous)) <= m.keepVersions {
+		return
+	}
+
 	m.logger.Info("cleaning bundles",
 		"id", runtimeID,
-		"max_version", maxVersion,
+		"active_version", active,
+		"keep_versions", m.keepVersions,
 	)
 
-	for _, manifest := range m.store.Manifests() {
-		if manifest.ID != runtimeID {
-			continue
-		}
-
-		ronl, ok := manifest.GetComponentByID(component.ID_RONL)
-		if !ok {
-			continue
-		}
-		if !ronl.Version.Less(maxVersion) {
-			continue
-		}
-
+	for _, manifest := range previous[m.keepVersions:] {
 		m.cleanBundle(manifest)
 	}
 }
@@ -878,6 +937,10 @@ func (m *Manager) explodeBundle(path string, opts ...AddOption) (*ExplodedManife
 	}
 	defer bnd.Close()
 
+	if err = m.verifyPublishers(bnd); err != nil {
+		return nil, fmt.Errorf("failed to verify bundle publishers: %w", err)
+	}
+
 	if options.validator != nil {
 		if err = options.validator(bnd); err != nil {
 			return nil, err
@@ -902,6 +965,26 @@ func (m *Manager) explodeBundle(path string, opts ...AddOption) (*ExplodedManife
 	}, nil
 }
 
+// verifyPublishers verifies that the bundle is signed by trusted publishers, if any are configured
+// for its runtime.
+func (m *Manager) verifyPublishers(bnd *Bundle) error {
+	publishers, ok := m.publishers[bnd.Manifest.ID]
+	if !ok {
+		return nil
+	}
+	return bnd.VerifySignatures(publishers.Keys, publishers.RequiredSignatures())
+}
+
+// verifyExplodedPublishers verifies that the exploded bundle is signed by trusted publishers, if
+// any are configured for its runtime.
+func (m *Manager) verifyExplodedPublishers(manifest *ExplodedManifest) error {
+	publishers, ok := m.publishers[manifest.ID]
+	if !ok {
+		return nil
+	}
+	return manifest.VerifySignatures(publishers.Keys, publishers.RequiredSignatures())
+}
+
 func (m *Manager) registerManifests(manifests []*ExplodedManifest) error {
 	m.logger.Info("registering manifests")
 
@@ -927,6 +1010,10 @@ func (m *Manager) registerManifest(manifest *ExplodedManifest) error {
 		"hash", manifest.Hash(),
 	)
 
+	if err := m.verifyExplodedPublishers(manifest); err != nil {
+		return fmt.Errorf("failed to verify bundle publishers: %w", err)
+	}
+
 	if manifest.Volumes == nil {
 		// No volumes have been configured, attach default volumes.
 		err := m.attachDefaultVolumes(manifest)
@@ -985,10 +1072,30 @@ func (m *Manager) openTemporaryBundleRoot(tmpPath string) (string, *os.Root, err
 }
 
 func validateAndNormalizeURL(rawURL string) (string, error) {
+	// Local directories are accessed via the file scheme.
+	if filepath.IsAbs(rawURL) {
+		localURL := url.URL{
+			Scheme: fileScheme,
+			Path:   filepath.Clean(rawURL),
+		}
+		return localURL.String(), nil
+	}
+
 	parsedURL, err := url.Parse(rawURL)
 	if err != nil {
 		return "", fmt.Errorf("invalid URL '%s': %w", rawURL, err)
 	}
+
+	switch parsedURL.Scheme {
+	case "http", "https":
+	case fileScheme:
+		if parsedURL.Host != "" && parsedURL.Host != "localhost" {
+			return "", fmt.Errorf("invalid URL '%s': remote file URLs are not supported", rawURL)
+		}
+	default:
+		return "", fmt.Errorf("invalid URL '%s': unsupported scheme '%s'", rawURL, parsedURL.Scheme)
+	}
+
 	return parsedURL.String(), nil
 }
 
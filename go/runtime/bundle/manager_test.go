@@ -8,6 +8,9 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
 	"github.com/oasisprotocol/oasis-core/go/runtime/volume"
 )
 
@@ -110,3 +113,117 @@ func TestAddTemporary(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 1, len(store.manifestHashes))
 }
+
+func TestAddTrustedPublishers(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	store := newMockStore()
+	volumeManager := newMockVolumeManager()
+	manager, err := NewManager(tmpDir, nil, store, volumeManager)
+	require.NoError(t, err)
+
+	var runtimeID common.Namespace
+	err = runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	require.NoError(t, err)
+
+	signer := memorySigner.NewTestSigner("bundle manager publisher test")
+	manager.publishers[runtimeID] = &rtConfig.PublishersConfig{
+		Keys: []signature.PublicKey{signer.Public()},
+	}
+
+	bundle := &Bundle{
+		Manifest: &Manifest{
+			Name: "test-runtime",
+			ID:   runtimeID,
+		},
+	}
+	unsignedFn := filepath.Join(tmpDir, "unsigned.orc")
+	err = bundle.Write(unsignedFn)
+	require.NoError(t, err)
+
+	err = manager.Add(unsignedFn)
+	require.ErrorContains(t, err, "failed to verify bundle publishers")
+	require.Empty(t, store.manifestHashes)
+
+	err = bundle.Sign(signer)
+	require.NoError(t, err)
+	signedFn := filepath.Join(tmpDir, "signed.orc")
+	err = bundle.Write(signedFn)
+	require.NoError(t, err)
+
+	err = manager.Add(signedFn)
+	require.NoError(t, err)
+	require.Len(t, store.manifestHashes, 1)
+}
+
+func TestLoadTrustedPublishers(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	var runtimeID common.Namespace
+	err := runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	require.NoError(t, err)
+
+	// Add signed and unsigned bundles while no publishers are configured.
+	manager, err := NewManager(tmpDir, nil, newMockStore(), newMockVolumeManager())
+	require.NoError(t, err)
+
+	signer := memorySigner.NewTestSigner("bundle manager publisher test")
+	unsigned := &Bundle{
+		Manifest: &Manifest{
+			Name: "unsigned-runtime",
+			ID:   runtimeID,
+		},
+	}
+	unsignedFn := filepath.Join(tmpDir, "unsigned.orc")
+	err = unsigned.Write(unsignedFn)
+	require.NoError(t, err)
+
+	signed := &Bundle{
+		Manifest: &Manifest{
+			Name: "signed-runtime",
+			ID:   runtimeID,
+		},
+	}
+	err = signed.Sign(signer)
+	require.NoError(t, err)
+	signedFn := filepath.Join(tmpDir, "signed.orc")
+	err = signed.Write(signedFn)
+	require.NoError(t, err)
+
+	err = manager.Add(unsignedFn)
+	require.NoError(t, err)
+	err = manager.Add(signedFn, WithManifestRewriter(func(m *Manifest) {
+		m.Name = "rewritten-runtime"
+	}))
+	require.NoError(t, err)
+
+	manifests, err := manager.loadManifests()
+	require.NoError(t, err)
+	require.Len(t, manifests, 2)
+
+	// Restart after trusted publishers have been configured.
+	store := newMockStore()
+	manager, err = NewManager(tmpDir, nil, store, newMockVolumeManager())
+	require.NoError(t, err)
+	manager.publishers[runtimeID] = &rtConfig.PublishersConfig{
+		Keys: []signature.PublicKey{signer.Public()},
+	}
+
+	manifests, err = manager.loadManifests()
+	require.NoError(t, err)
+	require.Len(t, manifests, 1, "unsigned bundle should be dropped")
+	require.Equal(t, "rewritten-runtime", manifests[0].Name)
+
+	err = manager.registerManifests(manifests)
+	require.NoError(t, err)
+	require.Len(t, store.manifestHashes, 1)
+
+	// Registering an unsigned bundle should fail.
+	err = manager.registerManifests([]*ExplodedManifest{
+		{
+			Manifest:        unsigned.Manifest,
+			ExplodedDataDir: filepath.Join(tmpDir, "missing"),
+		},
+	})
+	require.ErrorContains(t, err, "failed to verify bundle publishers")
+}
@@ -14,3 +14,9 @@ func ExplodedPath(dataDir string) string {
 func TmpBundlePath(dataDir string) string {
 	return filepath.Join(dataDir, "runtimes", "tmp", "bundles")
 }
+
+// DownloadsPath returns the path under the data directory that contains partially downloaded
+// bundles.
+func DownloadsPath(dataDir string) string {
+	return filepath.Join(dataDir, "runtimes", "downloads", "bundles")
+}
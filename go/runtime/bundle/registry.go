@@ -158,6 +158,20 @@ func (r *Registry) Manifests() []*ExplodedManifest {
 	return slices.Collect(maps.Values(r.manifests))
 }
 
+// ReferencedVolumes returns the identifiers of all volumes attached to known manifests.
+func (r *Registry) ReferencedVolumes() map[string]struct{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	refs := make(map[string]struct{})
+	for _, manifest := range r.manifests {
+		for _, volume := range manifest.Volumes {
+			refs[volume.ID] = struct{}{}
+		}
+	}
+	return refs
+}
+
 // ManifestsWithLabels returns all manifests that have the specified labels set.
 func (r *Registry) ManifestsWithLabels(labels map[string]string) []*ExplodedManifest {
 	r.mu.RLock()
@@ -0,0 +1,299 @@
+package bundle
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+// defaultTemporaryMaxAge is the default maximum amount of time an unfinished temporary bundle
+// upload is kept after it was last written to.
+const defaultTemporaryMaxAge = 24 * time.Hour
+
+// RuntimeBundles are the bundles installed for a runtime.
+type RuntimeBundles struct {
+	// ActiveVersion is the active runtime version, if known.
+	ActiveVersion *version.Version `json:"active_version,omitempty"`
+
+	// Size is the total disk usage of all installed bundles of the runtime in bytes.
+	Size uint64 `json:"size"`
+
+	// Bundles are the installed bundles.
+	Bundles []*InstalledBundle `json:"bundles,omitempty"`
+}
+
+// InstalledBundle is an installed bundle.
+type InstalledBundle struct {
+	// ManifestHash is the hash of the bundle manifest.
+	ManifestHash hash.Hash `json:"manifest_hash"`
+
+	// Name is the optional human readable runtime name.
+	Name string `json:"name,omitempty"`
+
+	// Labels are the labels attached to the bundle.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Detached specifies whether the bundle is detached.
+	Detached bool `json:"detached,omitempty"`
+
+	// Size is the disk usage of the exploded bundle in bytes.
+	Size uint64 `json:"size"`
+
+	// InUse specifies whether the bundle provides the active runtime version or the latest
+	// version of any of its components.
+	InUse bool `json:"in_use"`
+
+	// Components are the components provided by the bundle.
+	Components []*ComponentUsage `json:"components"`
+}
+
+// ComponentUsage is the disk usage of a bundle component.
+type ComponentUsage struct {
+	// ID is the component identifier.
+	ID component.ID `json:"id"`
+
+	// Version is the component version.
+	Version version.Version `json:"version"`
+
+	// Size is the total size of the component files in bytes.
+	Size uint64 `json:"size"`
+}
+
+// Bundles returns the bundles installed for the given runtime together with their disk usage.
+func (m *Manager) Bundles(runtimeID common.Namespace) *RuntimeBundles {
+	m.mu.RLock()
+	active, hasActive := m.activeVersions[runtimeID]
+	m.mu.RUnlock()
+
+	manifests := m.runtimeManifests(runtimeID)
+	latest := latestComponentVersions(manifests)
+
+	var bundles RuntimeBundles
+	if hasActive {
+		bundles.ActiveVersion = &active
+	}
+	for _, manifest := range manifests {
+		bnd := &InstalledBundle{
+			ManifestHash: manifest.Hash(),
+			Name:         manifest.Name,
+			Labels:       manifest.Labels,
+			Detached:     manifest.IsDetached(),
+			Size:         m.diskUsage(manifest.ExplodedDataDir),
+		}
+
+		for _, comp := range manifest.Components {
+			usage := &ComponentUsage{
+				ID:      comp.ID(),
+				Version: comp.Version,
+			}
+			for _, fn := range comp.Files() {
+				usage.Size += m.diskUsage(filepath.Join(manifest.ExplodedDataDir, fn))
+			}
+			bnd.Components = append(bnd.Components, usage)
+
+			switch comp.ID().IsRONL() {
+			case true:
+				bnd.InUse = bnd.InUse || !hasActive || comp.Version == active
+			case false:
+				bnd.InUse = bnd.InUse || comp.Version == latest[comp.ID()]
+			}
+		}
+
+		bundles.Size += bnd.Size
+		bundles.Bundles = append(bundles.Bundles, bnd)
+	}
+
+	slices.SortFunc(bundles.Bundles, func(a, b *InstalledBundle) int {
+		if a.Detached != b.Detached {
+			if a.Detached {
+				return 1
+			}
+			return -1
+		}
+		if c := strings.Compare(a.Components[0].ID.String(), b.Components[0].ID.String()); c != 0 {
+			return c
+		}
+		return b.Components[0].Version.Cmp(a.Components[0].Version)
+	})
+
+	return &bundles
+}
+
+func (m *Manager) runtimeManifests(runtimeID common.Namespace) []*ExplodedManifest {
+	var manifests []*ExplodedManifest
+	for _, manifest := range m.store.Manifests() {
+		if manifest.ID != runtimeID || len(manifest.Components) == 0 {
+			continue
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests
+}
+
+// previousBundles returns non-detached bundles of the given runtime whose version precedes
+// the active version, newest first.
+func (m *Manager) previousBundles(runtimeID common.Namespace, active version.Version) []*ExplodedManifest {
+	var manifests []*ExplodedManifest
+	for _, manifest := range m.runtimeManifests(runtimeID) {
+		ronl, ok := manifest.GetComponentByID(component.ID_RONL)
+		if !ok {
+			continue
+		}
+		if !ronl.Version.Less(active) {
+			continue
+		}
+		manifests = append(manifests, manifest)
+	}
+
+	slices.SortFunc(manifests, func(a, b *ExplodedManifest) int {
+		return ronlVersion(b).Cmp(ronlVersion(a))
+	})
+
+	return manifests
+}
+
+// enforceSizeLimit removes bundles of versions preceding the active versions, oldest first,
+// until the total size of all installed bundles is within the configured limit.
+func (m *Manager) enforceSizeLimit() {
+	if m.maxTotalSize == 0 {
+		return
+	}
+
+	total := m.diskUsage(m.bundleDir)
+	if total <= m.maxTotalSize {
+		return
+	}
+
+	m.mu.RLock()
+	activeVersions := make(map[common.Namespace]version.Version)
+	for runtimeID, active := range m.activeVersions {
+		activeVersions[runtimeID] = active
+	}
+	m.mu.RUnlock()
+
+	var candidates []*ExplodedManifest
+	for runtimeID, active := range activeVersions {
+		candidates = append(candidates, m.previousBundles(runtimeID, active)...)
+	}
+	slices.SortStableFunc(candidates, func(a, b *ExplodedManifest) int {
+		return ronlVersion(a).Cmp(ronlVersion(b))
+	})
+
+	for _, manifest := range candidates {
+		if total <= m.maxTotalSize {
+			break
+		}
+
+		size := m.diskUsage(manifest.ExplodedDataDir)
+		m.cleanBundle(manifest)
+		total -= min(size, total)
+	}
+
+	if total > m.maxTotalSize {
+		m.logger.Warn("installed bundles exceed maximum total size",
+			"size", total,
+			"max_size", m.maxTotalSize,
+		)
+	}
+}
+
+// cleanTemporary removes unfinished temporary bundle uploads that have not been written to for
+// longer than the configured maximum age.
+func (m *Manager) cleanTemporary() {
+	cutoff := time.Now().Add(-m.temporaryMaxAge)
+
+	entries, err := os.ReadDir(m.tmpBundleDir)
+	if err != nil {
+		m.logger.Error("failed to read temporary bundle directory",
+			"err", err,
+		)
+		return
+	}
+
+	for _, entry := range entries {
+		dir := filepath.Join(m.tmpBundleDir, entry.Name())
+		if !entry.IsDir() {
+			continue
+		}
+
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			fi, err := file.Info()
+			if err != nil || !fi.ModTime().Before(cutoff) {
+				continue
+			}
+
+			fn := filepath.Join(dir, file.Name())
+			m.logger.Info("removing stale temporary bundle",
+				"path", fn,
+			)
+			if err = os.RemoveAll(fn); err != nil {
+				m.logger.Error("failed to remove stale temporary bundle",
+					"err", err,
+					"path", fn,
+				)
+			}
+		}
+
+		// Remove the directory in case it is now empty.
+		_ = os.Remove(dir)
+	}
+}
+
+// diskUsage returns the disk usage of the given file or directory in bytes.
+func (m *Manager) diskUsage(path string) uint64 {
+	var size uint64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += uint64(fi.Size())
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		m.logger.Warn("failed to determine disk usage",
+			"err", err,
+			"path", path,
+		)
+	}
+	return size
+}
+
+// latestComponentVersions returns the latest version of each component provided by the given
+// manifests.
+func latestComponentVersions(manifests []*ExplodedManifest) map[component.ID]version.Version {
+	latest := make(map[component.ID]version.Version)
+	for _, manifest := range manifests {
+		for _, comp := range manifest.Components {
+			if v, ok := latest[comp.ID()]; ok && !v.Less(comp.Version) {
+				continue
+			}
+			latest[comp.ID()] = comp.Version
+		}
+	}
+	return latest
+}
+
+func ronlVersion(manifest *ExplodedManifest) version.Version {
+	ronl, _ := manifest.GetComponentByID(component.ID_RONL)
+	return ronl.Version
+}
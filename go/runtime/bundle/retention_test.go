@@ -0,0 +1,155 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+func addTestBundle(t *testing.T, manager *Manager, runtimeID common.Namespace, comp *Component, size int) *ExplodedManifest {
+	manifest := &ExplodedManifest{
+		Manifest: &Manifest{
+			Name:       "test-runtime",
+			ID:         runtimeID,
+			Components: []*Component{comp},
+		},
+	}
+	manifest.ExplodedDataDir = filepath.Join(manager.bundleDir, manifest.Hash().String())
+
+	err := common.Mkdir(manifest.ExplodedDataDir)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(manifest.ExplodedDataDir, comp.Executable), make([]byte, size), 0o600)
+	require.NoError(t, err)
+	err = manager.store.AddManifest(manifest)
+	require.NoError(t, err)
+
+	return manifest
+}
+
+func newRetentionTestManager(t *testing.T) (*Manager, common.Namespace) {
+	manager, err := NewManager(t.TempDir(), nil, NewRegistry(), newMockVolumeManager())
+	require.NoError(t, err)
+
+	var runtimeID common.Namespace
+	err = runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	require.NoError(t, err)
+	manager.runtimeIDs[runtimeID] = struct{}{}
+
+	return manager, runtimeID
+}
+
+func TestBundles(t *testing.T) {
+	require := require.New(t)
+
+	manager, runtimeID := newRetentionTestManager(t)
+
+	for i := range uint16(3) {
+		addTestBundle(t, manager, runtimeID, &Component{
+			Kind:       component.RONL,
+			Version:    version.Version{Major: 1, Minor: i},
+			Executable: "runtime.bin",
+		}, 100)
+	}
+	for i := range uint16(2) {
+		addTestBundle(t, manager, runtimeID, &Component{
+			Kind:       component.ROFL,
+			Name:       "app",
+			Version:    version.Version{Major: 0, Minor: i},
+			Executable: "app.bin",
+		}, 50)
+	}
+
+	// Without a known active version all RONL versions are considered in use.
+	bundles := manager.Bundles(runtimeID)
+	require.Nil(bundles.ActiveVersion)
+	require.EqualValues(400, bundles.Size)
+	require.Len(bundles.Bundles, 5)
+	for _, bnd := range bundles.Bundles[:3] {
+		require.True(bnd.InUse)
+	}
+
+	manager.Cleanup(runtimeID, version.Version{Major: 1, Minor: 1})
+	bundles = manager.Bundles(runtimeID)
+	require.Equal(&version.Version{Major: 1, Minor: 1}, bundles.ActiveVersion)
+
+	var inUse []string
+	for _, bnd := range bundles.Bundles {
+		require.Len(bnd.Components, 1)
+		comp := bnd.Components[0]
+		require.Equal(bnd.Size, comp.Size)
+		if bnd.InUse {
+			inUse = append(inUse, fmt.Sprintf("%s@%s", comp.ID, comp.Version))
+		}
+	}
+	require.Equal([]string{"ronl@1.1.0", "rofl (app)@0.1.0"}, inUse)
+}
+
+func TestCleanBundles(t *testing.T) {
+	require := require.New(t)
+
+	manager, runtimeID := newRetentionTestManager(t)
+	manager.keepVersions = 1
+
+	var manifests []*ExplodedManifest
+	for i := range uint16(4) {
+		manifests = append(manifests, addTestBundle(t, manager, runtimeID, &Component{
+			Kind:       component.RONL,
+			Version:    version.Version{Major: 1, Minor: i},
+			Executable: "runtime.bin",
+		}, 100))
+	}
+
+	manager.Cleanup(runtimeID, version.Version{Major: 1, Minor: 2})
+	manager.cleanBundles(runtimeID)
+
+	// Only the active version, one previous version and the upcoming version should be retained.
+	require.ElementsMatch(manifests[1:], manager.store.Manifests())
+	_, err := os.Stat(manifests[0].ExplodedDataDir)
+	require.ErrorIs(err, os.ErrNotExist)
+
+	// Enforcing the size limit should only remove previous versions.
+	manager.maxTotalSize = 100
+	manager.enforceSizeLimit()
+	require.ElementsMatch(manifests[2:], manager.store.Manifests())
+	_, err = os.Stat(manifests[1].ExplodedDataDir)
+	require.ErrorIs(err, os.ErrNotExist)
+}
+
+func TestCleanTemporary(t *testing.T) {
+	require := require.New(t)
+
+	manager, _ := newRetentionTestManager(t)
+	manager.temporaryMaxAge = time.Hour
+
+	staleDir := filepath.Join(manager.tmpBundleDir, "stale")
+	err := common.Mkdir(staleDir)
+	require.NoError(err)
+	staleFn := filepath.Join(staleDir, "bundle.orc")
+	err = os.WriteFile(staleFn, []byte("stale"), 0o600)
+	require.NoError(err)
+	modTime := time.Now().Add(-2 * time.Hour)
+	err = os.Chtimes(staleFn, modTime, modTime)
+	require.NoError(err)
+
+	freshDir := filepath.Join(manager.tmpBundleDir, "fresh")
+	err = common.Mkdir(freshDir)
+	require.NoError(err)
+	freshFn := filepath.Join(freshDir, "bundle.orc")
+	err = os.WriteFile(freshFn, []byte("fresh"), 0o600)
+	require.NoError(err)
+
+	manager.cleanTemporary()
+
+	_, err = os.Stat(staleDir)
+	require.ErrorIs(err, os.ErrNotExist)
+	_, err = os.Stat(freshFn)
+	require.NoError(err)
+}
@@ -0,0 +1,202 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+)
+
+const (
+	// signaturesName is the name of the file containing the detached manifest signatures.
+	signaturesName = manifestPath + "/SIGNATURES"
+	// signedManifestName is the name of the file containing the original signed manifest in case
+	// the manifest has been rewritten by the node. Bundles must not include it.
+	signedManifestName = manifestPath + "/MANIFEST.signed"
+)
+
+// ManifestSignatureContext is the signature context used for signing bundle manifests.
+var ManifestSignatureContext = signature.NewContext("oasis-core/runtime: bundle manifest")
+
+// Signatures returns the detached manifest signatures included in the bundle.
+func (bnd *Bundle) Signatures() ([]signature.Signature, error) {
+	d, ok := bnd.Data[signaturesName]
+	if !ok {
+		return nil, nil
+	}
+	b, err := ReadAllData(d)
+	if err != nil {
+		return nil, fmt.Errorf("runtime/bundle: failed to read signatures: %w", err)
+	}
+	return parseSignatures(b)
+}
+
+func parseSignatures(b []byte) ([]signature.Signature, error) {
+	var sigs []signature.Signature
+	if err := json.Unmarshal(b, &sigs); err != nil {
+		return nil, fmt.Errorf("runtime/bundle: failed to parse signatures: %w", err)
+	}
+	return sigs, nil
+}
+
+// Sign adds a detached signature over the current manifest to the bundle, replacing any existing
+// signature made by the same signer.
+//
+// Any later modification of the manifest invalidates the signature.
+func (bnd *Bundle) Sign(signer signature.Signer) error {
+	sigs, err := bnd.Signatures()
+	if err != nil {
+		return err
+	}
+
+	manifestHash := bnd.Manifest.Hash()
+	sig, err := signature.Sign(signer, ManifestSignatureContext, manifestHash[:])
+	if err != nil {
+		return fmt.Errorf("runtime/bundle: failed to sign manifest: %w", err)
+	}
+
+	newSigs := make([]signature.Signature, 0, len(sigs)+1)
+	for _, s := range sigs {
+		if s.PublicKey.Equal(sig.PublicKey) {
+			continue
+		}
+		newSigs = append(newSigs, s)
+	}
+	newSigs = append(newSigs, *sig)
+
+	b, err := json.Marshal(newSigs)
+	if err != nil {
+		return fmt.Errorf("runtime/bundle: failed to serialize signatures: %w", err)
+	}
+	if bnd.Data == nil {
+		bnd.Data = make(map[string]Data)
+	}
+	bnd.Data[signaturesName] = NewBytesData(b)
+
+	return nil
+}
+
+// VerifySignatures verifies that the manifest has valid signatures from at least threshold
+// distinct publishers out of the given set of trusted publishers.
+//
+// In case the manifest has been rewritten, the signatures are verified against the original
+// signed manifest instead, which must only differ from the rewritten manifest in the fields that
+// are rewritten by the node. Signatures made by other keys are ignored.
+func (bnd *Bundle) VerifySignatures(publishers []signature.PublicKey, threshold uint64) error {
+	sigs, err := bnd.Signatures()
+	if err != nil {
+		return err
+	}
+
+	signed := bnd.Manifest
+	if d, ok := bnd.Data[signedManifestName]; ok {
+		b, err := ReadAllData(d)
+		if err != nil {
+			return fmt.Errorf("runtime/bundle: failed to read signed manifest: %w", err)
+		}
+		if signed, err = parseSignedManifest(bnd.Manifest, b); err != nil {
+			return err
+		}
+	}
+
+	return verifyManifestSignatures(signed, sigs, publishers, threshold)
+}
+
+// VerifySignatures verifies that the exploded manifest has valid signatures from at least
+// threshold distinct publishers out of the given set of trusted publishers.
+//
+// The detached signatures and the original signed manifest (in case the manifest has been
+// rewritten) are read from the exploded bundle directory.
+func (m *ExplodedManifest) VerifySignatures(publishers []signature.PublicKey, threshold uint64) error {
+	var sigs []signature.Signature
+	b, err := os.ReadFile(filepath.Join(m.ExplodedDataDir, signaturesName))
+	switch {
+	case err == nil:
+		if sigs, err = parseSignatures(b); err != nil {
+			return err
+		}
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		return fmt.Errorf("runtime/bundle: failed to read signatures: %w", err)
+	}
+
+	signed := m.Manifest
+	b, err = os.ReadFile(filepath.Join(m.ExplodedDataDir, signedManifestName))
+	switch {
+	case err == nil:
+		if signed, err = parseSignedManifest(m.Manifest, b); err != nil {
+			return err
+		}
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		return fmt.Errorf("runtime/bundle: failed to read signed manifest: %w", err)
+	}
+
+	return verifyManifestSignatures(signed, sigs, publishers, threshold)
+}
+
+// parseSignedManifest parses the original signed manifest of the given rewritten manifest.
+//
+// The original manifest is only trusted in case the rewritten manifest differs from it solely in
+// the fields that are rewritten by the node itself, as otherwise signatures copied from another
+// bundle could be used to vouch for arbitrary bundle contents.
+func parseSignedManifest(manifest *Manifest, b []byte) (*Manifest, error) {
+	var signed Manifest
+	if err := json.Unmarshal(b, &signed); err != nil {
+		return nil, fmt.Errorf("runtime/bundle: failed to parse signed manifest: %w", err)
+	}
+	// Rewriting must not change the runtime the bundle belongs to.
+	if !signed.ID.Equal(&manifest.ID) {
+		return nil, fmt.Errorf("runtime/bundle: signed manifest runtime ID mismatch (got: %s, expected: %s)", signed.ID, manifest.ID)
+	}
+	signedHash, manifestHash := rewritableFieldsCleared(&signed), rewritableFieldsCleared(manifest)
+	if !signedHash.Equal(&manifestHash) {
+		return nil, fmt.Errorf("runtime/bundle: manifest does not match the signed manifest")
+	}
+	return &signed, nil
+}
+
+// rewritableFieldsCleared returns the hash of the given manifest with all fields that may be
+// rewritten by the node (runtime and component names and versions) cleared.
+func rewritableFieldsCleared(manifest *Manifest) hash.Hash {
+	raw, _ := json.Marshal(manifest)
+	var m Manifest
+	_ = json.Unmarshal(raw, &m)
+
+	m.Name = ""
+	m.Version = version.Version{}
+	for _, comp := range m.Components {
+		comp.Name = ""
+		comp.Version = version.Version{}
+	}
+	return hash.NewFrom(&m)
+}
+
+func verifyManifestSignatures(manifest *Manifest, sigs []signature.Signature, publishers []signature.PublicKey, threshold uint64) error {
+	trusted := make(map[signature.PublicKey]struct{}, len(publishers))
+	for _, pk := range publishers {
+		trusted[pk] = struct{}{}
+	}
+
+	manifestHash := manifest.Hash()
+	signers := make(map[signature.PublicKey]struct{})
+	for _, sig := range sigs {
+		if _, ok := trusted[sig.PublicKey]; !ok {
+			continue
+		}
+		if !sig.Verify(ManifestSignatureContext, manifestHash[:]) {
+			return fmt.Errorf("runtime/bundle: invalid manifest signature by publisher %s", sig.PublicKey)
+		}
+		signers[sig.PublicKey] = struct{}{}
+	}
+
+	if uint64(len(signers)) < threshold {
+		return fmt.Errorf("runtime/bundle: manifest signed by %d trusted publishers, %d required", len(signers), threshold)
+	}
+	return nil
+}
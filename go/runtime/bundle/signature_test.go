@@ -0,0 +1,125 @@
+package bundle
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+func TestBundleSignatures(t *testing.T) {
+	require := require.New(t)
+
+	signer1 := memorySigner.NewTestSigner("bundle signature test 1")
+	signer2 := memorySigner.NewTestSigner("bundle signature test 2")
+	signer3 := memorySigner.NewTestSigner("bundle signature test 3")
+	publishers := []signature.PublicKey{signer1.Public(), signer2.Public()}
+
+	bundle := &Bundle{
+		Manifest: &Manifest{
+			Name: "test-runtime",
+			Components: []*Component{
+				{
+					Kind: component.RONL,
+					ELF: &ELFMetadata{
+						Executable: "runtime.bin",
+					},
+				},
+			},
+		},
+	}
+	err := bundle.Add("runtime.bin", NewBytesData(randBuffer(1024)))
+	require.NoError(err, "bundle.Add")
+
+	// Unsigned bundles.
+	require.NoError(bundle.VerifySignatures(publishers, 0))
+	require.ErrorContains(bundle.VerifySignatures(publishers, 1), "manifest signed by 0 trusted publishers, 1 required")
+
+	// Signatures by untrusted publishers should be ignored.
+	require.NoError(bundle.Sign(signer3))
+	require.ErrorContains(bundle.VerifySignatures(publishers, 1), "manifest signed by 0 trusted publishers, 1 required")
+
+	// Signing twice with the same key should not count twice.
+	require.NoError(bundle.Sign(signer1))
+	require.NoError(bundle.Sign(signer1))
+	require.NoError(bundle.VerifySignatures(publishers, 1))
+	require.ErrorContains(bundle.VerifySignatures(publishers, 2), "manifest signed by 1 trusted publishers, 2 required")
+
+	require.NoError(bundle.Sign(signer2))
+	require.NoError(bundle.VerifySignatures(publishers, 2))
+
+	sigs, err := bundle.Signatures()
+	require.NoError(err)
+	require.Len(sigs, 3)
+
+	// Signatures should survive a round trip.
+	bundleFn := filepath.Join(t.TempDir(), "bundle.orc")
+	err = bundle.Write(bundleFn)
+	require.NoError(err, "bundle.Write")
+
+	bundle2, err := Open(bundleFn)
+	require.NoError(err, "Open")
+	defer bundle2.Close()
+	require.NoError(bundle2.VerifySignatures(publishers, 2))
+
+	// Modifying the manifest should invalidate the signatures.
+	bundle.Manifest.Name = "test-modified"
+	require.ErrorContains(bundle.VerifySignatures(publishers, 1), "invalid manifest signature")
+
+	// Rewriting the manifest should keep the signatures verifiable against the original manifest.
+	bundle2.Rewrite(func(m *Manifest) {
+		m.Name = "test-rewritten"
+	})
+	require.NoError(bundle2.VerifySignatures(publishers, 2))
+
+	dir := filepath.Join(t.TempDir(), "exploded")
+	require.NoError(bundle2.WriteExploded(dir), "WriteExploded")
+	exploded := &ExplodedManifest{
+		Manifest:        bundle2.Manifest,
+		ExplodedDataDir: dir,
+	}
+	require.NoError(exploded.VerifySignatures(publishers, 2))
+
+	// Rewriting the runtime ID should invalidate the signatures.
+	exploded.Manifest.ID[0] ^= 0xff
+	require.ErrorContains(exploded.VerifySignatures(publishers, 1), "signed manifest runtime ID mismatch")
+	exploded.Manifest.ID[0] ^= 0xff
+
+	// Signatures copied together with the signed manifest into a bundle with different contents
+	// should be rejected.
+	forged := &Bundle{
+		Manifest: &Manifest{
+			Components: []*Component{
+				{
+					Kind: component.RONL,
+					ELF: &ELFMetadata{
+						Executable: "runtime.bin",
+					},
+				},
+			},
+		},
+	}
+	err = forged.Add("runtime.bin", NewBytesData(randBuffer(1)))
+	require.NoError(err, "bundle.Add")
+	forged.Data[signaturesName] = bundle2.Data[signaturesName]
+	forged.Data[signedManifestName] = bundle2.Data[signedManifestName]
+	require.ErrorContains(forged.VerifySignatures(publishers, 1), "manifest does not match the signed manifest")
+
+	forgedFn := filepath.Join(t.TempDir(), "forged.orc")
+	err = forged.Write(forgedFn)
+	require.NoError(err, "bundle.Write")
+	_, err = Open(forgedFn)
+	require.ErrorContains(err, "unexpected signed manifest")
+
+	forgedDir := filepath.Join(t.TempDir(), "forged")
+	require.NoError(forged.WriteExploded(forgedDir), "WriteExploded")
+	forgedExploded := &ExplodedManifest{
+		Manifest:        forged.Manifest,
+		ExplodedDataDir: forgedDir,
+	}
+	require.ErrorContains(forgedExploded.VerifySignatures(publishers, 1), "manifest does not match the signed manifest")
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
@@ -84,6 +85,19 @@ type RuntimeClient interface {
 	// Query makes a runtime-specific query.
 	Query(ctx context.Context, request *QueryRequest) (*QueryResponse, error)
 
+	// GetTransactionStatus returns the current status of a transaction with the given hash.
+	//
+	// Transactions submitted via this node are tracked through their whole lifecycle while for
+	// other transactions only their presence in the local transaction pool or their inclusion in
+	// one of the most recent blocks can be reported.
+	GetTransactionStatus(ctx context.Context, request *GetTransactionStatusRequest) (*TransactionStatus, error)
+
+	// WatchTransactionStatus subscribes to status updates of a transaction with the given hash.
+	//
+	// The current status is sent first and the subscription is closed once the transaction reaches
+	// a terminal state (included, expired or evicted).
+	WatchTransactionStatus(ctx context.Context, request *GetTransactionStatusRequest) (<-chan *TransactionStatus, pubsub.ClosableSubscription, error)
+
 	// WatchBlocks subscribes to blocks for a specific runtimes.
 	WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error)
 
@@ -180,3 +194,125 @@ type QueryRequest struct {
 type QueryResponse struct {
 	Data []byte `json:"data"`
 }
+
+// GetTransactionStatusRequest is a GetTransactionStatus/WatchTransactionStatus request.
+type GetTransactionStatusRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	TxHash    hash.Hash        `json:"tx_hash"`
+}
+
+// TransactionState is the lifecycle state of a transaction.
+type TransactionState uint8
+
+const (
+	// TransactionStatePending is the state of a transaction that has been submitted but not yet
+	// checked.
+	TransactionStatePending TransactionState = 0
+	// TransactionStateChecked is the state of a transaction that has passed the transaction check.
+	TransactionStateChecked TransactionState = 1
+	// TransactionStateQueued is the state of a transaction that is queued for scheduling in the
+	// transaction pool.
+	TransactionStateQueued TransactionState = 2
+	// TransactionStateScheduled is the state of a transaction that has been scheduled for
+	// execution in a round.
+	//
+	// This state is only reported by nodes that schedule transactions themselves. Client nodes
+	// never schedule transactions, so there transactions move from queued directly to included.
+	TransactionStateScheduled TransactionState = 3
+	// TransactionStateIncluded is the state of a transaction that has been included in a block.
+	TransactionStateIncluded TransactionState = 4
+	// TransactionStateExpired is the state of a transaction that can no longer be included in a
+	// block.
+	TransactionStateExpired TransactionState = 5
+	// TransactionStateEvicted is the state of a transaction that has been removed from the
+	// transaction pool without being included in a block.
+	TransactionStateEvicted TransactionState = 6
+)
+
+// String returns a string representation of a transaction state.
+func (s TransactionState) String() string {
+	switch s {
+	case TransactionStatePending:
+		return "pending"
+	case TransactionStateChecked:
+		return "checked"
+	case TransactionStateQueued:
+		return "queued"
+	case TransactionStateScheduled:
+		return "scheduled"
+	case TransactionStateIncluded:
+		return "included"
+	case TransactionStateExpired:
+		return "expired"
+	case TransactionStateEvicted:
+		return "evicted"
+	default:
+		return "[invalid transaction state]"
+	}
+}
+
+// IsTerminal returns true iff the transaction state is final.
+func (s TransactionState) IsTerminal() bool {
+	switch s {
+	case TransactionStateIncluded, TransactionStateExpired, TransactionStateEvicted:
+		return true
+	default:
+		return false
+	}
+}
+
+// MarshalText encodes a TransactionState into text form.
+func (s TransactionState) MarshalText() ([]byte, error) {
+	switch s {
+	case TransactionStatePending,
+		TransactionStateChecked,
+		TransactionStateQueued,
+		TransactionStateScheduled,
+		TransactionStateIncluded,
+		TransactionStateExpired,
+		TransactionStateEvicted:
+		return []byte(s.String()), nil
+	default:
+		return nil, fmt.Errorf("invalid TransactionState: %d", s)
+	}
+}
+
+// UnmarshalText decodes a text slice into a TransactionState.
+func (s *TransactionState) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case TransactionStatePending.String():
+		*s = TransactionStatePending
+	case TransactionStateChecked.String():
+		*s = TransactionStateChecked
+	case TransactionStateQueued.String():
+		*s = TransactionStateQueued
+	case TransactionStateScheduled.String():
+		*s = TransactionStateScheduled
+	case TransactionStateIncluded.String():
+		*s = TransactionStateIncluded
+	case TransactionStateExpired.String():
+		*s = TransactionStateExpired
+	case TransactionStateEvicted.String():
+		*s = TransactionStateEvicted
+	default:
+		return fmt.Errorf("invalid TransactionState: %s", string(text))
+	}
+	return nil
+}
+
+// TransactionStatus is the status of a transaction.
+type TransactionStatus struct {
+	// Hash is the transaction hash.
+	Hash hash.Hash `json:"hash"`
+	// State is the current transaction state.
+	State TransactionState `json:"state"`
+	// Round is the round the transaction has been scheduled for or the round in which the
+	// transaction has been included.
+	Round uint64 `json:"round,omitempty"`
+	// BatchOrder is the order of the transaction in the execution batch (included only).
+	BatchOrder uint32 `json:"batch_order,omitempty"`
+	// Output is the transaction output (included only).
+	Output []byte `json:"output,omitempty"`
+	// Reason is the reason for the transaction being expired or evicted.
+	Reason string `json:"reason,omitempty"`
+}
@@ -42,6 +42,8 @@ var (
 	methodGetUnconfirmedTransactions = serviceName.NewMethod("GetUnconfirmedTransactions", common.Namespace{})
 	// methodGetEvents is the GetEvents method.
 	methodGetEvents = serviceName.NewMethod("GetEvents", GetEventsRequest{})
+	// methodGetTransactionStatus is the GetTransactionStatus method.
+	methodGetTransactionStatus = serviceName.NewMethod("GetTransactionStatus", GetTransactionStatusRequest{})
 	// methodQuery is the Query method.
 	methodQuery = serviceName.NewMethod("Query", QueryRequest{})
 	// methodStateSyncGet is the StateSyncGet method.
@@ -53,6 +55,8 @@ var (
 
 	// methodWatchBlocks is the WatchBlocks method.
 	methodWatchBlocks = serviceName.NewMethod("WatchBlocks", common.Namespace{})
+	// methodWatchTransactionStatus is the WatchTransactionStatus method.
+	methodWatchTransactionStatus = serviceName.NewMethod("WatchTransactionStatus", GetTransactionStatusRequest{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -103,6 +107,10 @@ var (
 				MethodName: methodGetEvents.ShortName(),
 				Handler:    handlerGetEvents,
 			},
+			{
+				MethodName: methodGetTransactionStatus.ShortName(),
+				Handler:    handlerGetTransactionStatus,
+			},
 			{
 				MethodName: methodQuery.ShortName(),
 				Handler:    handlerQuery,
@@ -126,6 +134,11 @@ var (
 				Handler:       handlerWatchBlocks,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodWatchTransactionStatus.ShortName(),
+				Handler:       handlerWatchTransactionStatus,
+				ServerStreams: true,
+			},
 		},
 	}
 )
@@ -416,6 +429,31 @@ func handlerGetEvents(
 	return interceptor(ctx, &rq, info, handler)
 }
 
+func handlerGetTransactionStatus(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var rq GetTransactionStatusRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		rsp, err := srv.(RuntimeClient).GetTransactionStatus(ctx, &rq)
+		return rsp, errorWrapNotFound(err)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetTransactionStatus.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		rsp, err := srv.(RuntimeClient).GetTransactionStatus(ctx, req.(*GetTransactionStatusRequest))
+		return rsp, errorWrapNotFound(err)
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
 func handlerQuery( // nolint: revive
 	srv any,
 	ctx context.Context,
@@ -539,6 +577,35 @@ func handlerWatchBlocks(srv any, stream grpc.ServerStream) error {
 	}
 }
 
+func handlerWatchTransactionStatus(srv any, stream grpc.ServerStream) error {
+	var rq GetTransactionStatusRequest
+	if err := stream.RecvMsg(&rq); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(RuntimeClient).WatchTransactionStatus(ctx, &rq)
+	if err != nil {
+		return errorWrapNotFound(err)
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case st, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(st); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // RegisterService registers a new runtime client service with the given gRPC server.
 func RegisterService(server *grpc.Server, service RuntimeClient) {
 	server.RegisterService(&serviceDesc, service)
@@ -644,6 +711,14 @@ func (c *Client) Query(ctx context.Context, request *QueryRequest) (*QueryRespon
 	return &rsp, nil
 }
 
+func (c *Client) GetTransactionStatus(ctx context.Context, request *GetTransactionStatusRequest) (*TransactionStatus, error) {
+	var rsp TransactionStatus
+	if err := c.conn.Invoke(ctx, methodGetTransactionStatus.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 type stateReadSync struct {
 	c *Client
 }
@@ -713,3 +788,38 @@ func (c *Client) WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<
 
 	return ch, sub, nil
 }
+
+func (c *Client) WatchTransactionStatus(ctx context.Context, request *GetTransactionStatusRequest) (<-chan *TransactionStatus, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[1], methodWatchTransactionStatus.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(request); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *TransactionStatus)
+	go func() {
+		defer close(ch)
+
+		for {
+			var st TransactionStatus
+			if serr := stream.RecvMsg(&st); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &st:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
@@ -4,12 +4,14 @@ package config
 import (
 	"fmt"
 	"net"
+	"net/url"
 	"slices"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 	tpConfig "github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
 )
@@ -101,6 +103,13 @@ type Config struct {
 	// Path to the sandbox binary (bubblewrap).
 	SandboxBinary string `yaml:"sandbox_binary,omitempty"`
 
+	// CgroupRoot is the path to the cgroup v2 directory under which sandboxed runtime components
+	// with resource limits are placed (e.g. /sys/fs/cgroup/oasis-runtimes).
+	//
+	// The directory must be delegated to the user running the node, must have the memory, cpu and
+	// pids controllers enabled by its parent and must not contain any processes itself.
+	CgroupRoot string `yaml:"cgroup_root,omitempty"`
+
 	// Path to SGX runtime loader binary (for SGX runtimes).
 	// NOTE: This may go away in the future, use `SGX.Loader` instead.
 	SGXLoader string `yaml:"sgx_loader,omitempty"`
@@ -134,6 +143,15 @@ type Config struct {
 	// a default will be used.
 	AttestInterval time.Duration `yaml:"attest_interval,omitempty"`
 
+	// Restart is the restart policy configuration for sandboxed runtimes.
+	Restart RestartConfig `yaml:"restart,omitempty"`
+
+	// Trace is the Runtime Host Protocol message tracing configuration.
+	Trace TraceConfig `yaml:"trace,omitempty"`
+
+	// Volumes is the persistent volume configuration.
+	Volumes VolumesConfig `yaml:"volumes,omitempty"`
+
 	// LoadBalancer is the load balancer configuration.
 	LoadBalancer LoadBalancerConfig `yaml:"load_balancer,omitempty"`
 
@@ -149,6 +167,12 @@ type Config struct {
 	// If not specified, a default value is used.
 	MaxBundleSize string `yaml:"max_bundle_size,omitempty"`
 
+	// Download is the bundle download configuration.
+	Download DownloadConfig `yaml:"download,omitempty"`
+
+	// Retention is the bundle retention policy.
+	Retention RetentionConfig `yaml:"retention,omitempty"`
+
 	// DebugMockTEE enables mocking of the Trusted Execution Environment (TEE).
 	//
 	// This flag can only be used if the DebugDontBlameOasis flag is set.
@@ -195,6 +219,26 @@ func (c *Config) GetLocalConfig(runtimeID common.Namespace) map[string]any {
 	return c.RuntimeConfig[runtimeID.String()]
 }
 
+// GetTxPoolConfig returns the transaction pool configuration for the given runtime.
+func (c *Config) GetTxPoolConfig(runtimeID common.Namespace) tpConfig.Config {
+	for _, rt := range c.Runtimes {
+		if rt.ID == runtimeID && rt.TxPool != nil {
+			return rt.TxPool.Apply(c.TxPool)
+		}
+	}
+	return c.TxPool
+}
+
+// GetQueryCacheConfig returns the query result cache configuration for the given runtime.
+func (c *Config) GetQueryCacheConfig(runtimeID common.Namespace) QueryCacheConfig {
+	for _, rt := range c.Runtimes {
+		if rt.ID == runtimeID {
+			return rt.QueryCache
+		}
+	}
+	return QueryCacheConfig{}
+}
+
 // SgxConfig is configuration specific to Intel SGX.
 type SgxConfig struct {
 	// Loader is the path to the SGX runtime loader binary.
@@ -213,6 +257,9 @@ type TdxConfig struct {
 type LogConfig struct {
 	// MaxLogSize is the maximum log size in bytes.
 	MaxLogSize int `yaml:"max_log_size,omitempty"`
+
+	// Exports is the list of sinks to which runtime component logs are forwarded.
+	Exports []LogExportConfig `yaml:"exports,omitempty"`
 }
 
 // Validate validates the log configuration for correctness.
@@ -220,6 +267,133 @@ func (l *LogConfig) Validate() error {
 	if l.MaxLogSize < 1024 {
 		return fmt.Errorf("maximum log size must be at least 1024 bytes")
 	}
+	for i, export := range l.Exports {
+		if err := export.Validate(); err != nil {
+			return fmt.Errorf("log export %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// LogExportKind is the kind of a log export sink.
+type LogExportKind string
+
+const (
+	// LogExportKindSyslog is a sink that forwards log entries to a syslog daemon.
+	LogExportKindSyslog LogExportKind = "syslog"
+
+	// LogExportKindUnix is a sink that writes newline-delimited JSON log entries to a Unix socket.
+	LogExportKindUnix LogExportKind = "unix"
+
+	// LogExportKindHTTP is a sink that pushes batches of log entries to an HTTP endpoint.
+	LogExportKindHTTP LogExportKind = "http"
+)
+
+// LogExportFormat is the payload format used by the HTTP log export sink.
+type LogExportFormat string
+
+const (
+	// LogExportFormatOTLP is the OTLP/HTTP JSON logs format.
+	LogExportFormatOTLP LogExportFormat = "otlp"
+
+	// LogExportFormatLoki is the Loki push API JSON format.
+	LogExportFormatLoki LogExportFormat = "loki"
+)
+
+// LogExportConfig is the configuration of a log export sink.
+type LogExportConfig struct {
+	// Kind is the kind of the sink (syslog, unix, http).
+	Kind LogExportKind `yaml:"kind"`
+
+	// Address is the address of the sink.
+	//
+	// For syslog sinks it is either empty to use the local syslog daemon or an address of the
+	// form `<network>://<host>:<port>` (e.g. udp://127.0.0.1:514), for Unix socket sinks it is
+	// the path to the socket and for HTTP sinks it is the endpoint URL.
+	Address string `yaml:"address,omitempty"`
+
+	// Format is the payload format used by HTTP sinks (otlp, loki).
+	Format LogExportFormat `yaml:"format,omitempty"`
+
+	// Headers are additional headers sent with each request by HTTP sinks (e.g. for
+	// authentication).
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Runtimes is the list of runtimes whose logs are exported. If empty, logs of all runtimes
+	// are exported.
+	Runtimes []common.Namespace `yaml:"runtimes,omitempty"`
+
+	// Components is the list of components whose logs are exported. If empty, logs of all
+	// components are exported.
+	Components []component.ID `yaml:"components,omitempty"`
+
+	// BatchSize is the maximum number of log entries sent in a single batch.
+	//
+	// If not set, a default value is used.
+	BatchSize uint64 `yaml:"batch_size,omitempty"`
+
+	// FlushInterval is the maximum amount of time a log entry is buffered before being sent.
+	//
+	// If not set, a default value is used.
+	FlushInterval time.Duration `yaml:"flush_interval,omitempty"`
+
+	// BufferSize is the maximum number of log entries buffered while the sink is unavailable or
+	// slow. When the buffer is full, reading logs is paused until the sink catches up.
+	//
+	// If not set, a default value is used.
+	BufferSize uint64 `yaml:"buffer_size,omitempty"`
+}
+
+// Matches returns true iff logs of the given runtime component should be exported to the sink.
+func (e *LogExportConfig) Matches(runtimeID common.Namespace, compID component.ID) bool {
+	if len(e.Runtimes) > 0 && !slices.Contains(e.Runtimes, runtimeID) {
+		return false
+	}
+	if len(e.Components) > 0 && !slices.Contains(e.Components, compID) {
+		return false
+	}
+	return true
+}
+
+// Validate validates the log export configuration for correctness.
+func (e *LogExportConfig) Validate() error {
+	switch e.Kind {
+	case LogExportKindSyslog:
+		if e.Address != "" {
+			u, err := url.Parse(e.Address)
+			if err != nil {
+				return fmt.Errorf("malformed syslog address: %w", err)
+			}
+			if u.Scheme != "udp" && u.Scheme != "tcp" {
+				return fmt.Errorf("unsupported syslog network '%s'", u.Scheme)
+			}
+			if u.Host == "" {
+				return fmt.Errorf("syslog address must include a host")
+			}
+		}
+	case LogExportKindUnix:
+		if e.Address == "" {
+			return fmt.Errorf("unix socket path must be specified")
+		}
+	case LogExportKindHTTP:
+		u, err := url.Parse(e.Address)
+		if err != nil {
+			return fmt.Errorf("malformed endpoint URL: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("unsupported endpoint URL scheme '%s'", u.Scheme)
+		}
+		switch e.Format {
+		case LogExportFormatOTLP, LogExportFormatLoki:
+		default:
+			return fmt.Errorf("unknown format '%s'", e.Format)
+		}
+	default:
+		return fmt.Errorf("unknown kind '%s'", e.Kind)
+	}
+	if e.FlushInterval < 0 {
+		return fmt.Errorf("flush_interval must not be negative")
+	}
 	return nil
 }
 
@@ -241,6 +415,16 @@ type RuntimeConfig struct {
 	// to the base URL. Therefore, the provided URLs don't need to be valid
 	// endpoints themselves, only the constructed URLs need to be valid.
 	Registries []string `yaml:"registries,omitempty"`
+
+	// Publishers is the trusted bundle publisher configuration. When set, only bundles whose
+	// manifests are signed by trusted publishers are accepted for this runtime.
+	Publishers *PublishersConfig `yaml:"publishers,omitempty"`
+
+	// TxPool overrides the transaction pool configuration for this runtime.
+	TxPool *RuntimeTxPoolConfig `yaml:"tx_pool,omitempty"`
+
+	// QueryCache is the runtime query result cache configuration.
+	QueryCache QueryCacheConfig `yaml:"query_cache,omitempty"`
 }
 
 // Validate validates the runtime configuration.
@@ -250,9 +434,96 @@ func (c *RuntimeConfig) Validate() error {
 			return err
 		}
 	}
+	if err := c.QueryCache.Validate(); err != nil {
+		return fmt.Errorf("query_cache: %w", err)
+	}
+	if c.Publishers != nil {
+		if err := c.Publishers.Validate(); err != nil {
+			return fmt.Errorf("publishers: %w", err)
+		}
+	}
 	return nil
 }
 
+// PublishersConfig is the trusted bundle publisher configuration.
+type PublishersConfig struct {
+	// Keys are the public keys of trusted publishers.
+	Keys []signature.PublicKey `yaml:"keys"`
+
+	// Threshold is the minimum number of distinct trusted publishers that must sign a bundle
+	// manifest. If not specified, a single signature is required.
+	Threshold uint64 `yaml:"threshold,omitempty"`
+}
+
+// Validate validates the trusted bundle publisher configuration.
+func (c *PublishersConfig) Validate() error {
+	if len(c.Keys) == 0 {
+		return fmt.Errorf("at least one key must be configured")
+	}
+	keys := make(map[signature.PublicKey]struct{}, len(c.Keys))
+	for _, pk := range c.Keys {
+		if !pk.IsValid() {
+			return fmt.Errorf("key %s is invalid", pk)
+		}
+		if _, ok := keys[pk]; ok {
+			return fmt.Errorf("duplicate key %s", pk)
+		}
+		keys[pk] = struct{}{}
+	}
+	if c.Threshold > uint64(len(c.Keys)) {
+		return fmt.Errorf("threshold must not exceed the number of keys")
+	}
+	return nil
+}
+
+// RequiredSignatures returns the number of distinct trusted publishers that must sign a bundle
+// manifest.
+func (c *PublishersConfig) RequiredSignatures() uint64 {
+	return max(c.Threshold, 1)
+}
+
+// QueryCacheConfig is the runtime query result cache configuration.
+type QueryCacheConfig struct {
+	// Enabled specifies whether query results should be cached on client nodes.
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// MaxSize is the maximum total size of cached query results (e.g. 64mb).
+	//
+	// If not specified, a default value is used.
+	MaxSize string `yaml:"max_size,omitempty"`
+
+	// Methods is the list of query methods whose results can be cached. Queries for other methods
+	// always reach the runtime.
+	Methods []string `yaml:"methods,omitempty"`
+}
+
+// Validate validates the query cache configuration.
+func (c *QueryCacheConfig) Validate() error {
+	if c.Enabled && len(c.Methods) == 0 {
+		return fmt.Errorf("methods must be set when the query cache is enabled")
+	}
+	return nil
+}
+
+// RuntimeTxPoolConfig is the per-runtime transaction pool configuration.
+type RuntimeTxPoolConfig struct {
+	// Transaction scheduling policy (priority, fair-share, sender-cap).
+	SchedulingPolicy string `yaml:"scheduling_policy,omitempty"`
+	// Maximum number of transactions of a single sender in each batch (sender-cap policy).
+	MaxSenderTxsPerBatch uint64 `yaml:"max_sender_txs_per_batch,omitempty"`
+}
+
+// Apply returns the given transaction pool configuration with the per-runtime overrides applied.
+func (c *RuntimeTxPoolConfig) Apply(cfg tpConfig.Config) tpConfig.Config {
+	if c.SchedulingPolicy != "" {
+		cfg.SchedulingPolicy = c.SchedulingPolicy
+	}
+	if c.MaxSenderTxsPerBatch != 0 {
+		cfg.MaxSenderTxsPerBatch = c.MaxSenderTxsPerBatch
+	}
+	return cfg
+}
+
 // ComponentConfig is the component configuration.
 type ComponentConfig struct {
 	// ID is the component identifier.
@@ -274,6 +545,9 @@ type ComponentConfig struct {
 	// Permissions is the list of permissions for this component.
 	Permissions []ComponentPermission `yaml:"permissions,omitempty"`
 
+	// Resources contains the resource limits for the component.
+	Resources ResourcesConfig `yaml:"resources,omitempty"`
+
 	// Config contains component local configuration.
 	Config map[string]any `yaml:"config,omitempty"`
 }
@@ -289,6 +563,45 @@ func (c *ComponentConfig) Validate() error {
 		return fmt.Errorf("unknown TEE select mode: %s", c.TEE)
 	}
 
+	if err := c.Resources.Validate(); err != nil {
+		return fmt.Errorf("resources: %w", err)
+	}
+
+	return nil
+}
+
+// ResourcesConfig is the resource limits configuration of a sandboxed component.
+//
+// Limits are enforced using cgroups v2 and require the cgroup root to be configured. They do not
+// apply to components running in TDX virtual machines.
+type ResourcesConfig struct {
+	// Memory is the maximum amount of memory (e.g. 512MB) that the component may use. Exceeding
+	// it causes the component to be killed.
+	Memory string `yaml:"memory,omitempty"`
+
+	// CPU is the maximum number of CPUs (e.g. 1.5) that the component may use.
+	CPU float64 `yaml:"cpu,omitempty"`
+
+	// Pids is the maximum number of processes and threads that the component may use.
+	Pids uint64 `yaml:"pids,omitempty"`
+}
+
+// IsEmpty returns true iff no resource limits are configured.
+func (c *ResourcesConfig) IsEmpty() bool {
+	return c.Memory == "" && c.CPU == 0 && c.Pids == 0
+}
+
+// Validate validates the resource limits configuration.
+func (c *ResourcesConfig) Validate() error {
+	if c.Memory != "" && common.ParseSizeInBytes(c.Memory) == 0 {
+		return fmt.Errorf("malformed memory limit: %s", c.Memory)
+	}
+	if c.CPU < 0 {
+		return fmt.Errorf("cpu must not be negative")
+	}
+	if c.CPU > 0 && c.CPU < 0.01 {
+		return fmt.Errorf("cpu must be at least 0.01")
+	}
 	return nil
 }
 
@@ -348,6 +661,14 @@ const (
 	// PermissionLogView is the permission that grants the component rights to view logs.
 	PermissionLogView ComponentPermission = "log_view"
 
+	// PermissionVolumeSnapshot is the permission that grants the component rights to create,
+	// restore and remove volume snapshots.
+	PermissionVolumeSnapshot ComponentPermission = "volume_snapshot"
+
+	// PermissionMetricsPush is the permission that grants the component rights to publish metrics
+	// which are re-exported by the node.
+	PermissionMetricsPush ComponentPermission = "metrics_push"
+
 	// PrunerStrategyNone is the name of the none pruner strategy.
 	PrunerStrategyNone = "none"
 
@@ -396,12 +717,331 @@ type IndexerConfig struct {
 	BatchSize uint16 `yaml:"batch_size,omitempty"`
 }
 
+// RestartPolicy is the policy for restarting runtimes that terminate or fail to start.
+type RestartPolicy string
+
+const (
+	// RestartPolicyAlways restarts runtimes regardless of how they terminated.
+	RestartPolicyAlways RestartPolicy = "always"
+
+	// RestartPolicyOnFailure restarts runtimes only if they failed, up to the configured maximum
+	// number of consecutive retries.
+	RestartPolicyOnFailure RestartPolicy = "on-failure"
+
+	// RestartPolicyNever never restarts runtimes.
+	RestartPolicyNever RestartPolicy = "never"
+)
+
+// RestartConfig is the runtime restart configuration.
+type RestartConfig struct {
+	// Policy is the restart policy (always, on-failure, never).
+	//
+	// If not specified, runtimes are always restarted.
+	Policy RestartPolicy `yaml:"policy,omitempty"`
+
+	// MaxRetries is the maximum number of consecutive restarts when using the on-failure policy.
+	//
+	// Setting it to zero means no limit.
+	MaxRetries uint64 `yaml:"max_retries,omitempty"`
+
+	// InitialInterval is the initial interval between restarts. Subsequent intervals increase
+	// exponentially.
+	//
+	// If not specified, a default value is used.
+	InitialInterval time.Duration `yaml:"initial_interval,omitempty"`
+
+	// MaxInterval is the maximum interval between restarts.
+	//
+	// If not specified, a default value is used.
+	MaxInterval time.Duration `yaml:"max_interval,omitempty"`
+
+	// CrashLoopThreshold is the number of consecutive crashes after which a runtime is considered
+	// to be crash looping. Crash looping runtimes are not restarted until an operator intervenes.
+	//
+	// Setting it to zero disables crash loop detection.
+	CrashLoopThreshold uint64 `yaml:"crash_loop_threshold,omitempty"`
+}
+
+// Validate validates the restart configuration.
+func (c *RestartConfig) Validate() error {
+	switch c.Policy {
+	case "", RestartPolicyAlways, RestartPolicyOnFailure, RestartPolicyNever:
+	default:
+		return fmt.Errorf("unknown restart policy: %s", c.Policy)
+	}
+
+	if c.InitialInterval < 0 {
+		return fmt.Errorf("initial_interval must not be negative")
+	}
+	if c.MaxInterval != 0 && c.MaxInterval < c.InitialInterval {
+		return fmt.Errorf("max_interval must not be less than initial_interval")
+	}
+	return nil
+}
+
+// TraceConfig is the Runtime Host Protocol message tracing configuration.
+type TraceConfig struct {
+	// Enabled enables recording of all Runtime Host Protocol messages exchanged with runtime
+	// components into trace files stored in the node's runtimes directory.
+	//
+	// Traces contain all data exchanged between the node and the components, including any
+	// sensitive data that is not encrypted by the components themselves.
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// MaxFileSize is the size in bytes after which a new trace file is started.
+	MaxFileSize uint64 `yaml:"max_file_size,omitempty"`
+
+	// MaxFiles is the maximum number of trace files kept for each component. Setting it to zero
+	// means that old trace files are never removed. The first file of the current session is
+	// always kept so that the session can be replayed.
+	MaxFiles uint64 `yaml:"max_files,omitempty"`
+}
+
+// Validate validates the trace configuration.
+func (c *TraceConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MaxFileSize < 1024 {
+		return fmt.Errorf("max_file_size must be at least 1024 bytes")
+	}
+	return nil
+}
+
+// VolumesConfig is the persistent volume configuration.
+type VolumesConfig struct {
+	// GCInterval is the interval between garbage collections of volumes that are no longer
+	// referenced by any bundle.
+	//
+	// Setting it to zero (the default) disables garbage collection. As unreferenced volumes may
+	// still hold data needed by bundles that are only temporarily removed, garbage collection
+	// must be explicitly enabled.
+	GCInterval time.Duration `yaml:"gc_interval,omitempty"`
+
+	// GCGracePeriod is the minimum amount of time a volume must remain unreferenced before it is
+	// removed by the garbage collector.
+	GCGracePeriod time.Duration `yaml:"gc_grace_period,omitempty"`
+
+	// MaxVolumeSize is the maximum size of a single volume, including its snapshots (e.g. 10gb).
+	// Components using volumes exceeding the quota are not started.
+	//
+	// If not specified, the size is not limited.
+	MaxVolumeSize string `yaml:"max_volume_size,omitempty"`
+
+	// MaxRuntimeSize is the maximum total size of all volumes of a single runtime, including
+	// their snapshots (e.g. 50gb). Components using volumes of a runtime exceeding the quota
+	// are not started.
+	//
+	// If not specified, the size is not limited.
+	MaxRuntimeSize string `yaml:"max_runtime_size,omitempty"`
+
+	// MaxSnapshots is the maximum number of snapshots of a single volume.
+	//
+	// Setting it to zero disables snapshots.
+	MaxSnapshots uint64 `yaml:"max_snapshots,omitempty"`
+}
+
+// maxDownloadSegments is the maximum number of bundle segments downloaded in parallel.
+const maxDownloadSegments = 64
+
+// DownloadConfig is the bundle download configuration.
+type DownloadConfig struct {
+	// MaxBandwidth is the maximum total bandwidth used for bundle downloads in bytes per second
+	// (e.g. 10mb).
+	//
+	// If not specified, the bandwidth is not limited.
+	MaxBandwidth string `yaml:"max_bandwidth,omitempty"`
+
+	// Segments is the maximum number of segments of a single bundle that are downloaded in
+	// parallel. Segments are spread across all mirrors serving the bundle.
+	//
+	// If not specified, a default value is used.
+	Segments uint64 `yaml:"segments,omitempty"`
+
+	// StallTimeout is the maximum amount of time a download may make no progress before it is
+	// aborted. Aborted downloads are resumed on the next attempt.
+	//
+	// If not specified, a default value is used.
+	StallTimeout time.Duration `yaml:"stall_timeout,omitempty"`
+}
+
+// Validate validates the bundle download configuration.
+func (c *DownloadConfig) Validate() error {
+	if c.Segments > maxDownloadSegments {
+		return fmt.Errorf("segments must be at most %d", maxDownloadSegments)
+	}
+	if c.StallTimeout < 0 {
+		return fmt.Errorf("stall_timeout must not be negative")
+	}
+	if c.StallTimeout > 0 && c.StallTimeout < time.Second {
+		return fmt.Errorf("stall_timeout must be at least 1 second")
+	}
+	return nil
+}
+
+// RetentionConfig is the bundle retention policy.
+type RetentionConfig struct {
+	// KeepVersions is the number of runtime versions preceding the active version whose bundles
+	// are retained, e.g. to allow fast rollbacks. Bundles of the active and upcoming versions
+	// are always retained.
+	KeepVersions uint64 `yaml:"keep_versions,omitempty"`
+
+	// MaxTotalSize is the maximum total size of all installed bundles (e.g. 10gb). When
+	// exceeded, retained bundles of versions preceding the active version are removed, oldest
+	// first.
+	//
+	// If not specified, the size is not limited.
+	MaxTotalSize string `yaml:"max_total_size,omitempty"`
+
+	// TemporaryMaxAge is the maximum amount of time an unfinished temporary bundle upload is kept
+	// after it was last written to.
+	//
+	// If not specified, a default value is used.
+	TemporaryMaxAge time.Duration `yaml:"temporary_max_age,omitempty"`
+}
+
+// Validate validates the bundle retention policy.
+func (c *RetentionConfig) Validate() error {
+	if c.TemporaryMaxAge < 0 {
+		return fmt.Errorf("temporary_max_age must not be negative")
+	}
+	return nil
+}
+
+// Validate validates the volume configuration.
+func (c *VolumesConfig) Validate() error {
+	if c.GCInterval < 0 {
+		return fmt.Errorf("gc_interval must not be negative")
+	}
+	if c.GCInterval > 0 && c.GCInterval < time.Second {
+		return fmt.Errorf("gc_interval must be at least 1 second")
+	}
+	if c.GCGracePeriod < 0 {
+		return fmt.Errorf("gc_grace_period must not be negative")
+	}
+	return nil
+}
+
+const (
+	// LoadBalancerStrategyRoundRobin is the name of the round-robin load balancing strategy.
+	LoadBalancerStrategyRoundRobin = "round-robin"
+
+	// LoadBalancerStrategyLeastOutstanding is the name of the load balancing strategy that selects
+	// the instance with the least outstanding requests.
+	LoadBalancerStrategyLeastOutstanding = "least-outstanding"
+
+	// LoadBalancerStrategyLatency is the name of the load balancing strategy that selects the
+	// instance with the lowest expected latency based on observed request latencies and the
+	// number of outstanding requests.
+	LoadBalancerStrategyLatency = "latency"
+)
+
 // LoadBalancerConfig is the load balancer configuration.
 type LoadBalancerConfig struct {
 	// NumInstances is the number of runtime instances to provision for load-balancing.
 	//
 	// Setting it to zero or one disables load balancing.
 	NumInstances uint64 `yaml:"num_instances,omitempty"`
+
+	// Strategy is the load balancing strategy (round-robin, least-outstanding, latency).
+	Strategy string `yaml:"strategy,omitempty"`
+
+	// EjectionThreshold is the number of consecutive failed or timed out requests after which
+	// an instance is temporarily ejected from load balancing.
+	//
+	// Setting it to zero (the default) disables ejection.
+	EjectionThreshold uint64 `yaml:"ejection_threshold,omitempty"`
+
+	// EjectionCooldown is the duration after which an ejected instance is re-admitted.
+	EjectionCooldown time.Duration `yaml:"ejection_cooldown,omitempty"`
+
+	// Autoscaling is the instance autoscaling configuration.
+	Autoscaling LoadBalancerAutoscalingConfig `yaml:"autoscaling,omitempty"`
+}
+
+// LoadBalancerAutoscalingConfig is the load balancer instance autoscaling configuration.
+//
+// When enabled, the number of instances is adjusted between the minimum and maximum number of
+// instances based on the number of in-flight requests and request latencies, and the configured
+// number of instances is ignored.
+type LoadBalancerAutoscalingConfig struct {
+	// Enabled specifies whether instance autoscaling is enabled.
+	Enabled bool `yaml:"enabled"`
+
+	// MinInstances is the minimum number of runtime instances.
+	MinInstances uint64 `yaml:"min_instances,omitempty"`
+
+	// MaxInstances is the maximum number of runtime instances.
+	MaxInstances uint64 `yaml:"max_instances,omitempty"`
+
+	// TargetOutstanding is the target number of in-flight requests per instance. An instance is
+	// added when the peak number of in-flight requests per instance reaches the target and one is
+	// removed when the load would stay below half of the target without it.
+	TargetOutstanding uint64 `yaml:"target_outstanding,omitempty"`
+
+	// MaxLatency is the average request latency above which an instance is added.
+	//
+	// Setting it to zero disables latency-based scaling.
+	MaxLatency time.Duration `yaml:"max_latency,omitempty"`
+
+	// Interval is the interval at which the load is evaluated.
+	Interval time.Duration `yaml:"interval,omitempty"`
+
+	// ScaleDownDelay is the duration for which the load must stay low before an instance is
+	// removed.
+	ScaleDownDelay time.Duration `yaml:"scale_down_delay,omitempty"`
+
+	// DrainTimeout is the maximum duration to wait for in-flight requests to complete before
+	// a removed instance is stopped.
+	DrainTimeout time.Duration `yaml:"drain_timeout,omitempty"`
+}
+
+// Validate validates the load balancer instance autoscaling configuration.
+func (c *LoadBalancerAutoscalingConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.MinInstances < 1 {
+		return fmt.Errorf("min_instances must be at least 1")
+	}
+	if c.MaxInstances < 2 || c.MaxInstances < c.MinInstances {
+		return fmt.Errorf("max_instances must be at least 2 and not less than min_instances")
+	}
+	if c.MaxInstances > 128 {
+		return fmt.Errorf("cannot specify more than 128 instances for load balancing")
+	}
+	if c.TargetOutstanding < 1 {
+		return fmt.Errorf("target_outstanding must be at least 1")
+	}
+	if c.Interval < time.Second {
+		return fmt.Errorf("interval must be at least 1 second")
+	}
+	return nil
+}
+
+// Validate validates the load balancer configuration.
+func (c *LoadBalancerConfig) Validate() error {
+	if c.NumInstances > 128 {
+		return fmt.Errorf("cannot specify more than 128 instances for load balancing")
+	}
+
+	switch c.Strategy {
+	case "", LoadBalancerStrategyRoundRobin:
+	case LoadBalancerStrategyLeastOutstanding:
+	case LoadBalancerStrategyLatency:
+	default:
+		return fmt.Errorf("unknown load balancing strategy: %s", c.Strategy)
+	}
+
+	if c.EjectionThreshold > 0 && c.EjectionCooldown < time.Second {
+		return fmt.Errorf("ejection cooldown must be at least 1 second")
+	}
+
+	if err := c.Autoscaling.Validate(); err != nil {
+		return fmt.Errorf("autoscaling: %w", err)
+	}
+	return nil
 }
 
 // Validate validates the configuration settings.
@@ -441,18 +1081,51 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("unknown runtime history pruner strategy: %s", c.Prune.Strategy)
 	}
 
-	if c.LoadBalancer.NumInstances > 128 {
-		return fmt.Errorf("cannot specify more than 128 instances for load balancing")
+	if err := c.LoadBalancer.Validate(); err != nil {
+		return fmt.Errorf("load_balancer: %w", err)
+	}
+
+	if err := c.Restart.Validate(); err != nil {
+		return fmt.Errorf("restart: %w", err)
+	}
+
+	if err := c.Trace.Validate(); err != nil {
+		return fmt.Errorf("trace: %w", err)
+	}
+
+	if err := c.Volumes.Validate(); err != nil {
+		return fmt.Errorf("volumes: %w", err)
+	}
+
+	if err := c.Download.Validate(); err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+
+	if err := c.Retention.Validate(); err != nil {
+		return fmt.Errorf("retention: %w", err)
 	}
 
 	if err := c.Log.Validate(); err != nil {
 		return err
 	}
 
+	if err := c.TxPool.ValidateScheduling(); err != nil {
+		return fmt.Errorf("tx_pool: %w", err)
+	}
+
 	for _, rt := range c.Runtimes {
 		if err := rt.Validate(); err != nil {
 			return err
 		}
+		for _, comp := range rt.Components {
+			if !comp.Resources.IsEmpty() && c.CgroupRoot == "" {
+				return fmt.Errorf("runtime %s: component %s: cgroup_root must be set when resource limits are configured", rt.ID, comp.ID)
+			}
+		}
+		tpCfg := c.GetTxPoolConfig(rt.ID)
+		if err := tpCfg.ValidateScheduling(); err != nil {
+			return fmt.Errorf("runtime %s: tx_pool: %w", rt.ID, err)
+		}
 	}
 	return c.validateNetworking()
 }
@@ -548,10 +1221,46 @@ func DefaultConfig() Config {
 			MaxCheckTxBatchSize:  128,
 			RecheckInterval:      5,
 			RepublishInterval:    60 * time.Second,
+			SchedulingPolicy:     tpConfig.SchedulingPolicyPriority,
+			JournalEnabled:       false,
+			JournalMainQueue:     false,
+			JournalMaxSize:       10_000,
+			JournalExpiry:        3 * time.Hour,
+			AllowEviction:        false,
 		},
 		PreWarmEpochs: 3,
+		Restart: RestartConfig{
+			Policy:             RestartPolicyAlways,
+			MaxRetries:         0,
+			InitialInterval:    500 * time.Millisecond,
+			MaxInterval:        time.Hour,
+			CrashLoopThreshold: 0,
+		},
+		Trace: TraceConfig{
+			Enabled:     false,
+			MaxFileSize: 64 * 1024 * 1024,
+			MaxFiles:    16,
+		},
+		Volumes: VolumesConfig{
+			GCInterval:    0,
+			GCGracePeriod: 24 * time.Hour,
+			MaxSnapshots:  4,
+		},
 		LoadBalancer: LoadBalancerConfig{
-			NumInstances: 0,
+			NumInstances:      0,
+			Strategy:          LoadBalancerStrategyRoundRobin,
+			EjectionThreshold: 0,
+			EjectionCooldown:  30 * time.Second,
+			Autoscaling: LoadBalancerAutoscalingConfig{
+				Enabled:           false,
+				MinInstances:      1,
+				MaxInstances:      4,
+				TargetOutstanding: 4,
+				MaxLatency:        0,
+				Interval:          10 * time.Second,
+				ScaleDownDelay:    5 * time.Minute,
+				DrainTimeout:      30 * time.Second,
+			},
 		},
 		Registries: []string{oasisBundleRegistryURL},
 		SGX: SgxConfig{
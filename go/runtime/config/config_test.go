@@ -2,12 +2,14 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+	tpConfig "github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
 )
 
 func TestComponentConfig(t *testing.T) {
@@ -140,3 +142,294 @@ func TestNetworkingConfig(t *testing.T) {
 	err = cfg.Validate()
 	require.ErrorContains(err, "component rofl (foo-test): overlapping incoming IP/protocol/port")
 }
+
+func TestTxPoolConfig(t *testing.T) {
+	require := require.New(t)
+
+	yamlCfg := `
+tx_pool:
+    schedule_max_tx_pool_size: 100
+runtimes:
+    - id: 8000000000000000000000000000000000000000000000000000000000000000
+      tx_pool:
+          scheduling_policy: sender-cap
+          max_sender_txs_per_batch: 10
+    - id: 8000000000000000000000000000000000000000000000000000000000000001
+`
+	decCfg := DefaultConfig()
+	err := yaml.Unmarshal([]byte(yamlCfg), &decCfg)
+	require.NoError(err, "yaml.Unmarshal")
+
+	var runtimeID1, runtimeID2 common.Namespace
+	err = runtimeID1.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	require.NoError(err)
+	err = runtimeID2.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000001")
+	require.NoError(err)
+
+	tpCfg := decCfg.GetTxPoolConfig(runtimeID1)
+	require.EqualValues(100, tpCfg.MaxPoolSize)
+	require.Equal(tpConfig.SchedulingPolicySenderCap, tpCfg.SchedulingPolicy)
+	require.EqualValues(10, tpCfg.MaxSenderTxsPerBatch)
+
+	tpCfg = decCfg.GetTxPoolConfig(runtimeID2)
+	require.EqualValues(100, tpCfg.MaxPoolSize)
+	require.Equal(tpConfig.SchedulingPolicyPriority, tpCfg.SchedulingPolicy)
+
+	// Sender cap policy requires a limit.
+	decCfg.Runtimes[0].TxPool.MaxSenderTxsPerBatch = 0
+	tpCfg = decCfg.GetTxPoolConfig(runtimeID1)
+	require.Error(tpCfg.ValidateScheduling())
+	decCfg.TxPool.MaxSenderTxsPerBatch = 5
+	tpCfg = decCfg.GetTxPoolConfig(runtimeID1)
+	require.NoError(tpCfg.ValidateScheduling())
+	decCfg.Runtimes[0].TxPool.SchedulingPolicy = "invalid"
+	tpCfg = decCfg.GetTxPoolConfig(runtimeID1)
+	require.Error(tpCfg.ValidateScheduling())
+}
+
+func TestQueryCacheConfig(t *testing.T) {
+	require := require.New(t)
+
+	yamlCfg := `
+runtimes:
+    - id: 8000000000000000000000000000000000000000000000000000000000000000
+      query_cache:
+          enabled: true
+          max_size: 16mb
+          methods:
+              - accounts.Balances
+`
+	decCfg := DefaultConfig()
+	err := yaml.Unmarshal([]byte(yamlCfg), &decCfg)
+	require.NoError(err, "yaml.Unmarshal")
+	require.NoError(decCfg.Validate())
+
+	var runtimeID1, runtimeID2 common.Namespace
+	err = runtimeID1.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	require.NoError(err)
+	err = runtimeID2.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000001")
+	require.NoError(err)
+
+	qcCfg := decCfg.GetQueryCacheConfig(runtimeID1)
+	require.True(qcCfg.Enabled)
+	require.Equal("16mb", qcCfg.MaxSize)
+	require.Equal([]string{"accounts.Balances"}, qcCfg.Methods)
+
+	qcCfg = decCfg.GetQueryCacheConfig(runtimeID2)
+	require.False(qcCfg.Enabled)
+
+	// Method allowlist is required.
+	decCfg.Runtimes[0].QueryCache.Methods = nil
+	require.ErrorContains(decCfg.Validate(), "query_cache: methods must be set")
+}
+
+func TestResourcesConfig(t *testing.T) {
+	require := require.New(t)
+
+	yamlCfg := `
+runtimes:
+    - id: 8000000000000000000000000000000000000000000000000000000000000000
+      components:
+          - id: rofl.foo-test
+            resources:
+                memory: 512mb
+                cpu: 1.5
+                pids: 128
+`
+	decCfg := DefaultConfig()
+	err := yaml.Unmarshal([]byte(yamlCfg), &decCfg)
+	require.NoError(err, "yaml.Unmarshal")
+
+	compCfg := decCfg.Runtimes[0].Components[0]
+	require.Equal(ResourcesConfig{Memory: "512mb", CPU: 1.5, Pids: 128}, compCfg.Resources)
+	require.False(compCfg.Resources.IsEmpty())
+
+	// The cgroup root is required when limits are configured.
+	require.ErrorContains(decCfg.Validate(), "cgroup_root must be set")
+	decCfg.CgroupRoot = "/sys/fs/cgroup/oasis-runtimes"
+	require.NoError(decCfg.Validate())
+
+	decCfg.Runtimes[0].Components[0].Resources.Memory = "lots"
+	require.ErrorContains(decCfg.Validate(), "resources: malformed memory limit: lots")
+	decCfg.Runtimes[0].Components[0].Resources.Memory = "512mb"
+
+	decCfg.Runtimes[0].Components[0].Resources.CPU = -1
+	require.ErrorContains(decCfg.Validate(), "resources: cpu must not be negative")
+}
+
+func TestRestartConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	require.NoError(cfg.Validate())
+
+	cfg.Restart.Policy = "sometimes"
+	require.ErrorContains(cfg.Validate(), "restart: unknown restart policy")
+	cfg.Restart.Policy = RestartPolicyOnFailure
+
+	cfg.Restart.MaxInterval = time.Millisecond
+	require.ErrorContains(cfg.Validate(), "restart: max_interval")
+	cfg.Restart.MaxInterval = time.Minute
+
+	cfg.Restart.InitialInterval = -time.Second
+	require.Error(cfg.Restart.Validate())
+}
+
+func TestVolumesConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	require.NoError(cfg.Validate())
+	require.Zero(cfg.Volumes.GCInterval, "volume garbage collection should be disabled by default")
+
+	cfg.Volumes.GCInterval = time.Millisecond
+	require.ErrorContains(cfg.Validate(), "volumes: gc_interval")
+	cfg.Volumes.GCInterval = 0
+	require.NoError(cfg.Validate())
+
+	cfg.Volumes.GCGracePeriod = -time.Second
+	require.ErrorContains(cfg.Validate(), "volumes: gc_grace_period")
+}
+
+func TestDownloadConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	require.NoError(cfg.Validate())
+
+	cfg.Download.Segments = maxDownloadSegments + 1
+	require.ErrorContains(cfg.Validate(), "download: segments")
+	cfg.Download.Segments = maxDownloadSegments
+	require.NoError(cfg.Validate())
+
+	cfg.Download.StallTimeout = time.Millisecond
+	require.ErrorContains(cfg.Validate(), "download: stall_timeout")
+	cfg.Download.StallTimeout = -time.Second
+	require.ErrorContains(cfg.Validate(), "download: stall_timeout")
+}
+
+func TestRetentionConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	require.NoError(cfg.Validate())
+
+	cfg.Retention.TemporaryMaxAge = -time.Second
+	require.ErrorContains(cfg.Validate(), "retention: temporary_max_age")
+}
+
+func TestLogExportConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	cfg.Log.Exports = []LogExportConfig{
+		{Kind: LogExportKindSyslog},
+		{Kind: LogExportKindSyslog, Address: "udp://127.0.0.1:514"},
+		{Kind: LogExportKindUnix, Address: "/run/logs.sock"},
+		{Kind: LogExportKindHTTP, Address: "https://logs.example.com/v1/logs", Format: LogExportFormatOTLP},
+	}
+	require.NoError(cfg.Validate())
+
+	for _, tc := range []struct {
+		export LogExportConfig
+		err    string
+	}{
+		{LogExportConfig{Kind: "kafka"}, "unknown kind"},
+		{LogExportConfig{Kind: LogExportKindSyslog, Address: "unix:///dev/log"}, "unsupported syslog network"},
+		{LogExportConfig{Kind: LogExportKindUnix}, "unix socket path must be specified"},
+		{LogExportConfig{Kind: LogExportKindHTTP, Address: "ftp://logs.example.com", Format: LogExportFormatLoki}, "unsupported endpoint URL scheme"},
+		{LogExportConfig{Kind: LogExportKindHTTP, Address: "https://logs.example.com"}, "unknown format"},
+		{LogExportConfig{Kind: LogExportKindSyslog, FlushInterval: -time.Second}, "flush_interval"},
+	} {
+		cfg.Log.Exports = []LogExportConfig{tc.export}
+		require.ErrorContains(cfg.Validate(), tc.err)
+	}
+
+	var runtimeID common.Namespace
+	appID := component.ID{Kind: component.ROFL, Name: "app"}
+	export := LogExportConfig{Kind: LogExportKindSyslog}
+	require.True(export.Matches(runtimeID, component.ID_RONL))
+	export.Components = []component.ID{appID}
+	require.True(export.Matches(runtimeID, appID))
+	require.False(export.Matches(runtimeID, component.ID_RONL))
+	export.Runtimes = []common.Namespace{{1}}
+	require.False(export.Matches(runtimeID, appID))
+}
+
+func TestLoadBalancerConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	require.NoError(cfg.LoadBalancer.Validate())
+
+	cfg.LoadBalancer.Strategy = LoadBalancerStrategyLatency
+	require.NoError(cfg.Validate())
+
+	cfg.LoadBalancer.Strategy = "random"
+	require.ErrorContains(cfg.Validate(), "load_balancer: unknown load balancing strategy")
+
+	cfg.LoadBalancer.Strategy = LoadBalancerStrategyLeastOutstanding
+	cfg.LoadBalancer.EjectionThreshold = 5
+	cfg.LoadBalancer.EjectionCooldown = 0
+	require.Error(cfg.LoadBalancer.Validate())
+	cfg.LoadBalancer.EjectionThreshold = 0
+	require.NoError(cfg.LoadBalancer.Validate())
+
+	cfg.LoadBalancer.NumInstances = 129
+	require.Error(cfg.LoadBalancer.Validate())
+	cfg.LoadBalancer.NumInstances = 0
+
+	cfg.LoadBalancer.Autoscaling.Enabled = true
+	require.NoError(cfg.LoadBalancer.Validate())
+
+	cfg.LoadBalancer.Autoscaling.MinInstances = 5
+	require.ErrorContains(cfg.Validate(), "load_balancer: autoscaling: max_instances")
+	cfg.LoadBalancer.Autoscaling.MinInstances = 0
+	require.Error(cfg.LoadBalancer.Validate())
+	cfg.LoadBalancer.Autoscaling.MinInstances = 1
+
+	cfg.LoadBalancer.Autoscaling.TargetOutstanding = 0
+	require.Error(cfg.LoadBalancer.Validate())
+	cfg.LoadBalancer.Autoscaling.TargetOutstanding = 1
+
+	cfg.LoadBalancer.Autoscaling.Interval = 0
+	require.Error(cfg.LoadBalancer.Validate())
+}
+
+func TestPublishersConfig(t *testing.T) {
+	require := require.New(t)
+
+	yamlCfg := `
+runtimes:
+    - id: 8000000000000000000000000000000000000000000000000000000000000000
+      publishers:
+          keys:
+              - 6/MY7D0r6U4gW/vXZMWd33X1bl+GoPhaH1GLmBDVvRw=
+              - TqUyj5Q+9vZtqu10yw6Zw7HEX3Ywe0JQA9vHyzY47TU=
+          threshold: 2
+`
+	decCfg := DefaultConfig()
+	err := yaml.Unmarshal([]byte(yamlCfg), &decCfg)
+	require.NoError(err, "yaml.Unmarshal")
+	require.NoError(decCfg.Validate())
+
+	publishers := decCfg.Runtimes[0].Publishers
+	require.NotNil(publishers)
+	require.Len(publishers.Keys, 2)
+	require.Equal("6/MY7D0r6U4gW/vXZMWd33X1bl+GoPhaH1GLmBDVvRw=", publishers.Keys[0].String())
+	require.EqualValues(2, publishers.RequiredSignatures())
+
+	publishers.Threshold = 0
+	require.NoError(decCfg.Validate())
+	require.EqualValues(1, publishers.RequiredSignatures())
+
+	publishers.Threshold = 3
+	require.ErrorContains(decCfg.Validate(), "publishers: threshold must not exceed the number of keys")
+	publishers.Threshold = 1
+
+	publishers.Keys[1] = publishers.Keys[0]
+	require.ErrorContains(decCfg.Validate(), "publishers: duplicate key")
+
+	publishers.Keys = nil
+	require.ErrorContains(decCfg.Validate(), "publishers: at least one key must be configured")
+}
@@ -3,8 +3,10 @@ package host
 
 import (
 	"context"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx/quote"
@@ -102,6 +104,71 @@ type CompositeRuntime interface {
 	Component(id component.ID) (Runtime, bool)
 }
 
+// StatusReporter is a runtime that can report its status.
+type StatusReporter interface {
+	// GetStatus returns the current status of the runtime.
+	GetStatus() (*Status, error)
+}
+
+// State is the state of a provisioned runtime.
+type State string
+
+const (
+	// StateStarting is the state of a runtime that is being (re)started.
+	StateStarting State = "starting"
+	// StateRunning is the state of a runtime that is running.
+	StateRunning State = "running"
+	// StateStopped is the state of a runtime that is not running and is no longer being restarted
+	// as its restart policy does not allow it.
+	StateStopped State = "stopped"
+	// StateCrashLooping is the state of a runtime that is not running and is no longer being
+	// restarted after repeatedly crashing.
+	StateCrashLooping State = "crash_looping"
+)
+
+// Status is the status of a provisioned runtime.
+type Status struct {
+	// State is the state of the runtime.
+	State State `json:"state"`
+
+	// Crashes is the number of times the runtime terminated unexpectedly or failed to start since
+	// it has been provisioned.
+	Crashes uint64 `json:"crashes"`
+
+	// StartedAt is the time when the runtime was last started. It is zero in case the runtime is
+	// not running.
+	StartedAt time.Time `json:"started_at"`
+
+	// Resources is the resource usage of the runtime. It may be nil in case the runtime is not
+	// running or the provisioner does not support reporting resource usage.
+	Resources *ResourceUsage `json:"resources,omitempty"`
+
+	// AttestedAt is the time of the last successful attestation of the runtime. It is zero in
+	// case the runtime is not running inside a TEE or has not yet been attested.
+	AttestedAt time.Time `json:"attested_at"`
+
+	// RAK is the runtime attestation key of the running runtime. It may be nil in case the
+	// runtime is not running inside a TEE.
+	RAK *signature.PublicKey `json:"rak,omitempty"`
+}
+
+// Uptime returns the time the runtime has been running for at the given time.
+func (s *Status) Uptime(now time.Time) time.Duration {
+	if s.State != StateRunning || s.StartedAt.IsZero() {
+		return 0
+	}
+	return now.Sub(s.StartedAt)
+}
+
+// ResourceUsage is the resource usage of a provisioned runtime.
+type ResourceUsage struct {
+	// CPUTime is the total CPU time consumed by the runtime since it was last started.
+	CPUTime time.Duration `json:"cpu_time"`
+
+	// Memory is the amount of resident memory used by the runtime in bytes.
+	Memory uint64 `json:"memory"`
+}
+
 // RuntimeHandler is the message handler for the host side of the runtime host protocol.
 type RuntimeHandler interface {
 	protocol.Handler
@@ -126,6 +193,7 @@ type Event struct {
 	Stopped       *StoppedEvent
 	Updated       *UpdatedEvent
 	ConfigUpdated *ConfigUpdatedEvent
+	CrashLooping  *CrashLoopingEvent
 }
 
 // StartedEvent is a runtime started event.
@@ -145,7 +213,21 @@ type FailedToStartEvent struct {
 }
 
 // StoppedEvent is a runtime stopped event.
-type StoppedEvent struct{}
+type StoppedEvent struct {
+	// Error is the reason for the runtime stopping unexpectedly (if any).
+	Error error
+}
+
+// CrashLoopingEvent is a runtime crash looping event, emitted when a runtime is no longer being
+// restarted after repeatedly crashing. The runtime remains stopped until it is explicitly
+// restarted.
+type CrashLoopingEvent struct {
+	// Crashes is the number of consecutive crashes.
+	Crashes uint64
+
+	// Error is the error that caused the last crash.
+	Error error
+}
 
 // UpdatedEvent is a runtime metadata updated event.
 type UpdatedEvent struct {
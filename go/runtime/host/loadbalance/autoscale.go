@@ -0,0 +1,240 @@
+package loadbalance
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	scaleUpDirection   = "up"
+	scaleDownDirection = "down"
+)
+
+// scalingDecision is the outcome of an autoscaler evaluation.
+type scalingDecision uint8
+
+const (
+	scaleNone scalingDecision = iota
+	scaleUp
+	scaleDown
+)
+
+// autoscale periodically evaluates the load and adds or removes instances as needed.
+func (h *lbHost) autoscale() {
+	ticker := time.NewTicker(h.cfg.Autoscaling.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-h.stopCh:
+			return
+		}
+
+		switch h.evaluateScaling(time.Now()) {
+		case scaleUp:
+			h.addInstance()
+		case scaleDown:
+			h.removeInstance()
+		}
+	}
+}
+
+// evaluateScaling decides whether an instance should be added or removed based on the peak number
+// of in-flight requests and the average request latency since the last evaluation.
+func (h *lbHost) evaluateScaling(now time.Time) scalingDecision {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	cfg := h.cfg.Autoscaling
+	peak := h.peakInFlight
+	h.peakInFlight = h.inFlight
+
+	var (
+		active, sampled int
+		totalLatency    time.Duration
+	)
+	for _, inst := range h.states {
+		if inst.draining {
+			continue
+		}
+		if inst.starting {
+			// Wait for the previously added instance to start before reevaluating.
+			h.lowLoadSince = time.Time{}
+			return scaleNone
+		}
+		active++
+
+		if inst.healthy && inst.latency > 0 {
+			sampled++
+			totalLatency += inst.latency
+		}
+	}
+	var avgLatency time.Duration
+	if sampled > 0 {
+		avgLatency = totalLatency / time.Duration(sampled)
+	}
+
+	target := int(cfg.TargetOutstanding)
+	overloaded := peak >= target*active || (cfg.MaxLatency > 0 && avgLatency > cfg.MaxLatency)
+	if overloaded {
+		h.lowLoadSince = time.Time{}
+		if active < int(cfg.MaxInstances) {
+			return scaleUp
+		}
+		return scaleNone
+	}
+
+	// Only remove an instance if the remaining ones would stay below half of the target load.
+	underloaded := 2*peak < target*(active-1) && (cfg.MaxLatency == 0 || 2*avgLatency < cfg.MaxLatency)
+	if !underloaded || active <= int(cfg.MinInstances) {
+		h.lowLoadSince = time.Time{}
+		return scaleNone
+	}
+	if h.lowLoadSince.IsZero() {
+		h.lowLoadSince = now
+	}
+	if now.Sub(h.lowLoadSince) < cfg.ScaleDownDelay {
+		return scaleNone
+	}
+	h.lowLoadSince = time.Time{}
+	return scaleDown
+}
+
+// addInstance provisions and starts a new instance.
+func (h *lbHost) addInstance() {
+	rt, err := h.factory()
+	if err != nil {
+		h.logger.Error("failed to provision new instance",
+			"err", err,
+		)
+		return
+	}
+
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	select {
+	case <-h.stopCh:
+		return
+	default:
+	}
+
+	inst := newInstance(h.nextIdx, rt)
+	inst.starting = true
+	h.nextIdx++
+	h.states = append(h.states, inst)
+
+	h.logger.Info("adding instance",
+		"instance", inst.idx,
+		"num_instances", len(h.states),
+	)
+
+	h.startInstanceLocked(inst)
+	h.updateInstanceCountLocked()
+	lbScalingEvents.With(h.scalingLabels(scaleUpDirection)).Inc()
+}
+
+// removeInstance selects the least loaded instance other than the primary one and starts
+// draining it.
+func (h *lbHost) removeInstance() {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	var inst *instance
+	for _, candidate := range h.states[1:] {
+		if candidate.draining {
+			continue
+		}
+		if inst == nil || candidate.outstanding <= inst.outstanding {
+			inst = candidate
+		}
+	}
+	if inst == nil {
+		return
+	}
+
+	h.logger.Info("draining instance",
+		"instance", inst.idx,
+		"outstanding", inst.outstanding,
+	)
+
+	inst.draining = true
+	inst.drainedCh = make(chan struct{})
+	if inst.outstanding == 0 {
+		close(inst.drainedCh)
+	}
+	lbScalingEvents.With(h.scalingLabels(scaleDownDirection)).Inc()
+
+	go h.drainInstance(inst)
+}
+
+// drainInstance waits for the outstanding requests of a draining instance to complete, then
+// removes and stops the instance.
+func (h *lbHost) drainInstance(inst *instance) {
+	timeout := time.NewTimer(h.cfg.Autoscaling.DrainTimeout)
+	defer timeout.Stop()
+
+	select {
+	case <-inst.drainedCh:
+	case <-timeout.C:
+		h.logger.Warn("timed out while draining instance",
+			"instance", inst.idx,
+		)
+	case <-h.stopCh:
+		// All instances are stopped when the host is stopped.
+		return
+	}
+
+	h.l.Lock()
+	for i, candidate := range h.states {
+		if candidate == inst {
+			h.states = append(h.states[:i], h.states[i+1:]...)
+			break
+		}
+	}
+	close(inst.quitCh)
+	inst.removed = true
+	h.updateInstanceCountLocked()
+	h.deleteInstanceMetricsLocked(inst)
+	h.l.Unlock()
+
+	h.logger.Info("stopping drained instance",
+		"instance", inst.idx,
+	)
+	inst.rt.Stop()
+}
+
+func (h *lbHost) scalingLabels(direction string) prometheus.Labels {
+	return prometheus.Labels{
+		labelRuntime:   h.id.String(),
+		labelDirection: direction,
+	}
+}
+
+func (h *lbHost) updateInstanceCountLocked() {
+	lbInstanceCount.With(prometheus.Labels{
+		labelRuntime: h.id.String(),
+	}).Set(float64(len(h.states)))
+
+	var healthy int
+	for _, inst := range h.states {
+		if inst.healthy {
+			healthy++
+		}
+	}
+	lbHealthyInstanceCount.With(prometheus.Labels{
+		labelRuntime: h.id.String(),
+	}).Set(float64(healthy))
+}
+
+func (h *lbHost) deleteInstanceMetricsLocked(inst *instance) {
+	labels := h.instanceLabels(inst)
+	lbRequestCount.Delete(labels)
+	lbOutstandingRequests.Delete(labels)
+	lbRequestLatency.Delete(labels)
+	lbRequestFailures.Delete(labels)
+	lbInstanceEjections.Delete(labels)
+	lbInstanceEjected.Delete(labels)
+}
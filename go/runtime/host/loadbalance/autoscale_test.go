@@ -0,0 +1,207 @@
+package loadbalance
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+)
+
+type testRuntime struct {
+	host.Runtime
+
+	notifier *pubsub.Broker
+	stopped  atomic.Bool
+
+	mu    sync.Mutex
+	calls []*protocol.Body
+}
+
+func newTestRuntime() *testRuntime {
+	return &testRuntime{
+		notifier: pubsub.NewBroker(false),
+	}
+}
+
+func (r *testRuntime) WatchEvents() (<-chan *host.Event, pubsub.ClosableSubscription) {
+	sub := r.notifier.Subscribe()
+	ch := make(chan *host.Event)
+	sub.Unwrap(ch)
+	return ch, sub
+}
+
+func (r *testRuntime) Call(_ context.Context, body *protocol.Body) (*protocol.Body, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, body)
+	return &protocol.Body{Empty: &protocol.Empty{}}, nil
+}
+
+func (r *testRuntime) Start() {
+	r.notifier.Broadcast(&host.Event{Started: &host.StartedEvent{}})
+}
+
+func (r *testRuntime) Stop() {
+	r.stopped.Store(true)
+}
+
+func TestAutoscaling(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+
+	var runtimes []*testRuntime
+	factory := func() (host.Runtime, error) {
+		rt := newTestRuntime()
+		runtimes = append(runtimes, rt)
+		return rt, nil
+	}
+	primary, _ := factory()
+
+	h, err := newHost(common.Namespace{}, []host.Runtime{primary}, rtConfig.LoadBalancerConfig{
+		Strategy: rtConfig.LoadBalancerStrategyLeastOutstanding,
+		Autoscaling: rtConfig.LoadBalancerAutoscalingConfig{
+			Enabled:           true,
+			MinInstances:      1,
+			MaxInstances:      2,
+			TargetOutstanding: 2,
+			Interval:          time.Second,
+			ScaleDownDelay:    time.Minute,
+			DrainTimeout:      time.Minute,
+		},
+	}, factory)
+	require.NoError(err, "newHost")
+	h.setHealthy(h.states[0], true)
+
+	// Low load does not remove the last instance.
+	require.Equal(scaleNone, h.evaluateScaling(now))
+
+	// Reaching the target number of in-flight requests adds an instance.
+	first := testSelect(t, h, now)
+	testSelect(t, h, now)
+	h.finishRequest(h.states[first], now, time.Millisecond, nil)
+	require.Equal(scaleUp, h.evaluateScaling(now))
+	h.addInstance()
+	require.Len(h.states, 2)
+
+	// No further scaling happens until the new instance has started.
+	require.Equal(scaleNone, h.evaluateScaling(now))
+	require.Eventually(func() bool {
+		h.l.Lock()
+		defer h.l.Unlock()
+		return h.states[1].healthy
+	}, time.Second, 10*time.Millisecond)
+
+	// The maximum number of instances is respected.
+	testSelect(t, h, now)
+	testSelect(t, h, now)
+	testSelect(t, h, now)
+	require.Equal(scaleNone, h.evaluateScaling(now))
+
+	// Low load removes an instance only after the scale down delay.
+	for _, inst := range h.states {
+		for inst.outstanding > 0 {
+			h.finishRequest(inst, now, time.Millisecond, nil)
+		}
+	}
+	require.Equal(scaleNone, h.evaluateScaling(now))
+	require.Equal(scaleNone, h.evaluateScaling(now))
+	require.Equal(scaleNone, h.evaluateScaling(now.Add(30*time.Second)))
+	require.Equal(scaleDown, h.evaluateScaling(now.Add(time.Minute)))
+
+	// Draining instances no longer receive requests and are stopped once idle.
+	inst := h.states[1]
+	if idx := testSelect(t, h, now); idx != inst.idx {
+		require.Equal(inst.idx, testSelect(t, h, now))
+	}
+	h.removeInstance()
+	require.True(inst.draining)
+	require.Equal(0, testSelect(t, h, now))
+	require.False(runtimes[1].stopped.Load())
+
+	h.finishRequest(inst, now, time.Millisecond, nil)
+	require.Eventually(func() bool {
+		return runtimes[1].stopped.Load()
+	}, time.Second, 10*time.Millisecond)
+
+	h.l.Lock()
+	defer h.l.Unlock()
+	require.Len(h.states, 1)
+	require.False(runtimes[0].stopped.Load())
+}
+
+func TestAutoscalingReplay(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+
+	var runtimes []*testRuntime
+	factory := func() (host.Runtime, error) {
+		rt := newTestRuntime()
+		runtimes = append(runtimes, rt)
+		return rt, nil
+	}
+	primary, _ := factory()
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("loadbalance autoscaling replay"), 0)
+	h, err := newHost(runtimeID, []host.Runtime{primary}, rtConfig.LoadBalancerConfig{
+		Strategy: rtConfig.LoadBalancerStrategyLeastOutstanding,
+		Autoscaling: rtConfig.LoadBalancerAutoscalingConfig{
+			Enabled:           true,
+			MinInstances:      1,
+			MaxInstances:      2,
+			TargetOutstanding: 2,
+			Interval:          time.Second,
+			ScaleDownDelay:    time.Minute,
+			DrainTimeout:      time.Millisecond,
+		},
+	}, factory)
+	require.NoError(err, "newHost")
+	h.setHealthy(h.states[0], true)
+
+	// Propagate some requests before adding an instance.
+	kmStatus := &protocol.Body{RuntimeKeyManagerStatusUpdateRequest: &protocol.RuntimeKeyManagerStatusUpdateRequest{}}
+	oldSync := &protocol.Body{RuntimeConsensusSyncRequest: &protocol.RuntimeConsensusSyncRequest{Height: 1}}
+	newSync := &protocol.Body{RuntimeConsensusSyncRequest: &protocol.RuntimeConsensusSyncRequest{Height: 2}}
+	for _, body := range []*protocol.Body{kmStatus, oldSync, newSync} {
+		_, err = h.Call(context.Background(), body)
+		require.NoError(err, "Call")
+	}
+	require.Len(runtimes[0].calls, 3)
+
+	// The new instance should receive the last propagated request of each kind before it is
+	// marked as healthy.
+	h.addInstance()
+	require.Eventually(func() bool {
+		h.l.Lock()
+		defer h.l.Unlock()
+		return h.states[1].healthy
+	}, time.Second, 10*time.Millisecond)
+
+	runtimes[1].mu.Lock()
+	require.Equal([]*protocol.Body{kmStatus, newSync}, runtimes[1].calls)
+	runtimes[1].mu.Unlock()
+
+	// Finishing a request after the instance has been removed due to a drain timeout should not
+	// recreate its metrics.
+	inst := h.states[1]
+	h.states[0].draining = true
+	require.Equal(inst.idx, testSelect(t, h, now))
+	h.states[0].draining = false
+	h.removeInstance()
+	require.Eventually(func() bool {
+		return runtimes[1].stopped.Load()
+	}, time.Second, 10*time.Millisecond)
+
+	h.finishRequest(inst, now, time.Millisecond, nil)
+	require.False(lbOutstandingRequests.Delete(h.instanceLabels(inst)), "metrics should not be recreated")
+	require.False(lbRequestLatency.Delete(h.instanceLabels(inst)), "metrics should not be recreated")
+}
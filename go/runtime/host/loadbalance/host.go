@@ -6,26 +6,119 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	cmnErrors "github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
 )
 
-type lbHost struct {
-	id        common.Namespace
-	instances []host.Runtime
+const (
+	// latencyDecay is the weight of the latest latency sample in the moving average of instance
+	// request latencies.
+	latencyDecay = 0.2
+	// replayTimeout is the timeout for replaying previously propagated requests to a (re)started
+	// instance.
+	replayTimeout = time.Minute
+)
+
+// instance is a runtime instance along with its load balancing state.
+type instance struct {
+	idx   int
+	label string
+	rt    host.Runtime
+
+	// quitCh is closed when the instance is removed from the load balancer.
+	quitCh chan struct{}
+
+	// healthy is true iff the instance has been started and has not stopped since.
+	healthy bool
+	// outstanding is the number of requests currently being processed by the instance.
+	outstanding int
+	// latency is the moving average of request latencies, zero if there are no samples.
+	latency time.Duration
+	// failures is the number of consecutive failed or timed out requests.
+	failures uint64
+	// ejectedUntil is the time until which the instance is ejected from load balancing.
+	ejectedUntil time.Time
+
+	// starting is true iff the instance has been added by the autoscaler and has not yet
+	// finished starting.
+	starting bool
+	// draining is true iff the instance is being removed and should no longer receive requests.
+	draining bool
+	// drainedCh is closed once a draining instance has no more outstanding requests.
+	drainedCh chan struct{}
+	// removed is true iff the instance has been removed from the load balancer.
+	removed bool
+}
+
+func newInstance(idx int, rt host.Runtime) *instance {
+	return &instance{
+		idx:    idx,
+		label:  strconv.Itoa(idx),
+		rt:     rt,
+		quitCh: make(chan struct{}),
+	}
+}
 
-	l                sync.Mutex
-	nextIdx          int
-	healthyInstances map[int]struct{}
+// isEjected returns true iff the instance is ejected at the given time.
+func (i *instance) isEjected(now time.Time) bool {
+	return now.Before(i.ejectedUntil)
+}
+
+// isInstanceFailure returns true iff the given request error indicates a problem with the instance
+// handling the request, as opposed to an error returned by the runtime itself.
+func isInstanceFailure(err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, context.Canceled):
+		// The caller is no longer interested in the result.
+		return false
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, protocol.ErrNotReady):
+		return true
+	default:
+		// Errors returned by the runtime are coded so anything else is a failure to communicate
+		// with the instance.
+		module, _ := cmnErrors.Code(err)
+		return module == cmnErrors.UnknownModule
+	}
+}
+
+type lbHost struct {
+	id  common.Namespace
+	cfg rtConfig.LoadBalancerConfig
+
+	// primary is the first instance which is never removed and handles all requests that are
+	// not load-balanced.
+	primary host.Runtime
+	// factory provisions new instances when autoscaling is enabled, nil otherwise.
+	factory func() (host.Runtime, error)
+
+	l        sync.Mutex
+	states   []*instance
+	nextIdx  int
+	strategy strategy
+	// inFlight is the number of requests currently being processed by all instances.
+	inFlight int
+	// peakInFlight is the maximum number of in-flight requests since the last autoscaler
+	// evaluation.
+	peakInFlight int
+	// lowLoadSince is the time since which the load has been low enough to remove an instance.
+	lowLoadSince time.Time
+	// propagated are the last requests of each kind that have been propagated to all instances.
+	propagated map[propagatedKind]*protocol.Body
 
 	startOnce sync.Once
 	stopOnce  sync.Once
@@ -35,14 +128,52 @@ type lbHost struct {
 }
 
 // NewHost creates a new load balancer runtime host.
-func NewHost(id common.Namespace, instances []host.Runtime) host.Runtime {
-	return &lbHost{
-		id:               id,
-		instances:        instances,
-		healthyInstances: make(map[int]struct{}),
-		stopCh:           make(chan struct{}),
-		logger:           logging.GetLogger("runtime/host/loadbalance").With("runtime_id", id),
+func NewHost(id common.Namespace, instances []host.Runtime, cfg rtConfig.LoadBalancerConfig) (host.Runtime, error) {
+	h, err := newHost(id, instances, cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// newHost creates a new load balancer runtime host. In case autoscaling is enabled, the given
+// factory is used to provision additional instances.
+func newHost(
+	id common.Namespace,
+	instances []host.Runtime,
+	cfg rtConfig.LoadBalancerConfig,
+	factory func() (host.Runtime, error),
+) (*lbHost, error) {
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("host/loadbalance: at least one instance is required")
+	}
+
+	lbStrategy, err := newStrategy(cfg.Strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*instance, 0, len(instances))
+	for idx, rt := range instances {
+		states = append(states, newInstance(idx, rt))
+	}
+
+	if !cfg.Autoscaling.Enabled {
+		factory = nil
 	}
+
+	return &lbHost{
+		id:         id,
+		cfg:        cfg,
+		primary:    instances[0],
+		factory:    factory,
+		states:     states,
+		nextIdx:    len(states),
+		strategy:   lbStrategy,
+		propagated: make(map[propagatedKind]*protocol.Body),
+		stopCh:     make(chan struct{}),
+		logger:     logging.GetLogger("runtime/host/loadbalance").With("runtime_id", id),
+	}, nil
 }
 
 // Implements host.Runtime.
@@ -52,48 +183,103 @@ func (h *lbHost) ID() common.Namespace {
 
 // Implements host.Runtime.
 func (h *lbHost) GetInfo(ctx context.Context) (*protocol.RuntimeInfoResponse, error) {
-	return h.instances[0].GetInfo(ctx)
+	return h.primary.GetInfo(ctx)
 }
 
 // Implements host.Runtime.
 func (h *lbHost) GetActiveVersion() (*version.Version, error) {
-	return h.instances[0].GetActiveVersion()
+	return h.primary.GetActiveVersion()
 }
 
 // Implements host.Runtime.
 func (h *lbHost) GetCapabilityTEE() (*node.CapabilityTEE, error) {
 	// TODO: This won't work when registration of all client runtimes is required.
-	return h.instances[0].GetCapabilityTEE()
+	return h.primary.GetCapabilityTEE()
 }
 
-// shouldPropagateToAll checks whether the given runtime request should be propagated to all
-// instances.
-func shouldPropagateToAll(body *protocol.Body) bool {
+// propagatedKind is the kind of a runtime request that is propagated to all instances.
+type propagatedKind uint8
+
+const (
+	propagatedNone propagatedKind = iota
+	propagatedConsensusSync
+	propagatedKeyManagerStatusUpdate
+	propagatedKeyManagerQuotePolicyUpdate
+)
+
+// propagatedKindOf returns the kind of the given runtime request in case it should be propagated
+// to all instances, and propagatedNone otherwise.
+func propagatedKindOf(body *protocol.Body) propagatedKind {
 	switch {
 	case body.RuntimeConsensusSyncRequest != nil:
 		// Consensus view of all instances should be up to date as otherwise signed attestations
 		// will be stale, resulting in them being rejected.
-		return true
-	case body.RuntimeKeyManagerStatusUpdateRequest != nil,
-		body.RuntimeKeyManagerQuotePolicyUpdateRequest != nil:
+		return propagatedConsensusSync
+	case body.RuntimeKeyManagerStatusUpdateRequest != nil:
 		// Key manager updates should be propagated.
-		return true
+		return propagatedKeyManagerStatusUpdate
+	case body.RuntimeKeyManagerQuotePolicyUpdateRequest != nil:
+		return propagatedKeyManagerQuotePolicyUpdate
 	default:
-		return false
+		return propagatedNone
 	}
 }
 
+// propagate records the given request so that it can be replayed to instances that start later
+// and returns all instances the request should be propagated to.
+func (h *lbHost) propagate(kind propagatedKind, body *protocol.Body) []host.Runtime {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	h.propagated[kind] = body
+
+	runtimes := make([]host.Runtime, 0, len(h.states))
+	for _, inst := range h.states {
+		runtimes = append(runtimes, inst.rt)
+	}
+	return runtimes
+}
+
+// replayPropagated replays the last propagated requests of each kind to the given instance, so
+// that (re)started instances do not miss any updates received before they started.
+func (h *lbHost) replayPropagated(inst *instance) error {
+	h.l.Lock()
+	var bodies []*protocol.Body
+	for _, kind := range []propagatedKind{
+		propagatedKeyManagerStatusUpdate,
+		propagatedKeyManagerQuotePolicyUpdate,
+		propagatedConsensusSync,
+	} {
+		if body, ok := h.propagated[kind]; ok {
+			bodies = append(bodies, body)
+		}
+	}
+	h.l.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+	defer cancel()
+
+	for _, body := range bodies {
+		if _, err := inst.rt.Call(ctx, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Implements host.Runtime.
 func (h *lbHost) Call(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
+	kind := propagatedKindOf(body)
 	switch {
-	case shouldPropagateToAll(body):
+	case kind != propagatedNone:
 		// Propagate call to all instances.
 		type result struct {
 			rsp *protocol.Body
 			err error
 		}
+		runtimes := h.propagate(kind, body)
 		resCh := make(chan *result)
-		for _, rt := range h.instances {
+		for _, rt := range runtimes {
 			go func() {
 				rsp, err := rt.Call(ctx, body)
 				resCh <- &result{
@@ -107,7 +293,7 @@ func (h *lbHost) Call(ctx context.Context, body *protocol.Body) (*protocol.Body,
 			anyErr error
 			rsp    *protocol.Body
 		)
-		for range h.instances {
+		for range runtimes {
 			res := <-resCh
 			// Return the response of the instance that finished last. Note that currently all of
 			// the propagated methods return a `protocol.Empty` response so this does not matter.
@@ -120,119 +306,266 @@ func (h *lbHost) Call(ctx context.Context, body *protocol.Body) (*protocol.Body,
 		return rsp, nil
 	case body.RuntimeQueryRequest != nil, body.RuntimeCheckTxBatchRequest != nil:
 		// Load-balance queries.
-		idx, err := h.selectInstance()
+		inst, err := h.selectInstance(time.Now())
 		if err != nil {
 			return nil, err
 		}
 
-		lbRequestCount.With(prometheus.Labels{
-			labelRuntime:    h.id.String(),
-			labelLBInstance: fmt.Sprintf("%d", idx),
-		}).Inc()
+		lbRequestCount.With(h.instanceLabels(inst)).Inc()
+
+		start := time.Now()
+		rsp, err := inst.rt.Call(ctx, body)
+		h.finishRequest(inst, time.Now(), time.Since(start), err)
 
-		return h.instances[idx].Call(ctx, body)
+		return rsp, err
 	default:
 		// Propagate only to the first instance.
-		return h.instances[0].Call(ctx, body)
+		return h.primary.Call(ctx, body)
 	}
 }
 
-func (h *lbHost) selectInstance() (int, error) {
+// runtimes returns all instances that are currently part of the load balancer, including the
+// ones being drained.
+func (h *lbHost) runtimes() []host.Runtime {
 	h.l.Lock()
 	defer h.l.Unlock()
 
-	for attempt := 0; attempt < len(h.instances); attempt++ {
-		idx := h.nextIdx
-		h.nextIdx = (h.nextIdx + 1) % len(h.instances)
+	runtimes := make([]host.Runtime, 0, len(h.states))
+	for _, inst := range h.states {
+		runtimes = append(runtimes, inst.rt)
+	}
+	return runtimes
+}
+
+func (h *lbHost) instanceLabels(inst *instance) prometheus.Labels {
+	return prometheus.Labels{
+		labelRuntime:    h.id.String(),
+		labelLBInstance: inst.label,
+	}
+}
+
+// selectInstance selects the instance that should handle the next request and marks the request
+// as outstanding.
+func (h *lbHost) selectInstance(now time.Time) (*instance, error) {
+	h.l.Lock()
+	defer h.l.Unlock()
 
-		if _, healthy := h.healthyInstances[idx]; healthy {
-			return idx, nil
+	var candidates, ejected []*instance
+	for _, inst := range h.states {
+		if !inst.healthy || inst.draining {
+			continue
 		}
+		if inst.isEjected(now) {
+			ejected = append(ejected, inst)
+			continue
+		}
+		if !inst.ejectedUntil.IsZero() {
+			h.logger.Info("re-admitting instance after cooldown",
+				"instance", inst.idx,
+			)
+			inst.ejectedUntil = time.Time{}
+			lbInstanceEjected.With(h.instanceLabels(inst)).Set(0)
+		}
+		candidates = append(candidates, inst)
+	}
+	if len(candidates) == 0 {
+		// Prefer ejected instances over failing the request outright.
+		candidates = ejected
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("host/loadbalance: no healthy instances available")
 	}
 
-	return 0, fmt.Errorf("host/loadbalance: no healthy instances available")
+	inst := h.strategy.pick(candidates)
+	inst.outstanding++
+	lbOutstandingRequests.With(h.instanceLabels(inst)).Set(float64(inst.outstanding))
+
+	h.inFlight++
+	h.peakInFlight = max(h.peakInFlight, h.inFlight)
+
+	return inst, nil
+}
+
+// finishRequest updates the instance state after a request has been processed. Instances that
+// repeatedly fail are ejected until the cooldown elapses.
+func (h *lbHost) finishRequest(inst *instance, now time.Time, latency time.Duration, err error) {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	inst.outstanding--
+	h.inFlight--
+	if inst.draining && inst.outstanding == 0 {
+		close(inst.drainedCh)
+	}
+
+	// Metrics of removed instances have already been deleted.
+	metrics := !inst.removed
+	labels := h.instanceLabels(inst)
+	if metrics {
+		lbOutstandingRequests.With(labels).Set(float64(inst.outstanding))
+	}
+
+	switch {
+	case isInstanceFailure(err):
+		inst.failures++
+		if metrics {
+			lbRequestFailures.With(labels).Inc()
+		}
+
+		if h.cfg.EjectionThreshold == 0 || inst.failures < h.cfg.EjectionThreshold || inst.isEjected(now) {
+			return
+		}
+
+		h.logger.Warn("ejecting instance after repeated failures",
+			"instance", inst.idx,
+			"failures", inst.failures,
+			"cooldown", h.cfg.EjectionCooldown,
+			"err", err,
+		)
+		inst.ejectedUntil = now.Add(h.cfg.EjectionCooldown)
+		if metrics {
+			lbInstanceEjections.With(labels).Inc()
+			lbInstanceEjected.With(labels).Set(1)
+		}
+	case errors.Is(err, context.Canceled):
+		// Canceled requests say nothing about the instance.
+	default:
+		inst.failures = 0
+		if inst.latency == 0 {
+			inst.latency = latency
+		} else {
+			inst.latency = time.Duration(latencyDecay*float64(latency) + (1-latencyDecay)*float64(inst.latency))
+		}
+		if metrics {
+			lbRequestLatency.With(labels).Observe(latency.Seconds())
+		}
+	}
+}
+
+// setHealthy updates the health of the given instance and returns the number of healthy instances.
+func (h *lbHost) setHealthy(inst *instance, healthy bool) int {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	inst.healthy = healthy
+	inst.starting = false
+	if healthy {
+		// A (re)started instance starts with a clean slate.
+		inst.failures = 0
+		inst.ejectedUntil = time.Time{}
+		inst.latency = 0
+		if !inst.removed {
+			lbInstanceEjected.With(h.instanceLabels(inst)).Set(0)
+		}
+	}
+
+	var count int
+	for _, inst := range h.states {
+		if inst.healthy {
+			count++
+		}
+	}
+	return count
 }
 
 // Implements host.Runtime.
 func (h *lbHost) UpdateCapabilityTEE() {
-	for _, rt := range h.instances {
+	for _, rt := range h.runtimes() {
 		rt.UpdateCapabilityTEE()
 	}
 }
 
 // Implements host.Runtime.
 func (h *lbHost) WatchEvents() (<-chan *host.Event, pubsub.ClosableSubscription) {
-	return h.instances[0].WatchEvents()
+	return h.primary.WatchEvents()
 }
 
 // Implements host.Runtime.
 func (h *lbHost) Start() {
 	h.startOnce.Do(func() {
-		for idx, rt := range h.instances {
-			// Subscribe to runtime events before starting runtime to make sure we don't miss the
-			// started event.
-			evCh, sub := rt.WatchEvents()
+		h.l.Lock()
+		for _, inst := range h.states {
+			h.startInstanceLocked(inst)
+		}
+		h.updateInstanceCountLocked()
+		h.l.Unlock()
 
-			// Start a goroutine to monitor whether an instance is healthy.
-			go func() {
-				defer sub.Close()
-
-				for {
-					select {
-					case ev := <-evCh:
-						switch {
-						case ev.Started != nil:
-							// Mark instance as available.
-							h.logger.Info("instance is available",
-								"instance", idx,
-							)
-
-							h.l.Lock()
-							h.healthyInstances[idx] = struct{}{}
-							h.l.Unlock()
-						case ev.FailedToStart != nil, ev.Stopped != nil:
-							// Mark instance as failed.
-							h.logger.Warn("instance is no longer available",
-								"instance", idx,
-							)
-
-							h.l.Lock()
-							delete(h.healthyInstances, idx)
-							h.l.Unlock()
-						default:
-						}
-
-						// Update healthy instance count metrics.
-						h.l.Lock()
-						healthyInstanceCount := len(h.healthyInstances)
-						h.l.Unlock()
-
-						lbHealthyInstanceCount.With(prometheus.Labels{
-							"runtime": h.id.String(),
-						}).Set(float64(healthyInstanceCount))
-					case <-h.stopCh:
-						return
+		if h.factory != nil {
+			go h.autoscale()
+		}
+	})
+}
+
+// startInstanceLocked starts the given instance and a goroutine monitoring whether the instance
+// is healthy.
+func (h *lbHost) startInstanceLocked(inst *instance) {
+	// Subscribe to runtime events before starting runtime to make sure we don't miss the
+	// started event.
+	evCh, sub := inst.rt.WatchEvents()
+
+	go func() {
+		defer sub.Close()
+
+		for {
+			select {
+			case ev := <-evCh:
+				var healthy bool
+				switch {
+				case ev.Started != nil:
+					// Make sure the instance has not missed any propagated requests before
+					// marking it as available.
+					if err := h.replayPropagated(inst); err != nil {
+						h.logger.Error("failed to replay propagated requests to instance",
+							"instance", inst.idx,
+							"err", err,
+						)
+						break
 					}
+
+					// Mark instance as available.
+					h.logger.Info("instance is available",
+						"instance", inst.idx,
+					)
+					healthy = true
+				case ev.FailedToStart != nil, ev.Stopped != nil:
+					// Mark instance as failed.
+					h.logger.Warn("instance is no longer available",
+						"instance", inst.idx,
+					)
+				default:
+					continue
 				}
-			}()
 
-			rt.Start()
+				// Update healthy instance count metrics.
+				healthyInstanceCount := h.setHealthy(inst, healthy)
+
+				lbHealthyInstanceCount.With(prometheus.Labels{
+					labelRuntime: h.id.String(),
+				}).Set(float64(healthyInstanceCount))
+			case <-inst.quitCh:
+				return
+			case <-h.stopCh:
+				return
+			}
 		}
-	})
+	}()
+
+	inst.rt.Start()
 }
 
 // Implements host.Runtime.
 func (h *lbHost) Abort(ctx context.Context, force bool) error {
 	// We don't know which instance to abort, so we abort all instances.
+	runtimes := h.runtimes()
 	errCh := make(chan error)
-	for _, rt := range h.instances {
+	for _, rt := range runtimes {
 		go func() {
 			errCh <- rt.Abort(ctx, force)
 		}()
 	}
 
 	var anyErr error
-	for range h.instances {
+	for range runtimes {
 		err := <-errCh
 		anyErr = errors.Join(anyErr, err)
 	}
@@ -244,7 +577,7 @@ func (h *lbHost) Stop() {
 	h.stopOnce.Do(func() {
 		close(h.stopCh)
 
-		for _, rt := range h.instances {
+		for _, rt := range h.runtimes() {
 			rt.Stop()
 		}
 	})
@@ -0,0 +1,150 @@
+package loadbalance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	cmnErrors "github.com/oasisprotocol/oasis-core/go/common/errors"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+)
+
+func newTestHost(t *testing.T, strategy string, numInstances int) *lbHost {
+	h, err := NewHost(common.Namespace{}, make([]host.Runtime, numInstances), rtConfig.LoadBalancerConfig{
+		Strategy:          strategy,
+		EjectionThreshold: 2,
+		EjectionCooldown:  time.Minute,
+	})
+	require.NoError(t, err, "NewHost")
+
+	lbh := h.(*lbHost)
+	for _, inst := range lbh.states {
+		lbh.setHealthy(inst, true)
+	}
+	return lbh
+}
+
+func testSelect(t *testing.T, h *lbHost, now time.Time) int {
+	inst, err := h.selectInstance(now)
+	require.NoError(t, err, "selectInstance")
+	return inst.idx
+}
+
+func TestRoundRobin(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+
+	h := newTestHost(t, rtConfig.LoadBalancerStrategyRoundRobin, 3)
+	var selected []int
+	for range 4 {
+		selected = append(selected, testSelect(t, h, now))
+	}
+	require.Equal([]int{0, 1, 2, 0}, selected)
+
+	// Unhealthy instances are skipped.
+	h.setHealthy(h.states[1], false)
+	selected = nil
+	for range 3 {
+		selected = append(selected, testSelect(t, h, now))
+	}
+	require.Equal([]int{2, 0, 2}, selected)
+
+	for _, inst := range h.states {
+		h.setHealthy(inst, false)
+	}
+	_, err := h.selectInstance(now)
+	require.Error(err)
+}
+
+func TestLeastOutstanding(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+
+	h := newTestHost(t, rtConfig.LoadBalancerStrategyLeastOutstanding, 3)
+	require.Equal(0, testSelect(t, h, now))
+	require.Equal(1, testSelect(t, h, now))
+	require.Equal(2, testSelect(t, h, now))
+
+	// Finishing a request makes the instance the least loaded one.
+	h.finishRequest(h.states[1], now, time.Millisecond, nil)
+	require.Equal(1, testSelect(t, h, now))
+	require.Equal(1, h.states[1].outstanding)
+}
+
+func TestLatency(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+
+	h := newTestHost(t, rtConfig.LoadBalancerStrategyLatency, 3)
+
+	// Instances without samples are preferred.
+	for i, latency := range []time.Duration{25 * time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond} {
+		require.Equal(i, testSelect(t, h, now))
+		h.finishRequest(h.states[i], now, latency, nil)
+	}
+
+	// The fastest instance is preferred until its outstanding requests make it slower.
+	require.Equal(1, testSelect(t, h, now))
+	require.Equal(1, testSelect(t, h, now))
+	require.Equal(0, testSelect(t, h, now))
+	require.Equal(1, testSelect(t, h, now))
+
+	// Latency is a moving average.
+	h.finishRequest(h.states[1], now, 60*time.Millisecond, nil)
+	require.Equal(20*time.Millisecond, h.states[1].latency)
+}
+
+func TestEjection(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+
+	h := newTestHost(t, rtConfig.LoadBalancerStrategyRoundRobin, 2)
+	failure := fmt.Errorf("connection closed")
+
+	// Errors returned by the runtime and canceled requests are not failures.
+	require.Equal(0, testSelect(t, h, now))
+	h.finishRequest(h.states[0], now, 0, cmnErrors.New("test/loadbalance", 1, "query failed"))
+	require.Equal(1, testSelect(t, h, now))
+	h.finishRequest(h.states[1], now, 0, context.Canceled)
+	require.Zero(h.states[0].failures)
+	require.Zero(h.states[1].failures)
+
+	// Repeated failures eject the instance.
+	for range 2 {
+		require.Equal(0, testSelect(t, h, now))
+		h.finishRequest(h.states[0], now, 0, failure)
+		require.Equal(1, testSelect(t, h, now))
+		h.finishRequest(h.states[1], now, time.Millisecond, nil)
+	}
+	require.True(h.states[0].isEjected(now))
+	for range 3 {
+		require.Equal(1, testSelect(t, h, now))
+		h.finishRequest(h.states[1], now, time.Millisecond, nil)
+	}
+
+	// Ejected instances are used in case there is no other choice.
+	h.setHealthy(h.states[1], false)
+	require.Equal(0, testSelect(t, h, now))
+	h.finishRequest(h.states[0], now, 0, context.Canceled)
+	h.setHealthy(h.states[1], true)
+
+	// The instance is re-admitted after the cooldown, but a single failure ejects it again.
+	later := now.Add(2 * time.Minute)
+	require.Equal(1, testSelect(t, h, later))
+	require.Equal(0, testSelect(t, h, later))
+	require.Zero(h.states[0].ejectedUntil)
+	h.finishRequest(h.states[0], later, 0, context.DeadlineExceeded)
+	require.True(h.states[0].isEjected(later))
+
+	// A successful request resets the failure count.
+	evenLater := later.Add(2 * time.Minute)
+	require.Equal(1, testSelect(t, h, evenLater))
+	require.Equal(0, testSelect(t, h, evenLater))
+	h.finishRequest(h.states[0], evenLater, time.Millisecond, nil)
+	require.Zero(h.states[0].failures)
+}
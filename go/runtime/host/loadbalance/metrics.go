@@ -13,6 +13,8 @@ const (
 	labelRuntime = "runtime"
 	// labelLBInstance is the label for the load balancer instance.
 	labelLBInstance = "lb_instance"
+	// labelDirection is the label for the scaling direction.
+	labelDirection = "direction"
 )
 
 var (
@@ -30,9 +32,65 @@ var (
 		},
 		[]string{labelRuntime},
 	)
+	lbOutstandingRequests = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_client_lb_outstanding_requests",
+			Help: "Number of requests currently being processed by the given load balancer instance.",
+		},
+		[]string{labelRuntime, labelLBInstance},
+	)
+	lbRequestLatency = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name: "oasis_worker_client_lb_request_latency",
+			Help: "Latency of requests processed by the given load balancer instance (seconds).",
+		},
+		[]string{labelRuntime, labelLBInstance},
+	)
+	lbRequestFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_client_lb_request_failures",
+			Help: "Number of failed or timed out requests processed by the given load balancer instance.",
+		},
+		[]string{labelRuntime, labelLBInstance},
+	)
+	lbInstanceEjections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_client_lb_instance_ejections",
+			Help: "Number of times the given load balancer instance has been ejected.",
+		},
+		[]string{labelRuntime, labelLBInstance},
+	)
+	lbInstanceEjected = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_client_lb_instance_ejected",
+			Help: "1 if the given load balancer instance is currently ejected, 0 otherwise.",
+		},
+		[]string{labelRuntime, labelLBInstance},
+	)
+	lbInstanceCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_client_lb_instance_count",
+			Help: "Number of instances in the load balancer, including the ones being drained.",
+		},
+		[]string{labelRuntime},
+	)
+	lbScalingEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_client_lb_scaling_events",
+			Help: "Number of times the load balancer autoscaler added (up) or removed (down) an instance.",
+		},
+		[]string{labelRuntime, labelDirection},
+	)
 	nodeCollectors = []prometheus.Collector{
 		lbRequestCount,
 		lbHealthyInstanceCount,
+		lbOutstandingRequests,
+		lbRequestLatency,
+		lbRequestFailures,
+		lbInstanceEjections,
+		lbInstanceEjected,
+		lbInstanceCount,
+		lbScalingEvents,
 	}
 
 	metricsOnce sync.Once
@@ -4,17 +4,26 @@ import (
 	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 )
 
 type lbProvisioner struct {
 	inner        host.Provisioner
 	numInstances int
+	cfg          rtConfig.LoadBalancerConfig
 }
 
 // NewProvisioner creates a load-balancing runtime provisioner.
-func NewProvisioner(inner host.Provisioner, numInstances int) host.Provisioner {
-	if numInstances < 2 {
+//
+// In case autoscaling is enabled, the minimum number of instances is provisioned initially and
+// further instances are provisioned on demand using the inner provisioner.
+func NewProvisioner(inner host.Provisioner, cfg rtConfig.LoadBalancerConfig) host.Provisioner {
+	numInstances := int(cfg.NumInstances)
+	if cfg.Autoscaling.Enabled {
+		numInstances = int(cfg.Autoscaling.MinInstances)
+	}
+	if numInstances < 2 && !cfg.Autoscaling.Enabled {
 		// If there is only a single instance configured just return the inner provisioner.
 		return inner
 	}
@@ -24,12 +33,13 @@ func NewProvisioner(inner host.Provisioner, numInstances int) host.Provisioner {
 	return &lbProvisioner{
 		inner:        inner,
 		numInstances: numInstances,
+		cfg:          cfg,
 	}
 }
 
 // Implements host.Provisioner.
 func (p *lbProvisioner) NewRuntime(cfg host.Config) (host.Runtime, error) {
-	if p.numInstances < 2 {
+	if p.numInstances < 2 && !p.cfg.Autoscaling.Enabled {
 		// This should never happen as the provisioner constructor made sure, but just to be safe.
 		return nil, fmt.Errorf("host/loadbalance: number of instances must be at least two")
 	}
@@ -50,12 +60,20 @@ func (p *lbProvisioner) NewRuntime(cfg host.Config) (host.Runtime, error) {
 		instances = append(instances, rt)
 	}
 
-	host := NewHost(cfg.ID, instances)
-
-	return host, nil
+	factory := func() (host.Runtime, error) {
+		return p.inner.NewRuntime(cfg)
+	}
+	h, err := newHost(cfg.ID, instances, p.cfg, factory)
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
 }
 
 // Implements host.Provisioner.
 func (p *lbProvisioner) Name() string {
+	if p.cfg.Autoscaling.Enabled {
+		return fmt.Sprintf("load-balancer[%d-%d]/%s", p.numInstances, p.cfg.Autoscaling.MaxInstances, p.inner.Name())
+	}
 	return fmt.Sprintf("load-balancer[%d]/%s", p.numInstances, p.inner.Name())
 }
@@ -0,0 +1,90 @@
+package loadbalance
+
+import (
+	"fmt"
+
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+)
+
+// strategy selects the instance that should handle the next request.
+type strategy interface {
+	// pick selects one of the given candidate instances. Candidates are ordered by instance index
+	// and there is always at least one candidate.
+	pick(candidates []*instance) *instance
+}
+
+// newStrategy creates a new load balancing strategy with the given name.
+func newStrategy(name string) (strategy, error) {
+	switch name {
+	case "", rtConfig.LoadBalancerStrategyRoundRobin:
+		return &roundRobinStrategy{}, nil
+	case rtConfig.LoadBalancerStrategyLeastOutstanding:
+		return &leastOutstandingStrategy{}, nil
+	case rtConfig.LoadBalancerStrategyLatency:
+		return &latencyStrategy{}, nil
+	default:
+		return nil, fmt.Errorf("host/loadbalance: unknown strategy: %s", name)
+	}
+}
+
+// roundRobinStrategy selects instances in turn.
+type roundRobinStrategy struct {
+	nextIdx int
+}
+
+func (s *roundRobinStrategy) pick(candidates []*instance) *instance {
+	selected := candidates[0]
+	for _, inst := range candidates {
+		if inst.idx >= s.nextIdx {
+			selected = inst
+			break
+		}
+	}
+	s.nextIdx = selected.idx + 1
+	return selected
+}
+
+// leastOutstandingStrategy selects the instance with the least outstanding requests, breaking ties
+// in a round-robin fashion.
+type leastOutstandingStrategy struct {
+	rr roundRobinStrategy
+}
+
+func (s *leastOutstandingStrategy) pick(candidates []*instance) *instance {
+	var best []*instance
+	for _, inst := range candidates {
+		switch {
+		case len(best) == 0 || inst.outstanding < best[0].outstanding:
+			best = []*instance{inst}
+		case inst.outstanding == best[0].outstanding:
+			best = append(best, inst)
+		}
+	}
+	return s.rr.pick(best)
+}
+
+// latencyStrategy selects the instance with the lowest expected latency, estimated from the moving
+// average of observed request latencies and the number of outstanding requests. Instances without
+// any latency samples are preferred so that they get sampled. Ties are broken in a round-robin
+// fashion.
+type latencyStrategy struct {
+	rr roundRobinStrategy
+}
+
+func (s *latencyStrategy) pick(candidates []*instance) *instance {
+	var (
+		best      []*instance
+		bestScore float64
+	)
+	for _, inst := range candidates {
+		score := inst.latency.Seconds() * float64(inst.outstanding+1)
+		switch {
+		case len(best) == 0 || score < bestScore:
+			best = []*instance{inst}
+			bestScore = score
+		case score == bestScore:
+			best = append(best, inst)
+		}
+	}
+	return s.rr.pick(best)
+}
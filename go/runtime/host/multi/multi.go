@@ -29,6 +29,9 @@ var (
 
 	// ErrNoSuchVersion is the error returned if the requested version is unknown.
 	ErrNoSuchVersion = errors.New("runtime/host/multi: no such version")
+
+	// ErrStatusNotSupported is the error returned if the active version does not report status.
+	ErrStatusNotSupported = errors.New("runtime/host/multi: status not supported")
 )
 
 type aggregatedHost struct {
@@ -201,6 +204,19 @@ func (agg *Aggregate) GetCapabilityTEE() (*node.CapabilityTEE, error) {
 	return active.host.GetCapabilityTEE()
 }
 
+// GetStatus implements host.StatusReporter.
+func (agg *Aggregate) GetStatus() (*host.Status, error) {
+	active, err := agg.getActiveHost()
+	if err != nil {
+		return nil, err
+	}
+	sr, ok := active.host.(host.StatusReporter)
+	if !ok {
+		return nil, ErrStatusNotSupported
+	}
+	return sr.GetStatus()
+}
+
 // shouldPropagateToNextVersion checks whether the given runtime request should also be propagated
 // to the next version that is pending activation.
 func shouldPropagateToNextVersion(body *protocol.Body) bool {
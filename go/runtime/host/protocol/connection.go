@@ -157,6 +157,8 @@ type connection struct {
 
 	info *RuntimeInfoResponse
 
+	recorder *Recorder
+
 	readyCh chan struct{}
 	outCh   chan *Message
 	closeCh chan struct{}
@@ -215,6 +217,10 @@ func (c *connection) Close() {
 
 	// Wait for all the connection-handling goroutines to terminate.
 	c.quitWg.Wait()
+
+	if c.recorder != nil {
+		c.recorder.Close()
+	}
 }
 
 // Implements Connection.
@@ -329,6 +335,10 @@ func (c *connection) workerOutgoing() {
 					"err", err,
 				)
 			}
+			// Record the message before sending it so that the trace preserves causality.
+			if c.recorder != nil {
+				c.recorder.Record(TraceDirectionOutgoing, msg)
+			}
 			// Outgoing message, send it.
 			if err := c.codec.Write(msg); err != nil {
 				c.logger.Error("error while sending message",
@@ -434,6 +444,9 @@ func (c *connection) workerIncoming() {
 			)
 			break
 		}
+		if c.recorder != nil {
+			c.recorder.Record(TraceDirectionIncoming, &message)
+		}
 
 		// Handle message in a separate goroutine.
 		wg.Go(func() {
@@ -527,11 +540,22 @@ func (c *connection) InitHost(ctx context.Context, conn net.Conn, hi *HostInfo)
 	return &rtVersion, nil
 }
 
+// ConnectionOption is an option used when creating a new connection.
+type ConnectionOption func(c *connection)
+
+// WithRecorder configures the connection to record all exchanged messages using the given trace
+// recorder. The connection takes ownership of the recorder and closes it when it is closed.
+func WithRecorder(recorder *Recorder) ConnectionOption {
+	return func(c *connection) {
+		c.recorder = recorder
+	}
+}
+
 // NewConnection creates a new uninitialized RHP connection.
-func NewConnection(logger *logging.Logger, runtimeID common.Namespace, handler Handler) (Connection, error) {
+func NewConnection(logger *logging.Logger, runtimeID common.Namespace, handler Handler, opts ...ConnectionOption) (Connection, error) {
 	initMetrics()
 
-	return &connection{
+	c := &connection{
 		runtimeID:       runtimeID,
 		handler:         handler,
 		state:           stateUninitialized,
@@ -540,5 +564,9 @@ func NewConnection(logger *logging.Logger, runtimeID common.Namespace, handler H
 		outCh:           make(chan *Message),
 		closeCh:         make(chan struct{}),
 		logger:          logger,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
 }
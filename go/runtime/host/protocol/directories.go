@@ -0,0 +1,16 @@
+package protocol
+
+import (
+	"path/filepath"
+
+	"github.com/oasisprotocol/oasis-core/go/runtime/config"
+)
+
+// TracesDir is the name of the directory located inside the node's runtimes directory which
+// contains the Runtime Host Protocol message traces.
+const TracesDir = "traces"
+
+// GetTracesDir derives the path to the traces directory.
+func GetTracesDir(dataDir string) string {
+	return filepath.Join(dataDir, config.RuntimesDir, TracesDir)
+}
@@ -0,0 +1,242 @@
+package protocol
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+)
+
+// ReplayMismatch is a difference between a recorded and a replayed message.
+type ReplayMismatch struct {
+	// Type is the type of the request.
+	Type string `json:"type"`
+	// Runtime is true iff the request was sent by the runtime, otherwise it was sent by the host.
+	Runtime bool `json:"runtime"`
+	// Expected is the recorded message body.
+	Expected *Body `json:"expected"`
+	// Actual is the replayed message body.
+	Actual *Body `json:"actual"`
+}
+
+// ReplayResult is the result of replaying a recorded session.
+type ReplayResult struct {
+	// HostRequests is the number of replayed requests sent by the host.
+	HostRequests uint64 `json:"host_requests"`
+	// RuntimeRequests is the number of requests received from the runtime.
+	RuntimeRequests uint64 `json:"runtime_requests"`
+	// Mismatches are the differences between the recorded and the replayed session.
+	Mismatches []*ReplayMismatch `json:"mismatches,omitempty"`
+}
+
+type replayCall struct {
+	offset   time.Duration
+	request  *Body
+	response *Body
+}
+
+// Replayer replays a session recorded on the host side of a connection against a runtime.
+//
+// Requests sent by the host are replayed against the runtime and the responses are compared
+// with the recorded ones. Requests sent by the runtime are answered with the recorded responses,
+// matched by request type in the recorded order.
+type Replayer struct {
+	l sync.Mutex
+
+	runtimeID common.Namespace
+	hostInfo  *HostInfo
+	info      *RuntimeInfoResponse
+
+	hostCalls    []*replayCall
+	runtimeCalls map[string][]*replayCall
+
+	result ReplayResult
+}
+
+// NewReplayer creates a new replayer for the given recorded session.
+//
+// The records must start at the beginning of the session, i.e. with the runtime information
+// request sent by the host during connection initialization.
+func NewReplayer(records []*TraceRecord) (*Replayer, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("rhp/replay: empty trace")
+	}
+	first := records[0]
+	if first.Direction != TraceDirectionOutgoing || first.Message.MessageType != MessageRequest || first.Message.Body.RuntimeInfoRequest == nil {
+		return nil, fmt.Errorf("rhp/replay: trace does not start with a runtime info request sent by the host")
+	}
+	start := first.Timestamp
+
+	// Index requests and responses by direction and identifier.
+	var (
+		hostCalls    []*replayCall
+		runtimeCalls []*replayCall
+	)
+	hostPending := make(map[uint64]*replayCall)
+	runtimePending := make(map[uint64]*replayCall)
+	for _, rec := range records {
+		body := rec.Message.Body
+		call := &replayCall{
+			offset:  time.Duration(rec.Timestamp - start),
+			request: &body,
+		}
+
+		switch {
+		case rec.Message.MessageType == MessageRequest && rec.Direction == TraceDirectionOutgoing:
+			hostCalls = append(hostCalls, call)
+			hostPending[rec.Message.ID] = call
+		case rec.Message.MessageType == MessageRequest && rec.Direction == TraceDirectionIncoming:
+			runtimeCalls = append(runtimeCalls, call)
+			runtimePending[rec.Message.ID] = call
+		case rec.Message.MessageType == MessageResponse && rec.Direction == TraceDirectionIncoming:
+			if call, ok := hostPending[rec.Message.ID]; ok {
+				call.response = &body
+				delete(hostPending, rec.Message.ID)
+			}
+		case rec.Message.MessageType == MessageResponse && rec.Direction == TraceDirectionOutgoing:
+			if call, ok := runtimePending[rec.Message.ID]; ok {
+				call.response = &body
+				delete(runtimePending, rec.Message.ID)
+			}
+		}
+	}
+
+	rq := hostCalls[0].request.RuntimeInfoRequest
+	r := &Replayer{
+		runtimeID: rq.RuntimeID,
+		hostInfo: &HostInfo{
+			ConsensusBackend:         rq.ConsensusBackend,
+			ConsensusProtocolVersion: rq.ConsensusProtocolVersion,
+			ConsensusChainContext:    rq.ConsensusChainContext,
+			LocalConfig:              rq.LocalConfig,
+		},
+		runtimeCalls: make(map[string][]*replayCall),
+	}
+	if rsp := hostCalls[0].response; rsp != nil {
+		r.info = rsp.RuntimeInfoResponse
+	}
+	// Requests without a recorded response are still replayed but cannot be compared.
+	r.hostCalls = hostCalls[1:]
+	for _, call := range runtimeCalls {
+		if call.response == nil {
+			// The runtime was not answered before the trace ended.
+			continue
+		}
+		tp := call.request.Type()
+		r.runtimeCalls[tp] = append(r.runtimeCalls[tp], call)
+	}
+	return r, nil
+}
+
+// RuntimeID returns the identifier of the runtime from the recorded session.
+func (r *Replayer) RuntimeID() common.Namespace {
+	return r.runtimeID
+}
+
+// HostInfo returns the host information from the recorded session.
+func (r *Replayer) HostInfo() *HostInfo {
+	return r.hostInfo.Clone()
+}
+
+// Implements Handler.
+func (r *Replayer) Handle(_ context.Context, body *Body) (*Body, error) {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	r.result.RuntimeRequests++
+
+	tp := body.Type()
+	calls := r.runtimeCalls[tp]
+	if len(calls) == 0 {
+		r.result.Mismatches = append(r.result.Mismatches, &ReplayMismatch{
+			Type:    tp,
+			Runtime: true,
+			Actual:  body,
+		})
+		return nil, fmt.Errorf("rhp/replay: no recorded response for request %s", tp)
+	}
+	call := calls[0]
+	r.runtimeCalls[tp] = calls[1:]
+
+	r.compareLocked(tp, true, call.request, body)
+
+	// Return the recorded response as-is, including any errors.
+	return call.response, nil
+}
+
+// Replay replays the requests sent by the host against the runtime connected via the given
+// connection, which must already be initialized using the recorded host information.
+//
+// In case preserveTiming is true, requests are sent concurrently at the same offsets from the
+// start of the session as recorded. Otherwise they are sent sequentially.
+func (r *Replayer) Replay(ctx context.Context, conn Connection, preserveTiming bool) (*ReplayResult, error) {
+	if r.info != nil {
+		info, err := conn.GetInfo()
+		if err != nil {
+			return nil, fmt.Errorf("rhp/replay: failed to get runtime info: %w", err)
+		}
+
+		r.l.Lock()
+		expected := &Body{RuntimeInfoResponse: r.info}
+		r.compareLocked(expected.Type(), false, expected, &Body{RuntimeInfoResponse: info})
+		r.l.Unlock()
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, call := range r.hostCalls {
+		if !preserveTiming {
+			r.replayCall(ctx, conn, call)
+			continue
+		}
+
+		select {
+		case <-time.After(time.Until(start.Add(call.offset))):
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+		wg.Go(func() {
+			r.replayCall(ctx, conn, call)
+		})
+	}
+	wg.Wait()
+
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	result := r.result
+	return &result, nil
+}
+
+func (r *Replayer) replayCall(ctx context.Context, conn Connection, call *replayCall) {
+	rsp, err := conn.Call(ctx, call.request)
+	if err != nil {
+		rsp = errorToBody(err)
+	}
+
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	r.result.HostRequests++
+	if call.response == nil {
+		return
+	}
+	r.compareLocked(call.request.Type(), false, call.response, rsp)
+}
+
+func (r *Replayer) compareLocked(tp string, runtime bool, expected, actual *Body) {
+	if bytes.Equal(cbor.Marshal(expected), cbor.Marshal(actual)) {
+		return
+	}
+	r.result.Mismatches = append(r.result.Mismatches, &ReplayMismatch{
+		Type:     tp,
+		Runtime:  runtime,
+		Expected: expected,
+		Actual:   actual,
+	})
+}
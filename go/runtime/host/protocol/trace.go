@@ -0,0 +1,271 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+const (
+	// TraceFileExtension is the extension of trace files written by the recorder.
+	TraceFileExtension = ".trace"
+
+	traceModuleName      = "rhp/trace"
+	traceTimestampFormat = "20060102T150405.000000000"
+)
+
+// TraceDirection is the direction of a traced message from the point of view of the recording
+// side of the connection.
+type TraceDirection uint8
+
+const (
+	// TraceDirectionIncoming is the direction of messages received from the other side.
+	TraceDirectionIncoming TraceDirection = 0
+	// TraceDirectionOutgoing is the direction of messages sent to the other side.
+	TraceDirectionOutgoing TraceDirection = 1
+)
+
+// String returns a string representation of the trace direction.
+func (d TraceDirection) String() string {
+	switch d {
+	case TraceDirectionIncoming:
+		return "incoming"
+	case TraceDirectionOutgoing:
+		return "outgoing"
+	default:
+		return fmt.Sprintf("[malformed: %d]", d)
+	}
+}
+
+// TraceRecord is a single traced Runtime Host Protocol message.
+type TraceRecord struct {
+	// Timestamp is the time (in nanoseconds since the UNIX epoch) when the message was sent or
+	// received.
+	Timestamp int64 `json:"timestamp"`
+	// Direction is the direction of the message.
+	Direction TraceDirection `json:"direction"`
+	// Message is the traced message.
+	Message Message `json:"message"`
+}
+
+// RecorderConfig is the trace recorder configuration.
+type RecorderConfig struct {
+	// Dir is the directory where the trace files are stored.
+	Dir string
+	// MaxFileSize is the size in bytes after which a new trace file is started. Zero means that
+	// the trace files are never rotated.
+	MaxFileSize uint64
+	// MaxFiles is the maximum number of trace files kept in the directory. Zero means that old
+	// trace files are never removed.
+	//
+	// The first file of the current session is never removed as it contains the connection
+	// initialization required to replay the session.
+	MaxFiles uint64
+}
+
+// Recorder records Runtime Host Protocol messages into rotating trace files.
+//
+// Each recorder writes a separate session of trace files, named after the time the recorder was
+// created, followed by a sequence number. Files of a session should be replayed in lexicographic
+// order.
+type Recorder struct {
+	l sync.Mutex
+
+	cfg     RecorderConfig
+	session string
+	seq     uint64
+	size    uint64
+
+	file   *os.File
+	codec  *cbor.MessageCodec
+	closed bool
+
+	logger *logging.Logger
+}
+
+// NewRecorder creates a new trace recorder writing a new session into the configured directory.
+func NewRecorder(cfg RecorderConfig) (*Recorder, error) {
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("rhp/trace: failed to create trace directory: %w", err)
+	}
+
+	r := &Recorder{
+		cfg:     cfg,
+		session: time.Now().UTC().Format(traceTimestampFormat),
+		logger:  logging.GetLogger(traceModuleName).With("dir", cfg.Dir),
+	}
+	if err := r.openLocked(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Record records the given message.
+func (r *Recorder) Record(direction TraceDirection, msg *Message) {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	if r.closed {
+		return
+	}
+
+	rec := TraceRecord{
+		Timestamp: time.Now().UnixNano(),
+		Direction: direction,
+		Message:   *msg,
+	}
+	if err := r.codec.Write(&rec); err != nil {
+		r.logger.Error("failed to write trace record",
+			"err", err,
+		)
+		return
+	}
+
+	pos, err := r.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		r.logger.Error("failed to determine trace file size",
+			"err", err,
+		)
+		return
+	}
+	r.size = uint64(pos)
+
+	if r.cfg.MaxFileSize == 0 || r.size < r.cfg.MaxFileSize {
+		return
+	}
+
+	// Rotate the trace file.
+	_ = r.file.Close()
+	r.seq++
+	if err = r.openLocked(); err != nil {
+		r.logger.Error("failed to rotate trace file, tracing stopped",
+			"err", err,
+		)
+		r.closed = true
+	}
+}
+
+// Close flushes and closes the trace file. Any further messages are ignored.
+func (r *Recorder) Close() {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	if r.closed {
+		return
+	}
+	r.closed = true
+
+	if err := r.file.Close(); err != nil {
+		r.logger.Error("failed to close trace file",
+			"err", err,
+		)
+	}
+}
+
+func (r *Recorder) fileName(seq uint64) string {
+	return fmt.Sprintf("%s-%04d%s", r.session, seq, TraceFileExtension)
+}
+
+func (r *Recorder) openLocked() error {
+	fn := filepath.Join(r.cfg.Dir, r.fileName(r.seq))
+	f, err := os.OpenFile(fn, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("rhp/trace: failed to create trace file: %w", err)
+	}
+
+	r.file = f
+	r.codec = cbor.NewMessageCodec(f, traceModuleName)
+	r.size = 0
+
+	r.pruneLocked()
+	return nil
+}
+
+// pruneLocked removes the oldest trace files in case there are more than the configured maximum.
+// The first file of the current session is always kept.
+func (r *Recorder) pruneLocked() {
+	if r.cfg.MaxFiles == 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(r.cfg.Dir)
+	if err != nil {
+		r.logger.Warn("failed to list trace files",
+			"err", err,
+		)
+		return
+	}
+
+	var (
+		files     []string
+		total     uint64
+		sessionFn = r.fileName(0)
+	)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !strings.HasSuffix(entry.Name(), TraceFileExtension) {
+			continue
+		}
+		total++
+		if entry.Name() == sessionFn {
+			continue
+		}
+		files = append(files, entry.Name())
+	}
+	if total <= r.cfg.MaxFiles {
+		return
+	}
+	slices.Sort(files)
+
+	// Never remove the file that is currently being written.
+	n := min(total-r.cfg.MaxFiles, uint64(len(files)))
+	if n == uint64(len(files)) && r.seq > 0 {
+		n--
+	}
+	for _, fn := range files[:n] {
+		if err = os.Remove(filepath.Join(r.cfg.Dir, fn)); err != nil {
+			r.logger.Warn("failed to remove old trace file",
+				"file", fn,
+				"err", err,
+			)
+		}
+	}
+}
+
+// ReadTrace reads all trace records from the given trace files, in order.
+func ReadTrace(paths ...string) ([]*TraceRecord, error) {
+	var records []*TraceRecord
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("rhp/trace: failed to open trace file: %w", err)
+		}
+
+		codec := cbor.NewMessageCodec(f, traceModuleName)
+		for {
+			var rec TraceRecord
+			if err = codec.Read(&rec); err != nil {
+				break
+			}
+			records = append(records, &rec)
+		}
+		f.Close()
+
+		switch {
+		case errors.Is(err, io.EOF):
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			// The last record may be truncated in case the node was terminated while writing it.
+		default:
+			return nil, fmt.Errorf("rhp/trace: malformed trace file '%s': %w", path, err)
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,184 @@
+package protocol
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+type mutatingHandler struct {
+	testHandler
+}
+
+// Implements Handler.
+func (h *mutatingHandler) Handle(ctx context.Context, body *Body) (*Body, error) {
+	if body.RuntimeRPCCallRequest != nil {
+		return &Body{RuntimeRPCCallRequest: &RuntimeRPCCallRequest{Request: []byte("mutated")}}, nil
+	}
+	return h.testHandler.Handle(ctx, body)
+}
+
+func TestTraceRecordReplay(t *testing.T) {
+	require := require.New(t)
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("test conn"), 0)
+	logger := logging.GetLogger("test")
+	dir := t.TempDir()
+
+	// Record a session on the host side.
+	recorder, err := NewRecorder(RecorderConfig{Dir: dir})
+	require.NoError(err, "NewRecorder")
+
+	connA, connB := net.Pipe()
+	guest, err := NewConnection(logger, runtimeID, &testHandler{})
+	require.NoError(err, "NewConnection")
+	host, err := NewConnection(logger, runtimeID, &testHandler{}, WithRecorder(recorder))
+	require.NoError(err, "NewConnection")
+
+	err = guest.InitGuest(connA)
+	require.NoError(err, "InitGuest")
+	_, err = host.InitHost(context.Background(), connB, &HostInfo{ConsensusBackend: "test"})
+	require.NoError(err, "InitHost")
+
+	rpcReq := &Body{RuntimeRPCCallRequest: &RuntimeRPCCallRequest{Request: []byte("host")}}
+	_, err = host.Call(context.Background(), rpcReq)
+	require.NoError(err, "host.Call")
+	guestReq := &Body{RuntimeRPCCallRequest: &RuntimeRPCCallRequest{Request: []byte("guest")}}
+	_, err = guest.Call(context.Background(), guestReq)
+	require.NoError(err, "guest.Call")
+
+	guest.Close()
+	host.Close()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+TraceFileExtension))
+	require.NoError(err)
+	require.Len(files, 1, "a single trace file should be written")
+	records, err := ReadTrace(files...)
+	require.NoError(err, "ReadTrace")
+	require.Len(records, 6, "all messages should be recorded")
+
+	replay := func(guestHandler Handler) *ReplayResult {
+		replayer, err := NewReplayer(records)
+		require.NoError(err, "NewReplayer")
+		require.Equal(runtimeID, replayer.RuntimeID())
+		require.Equal("test", replayer.HostInfo().ConsensusBackend)
+
+		connA, connB := net.Pipe()
+		guest, err := NewConnection(logger, runtimeID, guestHandler)
+		require.NoError(err, "NewConnection")
+		host, err := NewConnection(logger, replayer.RuntimeID(), replayer)
+		require.NoError(err, "NewConnection")
+		defer guest.Close()
+		defer host.Close()
+
+		err = guest.InitGuest(connA)
+		require.NoError(err, "InitGuest")
+		_, err = host.InitHost(context.Background(), connB, replayer.HostInfo())
+		require.NoError(err, "InitHost")
+
+		// Runtime requests are answered with recorded responses.
+		rsp, err := guest.Call(context.Background(), guestReq)
+		require.NoError(err, "guest.Call")
+		require.EqualValues(guestReq, rsp)
+
+		result, err := replayer.Replay(context.Background(), host, false)
+		require.NoError(err, "Replay")
+		require.EqualValues(1, result.HostRequests)
+		require.EqualValues(1, result.RuntimeRequests)
+		return result
+	}
+
+	// Replaying against an identical runtime results in no mismatches.
+	result := replay(&testHandler{})
+	require.Empty(result.Mismatches)
+
+	// Replaying against a different runtime reports the mismatch.
+	result = replay(&mutatingHandler{})
+	require.Len(result.Mismatches, 1)
+	require.Equal("RuntimeRPCCallRequest", result.Mismatches[0].Type)
+	require.False(result.Mismatches[0].Runtime)
+}
+
+func TestTraceRotation(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	recorder, err := NewRecorder(RecorderConfig{
+		Dir:         dir,
+		MaxFileSize: 1,
+		MaxFiles:    3,
+	})
+	require.NoError(err, "NewRecorder")
+
+	for i := range 5 {
+		recorder.Record(TraceDirectionOutgoing, &Message{ID: uint64(i), Body: Body{Empty: &Empty{}}})
+	}
+	recorder.Close()
+	recorder.Record(TraceDirectionOutgoing, &Message{ID: 5, Body: Body{Empty: &Empty{}}})
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(err)
+	require.Len(entries, 3, "old trace files should be removed")
+
+	var files []string
+	for _, entry := range entries {
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	records, err := ReadTrace(files...)
+	require.NoError(err, "ReadTrace")
+	require.Len(records, 2, "each record should be rotated into a new file")
+	require.EqualValues(0, records[0].Message.ID, "the first session file should be kept")
+	require.EqualValues(4, records[1].Message.ID)
+}
+
+func TestTraceRotationReplay(t *testing.T) {
+	require := require.New(t)
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("test conn"), 0)
+	logger := logging.GetLogger("test")
+	dir := t.TempDir()
+
+	// Record a session that rotates past the maximum number of files.
+	recorder, err := NewRecorder(RecorderConfig{
+		Dir:         dir,
+		MaxFileSize: 1,
+		MaxFiles:    3,
+	})
+	require.NoError(err, "NewRecorder")
+
+	connA, connB := net.Pipe()
+	guest, err := NewConnection(logger, runtimeID, &testHandler{})
+	require.NoError(err, "NewConnection")
+	host, err := NewConnection(logger, runtimeID, &testHandler{}, WithRecorder(recorder))
+	require.NoError(err, "NewConnection")
+
+	err = guest.InitGuest(connA)
+	require.NoError(err, "InitGuest")
+	_, err = host.InitHost(context.Background(), connB, &HostInfo{ConsensusBackend: "test"})
+	require.NoError(err, "InitHost")
+
+	for range 5 {
+		_, err = host.Call(context.Background(), &Body{RuntimeRPCCallRequest: &RuntimeRPCCallRequest{Request: []byte("host")}})
+		require.NoError(err, "host.Call")
+	}
+
+	guest.Close()
+	host.Close()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+TraceFileExtension))
+	require.NoError(err)
+	require.Len(files, 3, "old trace files should be removed")
+
+	// The remaining files should still be replayable.
+	records, err := ReadTrace(files...)
+	require.NoError(err, "ReadTrace")
+	replayer, err := NewReplayer(records)
+	require.NoError(err, "NewReplayer")
+	require.Equal(runtimeID, replayer.RuntimeID())
+	require.Equal("test", replayer.HostInfo().ConsensusBackend)
+}
@@ -2,6 +2,7 @@ package provisioner
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/persistent"
@@ -18,6 +19,7 @@ import (
 	hostMock "github.com/oasisprotocol/oasis-core/go/runtime/host/mock"
 	hostProtocol "github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
 	hostSandbox "github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox"
+	hostProcess "github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox/process"
 	hostSgx "github.com/oasisprotocol/oasis-core/go/runtime/host/sgx"
 	hostTdx "github.com/oasisprotocol/oasis-core/go/runtime/host/tdx"
 )
@@ -83,6 +85,7 @@ func createProvisioner(
 	var insecureNoSandbox bool
 
 	attestInterval := config.GlobalConfig.Runtime.AttestInterval
+	newTraceRecorder := newTraceRecorderFunc(dataDir)
 	sandboxBinary := config.GlobalConfig.Runtime.SandboxBinary
 	sgxLoader := config.GlobalConfig.Runtime.SGX.Loader
 	if sgxLoader == "" {
@@ -123,6 +126,9 @@ func createProvisioner(
 			HostInfo:          hostInfo,
 			InsecureNoSandbox: insecureNoSandbox,
 			SandboxBinaryPath: sandboxBinary,
+			GetCgroupConfig:   getCgroupConfig,
+			NewTraceRecorder:  newTraceRecorder,
+			Restart:           config.GlobalConfig.Runtime.Restart,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to create runtime provisioner: %w", err)
@@ -146,6 +152,9 @@ func createProvisioner(
 			QuotePolicy:           policyProvider,
 			Identity:              identity,
 			SandboxBinaryPath:     sandboxBinary,
+			GetCgroupConfig:       getCgroupConfig,
+			NewTraceRecorder:      newTraceRecorder,
+			Restart:               config.GlobalConfig.Runtime.Restart,
 			InsecureNoSandbox:     insecureNoSandbox,
 			InsecureMock:          insecureMock,
 			RuntimeAttestInterval: attestInterval,
@@ -176,6 +185,8 @@ func createProvisioner(
 		Identity:              identity,
 		CidPool:               cidPool,
 		RuntimeAttestInterval: attestInterval,
+		Restart:               config.GlobalConfig.Runtime.Restart,
+		NewTraceRecorder:      newTraceRecorder,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create TDX runtime provisioner: %w", err)
@@ -183,8 +194,7 @@ func createProvisioner(
 
 	// Configure optional load balancing.
 	for tee, rp := range provisioners {
-		numInstances := int(config.GlobalConfig.Runtime.LoadBalancer.NumInstances)
-		provisioners[tee] = hostLoadBalance.NewProvisioner(rp, numInstances)
+		provisioners[tee] = hostLoadBalance.NewProvisioner(rp, config.GlobalConfig.Runtime.LoadBalancer)
 	}
 
 	// Create a composite provisioner to provision the individual components.
@@ -192,3 +202,59 @@ func createProvisioner(
 
 	return provisioner, nil
 }
+
+// getCgroupConfig returns the cgroup configuration of the given runtime component based on the
+// configured resource limits.
+func getCgroupConfig(cfg runtimeHost.Config) (*hostProcess.CgroupConfig, error) {
+	compCfg, ok := config.GlobalConfig.Runtime.GetComponent(cfg.ID, cfg.Component.ID())
+	if !ok || compCfg.Resources.IsEmpty() {
+		return nil, nil
+	}
+	cgroupRoot := config.GlobalConfig.Runtime.CgroupRoot
+	if cgroupRoot == "" {
+		return nil, fmt.Errorf("cgroup_root must be set when resource limits are configured")
+	}
+
+	var memoryMax uint64
+	if mem := compCfg.Resources.Memory; mem != "" {
+		memoryMax = uint64(config.ParseSizeInBytes(mem))
+		if memoryMax == 0 {
+			return nil, fmt.Errorf("malformed memory limit: %s", mem)
+		}
+	}
+
+	compID, err := cfg.Component.ID().MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return &hostProcess.CgroupConfig{
+		Root:      cgroupRoot,
+		Name:      fmt.Sprintf("%s-%s", cfg.ID, compID),
+		MemoryMax: memoryMax,
+		CPUMax:    compCfg.Resources.CPU,
+		PidsMax:   compCfg.Resources.Pids,
+	}, nil
+}
+
+// newTraceRecorderFunc returns a function that creates trace recorders for runtime components
+// in case tracing is enabled.
+func newTraceRecorderFunc(dataDir string) hostSandbox.NewTraceRecorderFunc {
+	return func(cfg runtimeHost.Config) (*hostProtocol.Recorder, error) {
+		traceCfg := config.GlobalConfig.Runtime.Trace
+		if !traceCfg.Enabled {
+			return nil, nil
+		}
+
+		compID, err := cfg.Component.ID().MarshalText()
+		if err != nil {
+			return nil, err
+		}
+
+		return hostProtocol.NewRecorder(hostProtocol.RecorderConfig{
+			Dir:         filepath.Join(hostProtocol.GetTracesDir(dataDir), cfg.ID.String(), string(compID)),
+			MaxFileSize: traceCfg.MaxFileSize,
+			MaxFiles:    traceCfg.MaxFiles,
+		})
+	}
+}
@@ -32,8 +32,6 @@ const (
 	stopTickerTimeout          = 15 * time.Minute
 	watchdogInterval           = 15 * time.Second
 	watchdogPingTimeout        = 5 * time.Second
-	restartMaxInterval         = time.Hour
-	restartMaxElapsedTime      = 24 * time.Hour
 
 	ctrlChannelBufferSize = 16
 )
@@ -65,7 +63,16 @@ type sandboxHost struct {
 
 	startOne cmSync.One
 	ctrlCh   chan any
-
+	restarts *restartTracker
+	// halted is true iff the runtime is not running and is no longer being restarted.
+	halted bool
+	// crashLooping is true iff restarts were halted due to repeated crashes.
+	crashLooping bool
+	// crashes is the number of times the runtime terminated unexpectedly or failed to start.
+	crashes uint64
+
+	// process is the running runtime process. It is only modified by the manager goroutine while
+	// holding the lock so the manager goroutine may read it without holding the lock.
 	process  process.Process
 	conn     protocol.Connection
 	notifier *pubsub.Broker
@@ -75,6 +82,9 @@ type sandboxHost struct {
 
 	rtVersion *version.Version
 
+	startedAt  time.Time
+	attestedAt time.Time
+
 	logger *logging.Logger
 }
 
@@ -115,6 +125,44 @@ func (h *sandboxHost) GetCapabilityTEE() (*node.CapabilityTEE, error) {
 	return h.capabilityTEE, nil
 }
 
+// GetStatus implements host.StatusReporter.
+func (h *sandboxHost) GetStatus() (*host.Status, error) {
+	h.RLock()
+	defer h.RUnlock()
+
+	status := host.Status{
+		Crashes: h.crashes,
+	}
+	switch {
+	case h.conn != nil:
+		status.State = host.StateRunning
+		status.StartedAt = h.startedAt
+		status.AttestedAt = h.attestedAt
+		if h.capabilityTEE != nil {
+			rak := h.capabilityTEE.RAK
+			status.RAK = &rak
+		}
+	case h.halted && h.crashLooping:
+		status.State = host.StateCrashLooping
+	case h.halted:
+		status.State = host.StateStopped
+	default:
+		status.State = host.StateStarting
+	}
+
+	if h.process != nil && h.cfg.GetResourceUsage != nil {
+		resources, err := h.cfg.GetResourceUsage(h.process)
+		if err != nil {
+			h.logger.Warn("failed to get runtime resource usage",
+				"err", err,
+			)
+		}
+		status.Resources = resources
+	}
+
+	return &status, nil
+}
+
 // Implements host.Runtime.
 func (h *sandboxHost) Call(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
 	conn, err := h.getConnection(ctx)
@@ -175,9 +223,10 @@ func (h *sandboxHost) Start() {
 
 // Implements host.Runtime.
 func (h *sandboxHost) Abort(ctx context.Context, force bool) error {
-	// Ignore abort requests when connection is not available.
+	// Ignore abort requests when connection is not available, unless restarts have been halted
+	// in which case an abort request can be used to restart the runtime.
 	h.RLock()
-	if h.conn == nil {
+	if h.conn == nil && !h.halted {
 		h.RUnlock()
 		return nil
 	}
@@ -235,6 +284,11 @@ func (h *sandboxHost) startProcess(ctx context.Context) (err error) {
 		// Make sure the process gets killed in case of errors.
 		if !ok && p != nil {
 			p.Kill()
+
+			// Report processes killed due to exceeding their memory limit during startup.
+			if perr := p.Error(); errors.Is(perr, process.ErrOutOfMemory) {
+				err = fmt.Errorf("%w (%w)", err, perr)
+			}
 		}
 	}()
 
@@ -245,6 +299,11 @@ func (h *sandboxHost) startProcess(ctx context.Context) (err error) {
 	if err = connector.Configure(&h.rtCfg, &cfg); err != nil {
 		return err
 	}
+	if h.cfg.GetCgroupConfig != nil {
+		if cfg.Cgroup, err = h.cfg.GetCgroupConfig(h.rtCfg); err != nil {
+			return fmt.Errorf("failed to configure cgroup: %w", err)
+		}
+	}
 
 	switch h.cfg.InsecureNoSandbox {
 	case true:
@@ -278,7 +337,18 @@ func (h *sandboxHost) startProcess(ctx context.Context) (err error) {
 		"pid", p.GetPID(),
 	)
 
-	pc, err := protocol.NewConnection(h.logger, h.id, h.rtCfg.MessageHandler)
+	var connOpts []protocol.ConnectionOption
+	if h.cfg.NewTraceRecorder != nil {
+		recorder, rerr := h.cfg.NewTraceRecorder(h.rtCfg)
+		if rerr != nil {
+			return fmt.Errorf("failed to create trace recorder: %w", rerr)
+		}
+		if recorder != nil {
+			connOpts = append(connOpts, protocol.WithRecorder(recorder))
+		}
+	}
+
+	pc, err := protocol.NewConnection(h.logger, h.id, h.rtCfg.MessageHandler, connOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to create connection: %w", err)
 	}
@@ -329,11 +399,16 @@ func (h *sandboxHost) startProcess(ctx context.Context) (err error) {
 	}
 
 	ok = true
-	h.process = p
+	now := time.Now()
 	h.Lock()
+	h.process = p
 	h.conn = pc
 	h.capabilityTEE = ev.CapabilityTEE
 	h.rtVersion = rtVersion
+	h.startedAt = now
+	if ev.CapabilityTEE != nil {
+		h.attestedAt = now
+	}
 	h.Unlock()
 
 	// Ensure the command queue is empty to avoid processing any stale requests after the
@@ -392,8 +467,8 @@ func (h *sandboxHost) handleAbortRequest(ctx context.Context, rq *abortRequest)
 	// Remove the process so it will be respanwed (it would be respawned either way, but with an
 	// additional "unexpected termination" message).
 	h.conn.Close()
-	h.process = nil
 	h.Lock()
+	h.process = nil
 	h.conn = nil
 	h.capabilityTEE = nil
 	h.rtVersion = nil
@@ -437,9 +512,9 @@ func (h *sandboxHost) manager(ctx context.Context) {
 			h.conn.Close()
 			h.process.Kill()
 			<-h.process.Wait()
-			h.process = nil
 
 			h.Lock()
+			h.process = nil
 			h.conn = nil
 			h.capabilityTEE = nil
 			h.Unlock()
@@ -468,6 +543,17 @@ func (h *sandboxHost) manager(ctx context.Context) {
 		default:
 		}
 
+		// Wait for an explicit restart in case the restart policy no longer allows restarts.
+		if h.process == nil && h.restarts.halted {
+			if ticker != nil {
+				ticker.Stop()
+				ticker = nil
+			}
+			if !h.waitRestart(ctx) {
+				return
+			}
+		}
+
 		// Make sure to restart the process if terminated.
 		if h.process == nil {
 			firstTickCh := make(chan struct{}, 1)
@@ -475,10 +561,7 @@ func (h *sandboxHost) manager(ctx context.Context) {
 				// Initialize a ticker for restarting the process. We use a separate channel
 				// to restart the process immediately on the first run, as we don't want to wait
 				// for the first tick.
-				ticker = backoff.NewTicker(backoff.NewExponentialBackOff(
-					backoff.WithMaxInterval(restartMaxInterval),
-					backoff.WithMaxElapsedTime(restartMaxElapsedTime),
-				))
+				ticker = backoff.NewTicker(h.restarts.newBackOff())
 				firstTickCh <- struct{}{}
 				attempt = 0
 			}
@@ -513,6 +596,7 @@ func (h *sandboxHost) manager(ctx context.Context) {
 					},
 				})
 
+				h.handleCrash(err)
 				continue
 			}
 
@@ -539,20 +623,24 @@ func (h *sandboxHost) manager(ctx context.Context) {
 			return
 		case <-h.process.Wait():
 			// Process has terminated.
+			perr := h.process.Error()
 			h.logger.Error("runtime process has terminated unexpectedly",
-				"err", h.process.Error(),
+				"err", perr,
+				"out_of_memory", errors.Is(perr, process.ErrOutOfMemory),
 			)
 
 			h.conn.Close()
-			h.process = nil
 			h.Lock()
+			h.process = nil
 			h.conn = nil
 			h.capabilityTEE = nil
 			h.rtVersion = nil
 			h.Unlock()
 
 			// Notify subscribers that the runtime has stopped.
-			h.notifier.Broadcast(&host.Event{Stopped: &host.StoppedEvent{}})
+			h.notifier.Broadcast(&host.Event{Stopped: &host.StoppedEvent{Error: perr}})
+
+			h.handleCrash(perr)
 		case <-stopTickerCh:
 			// Stop the ticker if things work smoothly. Otherwise, keep on using the old ticker as
 			// it can happen that the runtime constantly terminates after a successful start.
@@ -560,11 +648,15 @@ func (h *sandboxHost) manager(ctx context.Context) {
 				ticker.Stop()
 				ticker = nil
 			}
+			h.restarts.stable()
 		case ev := <-evCh:
 			// Update runtime's CapabilityTEE in case this is an update event.
 			if ue := ev.Updated; ue != nil {
 				h.Lock()
 				h.capabilityTEE = ue.CapabilityTEE
+				if ue.CapabilityTEE != nil {
+					h.attestedAt = time.Now()
+				}
 				h.Unlock()
 			}
 		case <-watchdogCh:
@@ -573,3 +665,77 @@ func (h *sandboxHost) manager(ctx context.Context) {
 		}
 	}
 }
+
+// handleCrash records a crash of the runtime and halts restarts in case the restart policy no
+// longer allows them.
+func (h *sandboxHost) handleCrash(err error) {
+	h.Lock()
+	h.crashes++
+	h.Unlock()
+
+	if h.restarts.crashed(err) {
+		return
+	}
+
+	h.Lock()
+	h.halted = true
+	h.crashLooping = h.restarts.crashLooping
+	h.Unlock()
+
+	if !h.restarts.crashLooping {
+		h.logger.Warn("not restarting runtime due to restart policy",
+			"policy", h.cfg.Restart.Policy,
+		)
+		return
+	}
+
+	h.logger.Error("runtime is crash looping, not restarting until explicitly restarted",
+		"crashes", h.restarts.consecutive,
+		"err", err,
+	)
+
+	// Notify subscribers that the runtime is crash looping.
+	h.notifier.Broadcast(&host.Event{
+		CrashLooping: &host.CrashLoopingEvent{
+			Crashes: h.restarts.consecutive,
+			Error:   err,
+		},
+	})
+}
+
+// waitRestart waits for a forced abort request which restarts the runtime after restarts have
+// been halted. It returns false in case the manager should terminate instead.
+func (h *sandboxHost) waitRestart(ctx context.Context) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			h.logger.Warn("termination requested")
+			return false
+		case grq := <-h.ctrlCh:
+			rq, ok := grq.(*abortRequest)
+			if !ok {
+				h.logger.Error("received unknown request type",
+					"request_type", fmt.Sprintf("%T", grq),
+				)
+				continue
+			}
+			if !rq.force {
+				rq.ch <- fmt.Errorf("runtime is not running")
+				close(rq.ch)
+				continue
+			}
+
+			h.logger.Info("restarting runtime on request")
+
+			h.restarts.reset()
+			h.Lock()
+			h.halted = false
+			h.crashLooping = false
+			h.Unlock()
+
+			rq.ch <- nil
+			close(rq.ch)
+			return true
+		}
+	}
+}
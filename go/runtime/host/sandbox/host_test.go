@@ -0,0 +1,49 @@
+package sandbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+)
+
+func TestHostStatus(t *testing.T) {
+	require := require.New(t)
+
+	restart := rtConfig.RestartConfig{
+		Policy:             rtConfig.RestartPolicyAlways,
+		CrashLoopThreshold: 2,
+	}
+	h := &sandboxHost{
+		cfg:      Config{Restart: restart},
+		restarts: newRestartTracker(restart),
+		notifier: pubsub.NewBroker(false),
+		logger:   logging.GetLogger("runtime/host/sandbox/test"),
+	}
+
+	status, err := h.GetStatus()
+	require.NoError(err, "GetStatus")
+	require.Equal(host.StateStarting, status.State)
+	require.EqualValues(0, status.Crashes)
+
+	h.handleCrash(errors.New("crash"))
+	status, err = h.GetStatus()
+	require.NoError(err, "GetStatus")
+	require.Equal(host.StateStarting, status.State)
+	require.EqualValues(1, status.Crashes)
+
+	h.handleCrash(errors.New("crash"))
+	status, err = h.GetStatus()
+	require.NoError(err, "GetStatus")
+	require.Equal(host.StateCrashLooping, status.State)
+	require.EqualValues(2, status.Crashes)
+	require.Zero(status.Uptime(time.Now()))
+	require.Nil(status.Resources)
+	require.Nil(status.RAK)
+}
@@ -193,6 +193,7 @@ func NewBubbleWrap(cfg Config) (Process, error) {
 		Args:   cliArgs,
 		Stdout: cfg.Stdout,
 		Stderr: cfg.Stderr,
+		Cgroup: cfg.Cgroup,
 		// Pass all the pipe file descriptors.
 		// NOTE: Entry i becomes file descriptor 3+i.
 		extraFiles: fdPipes.pipes,
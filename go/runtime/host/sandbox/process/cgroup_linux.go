@@ -0,0 +1,136 @@
+package process
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// cgroupCPUPeriod is the cpu.max period in microseconds.
+	cgroupCPUPeriod = 100_000
+	// cgroupMinCPUQuota is the minimum cpu.max quota in microseconds accepted by the kernel.
+	cgroupMinCPUQuota = 1_000
+
+	cgroupRemoveRetries  = 10
+	cgroupRemoveInterval = 10 * time.Millisecond
+)
+
+// cgroup is a cgroup v2 confining a single process and all of its descendants.
+type cgroup struct {
+	path string
+	dir  *os.File
+}
+
+// newCgroup creates a new cgroup with the given resource limits.
+func newCgroup(cfg *CgroupConfig) (*cgroup, error) {
+	if cfg.Root == "" {
+		// Never fall back to the default temporary directory, which is not a cgroup.
+		return nil, fmt.Errorf("failed to create cgroup: cgroup root not configured")
+	}
+
+	path, err := os.MkdirTemp(cfg.Root, cfg.Name+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cgroup: %w", err)
+	}
+	cg := &cgroup{path: path}
+
+	var ok bool
+	defer func() {
+		if !ok {
+			cg.destroy()
+		}
+	}()
+
+	if cfg.MemoryMax > 0 {
+		if err = cg.write("memory.max", strconv.FormatUint(cfg.MemoryMax, 10)); err != nil {
+			return nil, err
+		}
+		// Prevent the limit from being circumvented by swapping. The file is missing in case swap
+		// accounting is not enabled.
+		if err = cg.write("memory.swap.max", "0"); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if cfg.CPUMax > 0 {
+		quota := max(int64(cfg.CPUMax*cgroupCPUPeriod), cgroupMinCPUQuota)
+		if err = cg.write("cpu.max", fmt.Sprintf("%d %d", quota, cgroupCPUPeriod)); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.PidsMax > 0 {
+		if err = cg.write("pids.max", strconv.FormatUint(cfg.PidsMax, 10)); err != nil {
+			return nil, err
+		}
+	}
+
+	if cg.dir, err = os.Open(path); err != nil {
+		return nil, fmt.Errorf("failed to open cgroup: %w", err)
+	}
+
+	ok = true
+	return cg, nil
+}
+
+func (cg *cgroup) write(name, value string) error {
+	if err := os.WriteFile(filepath.Join(cg.path, name), []byte(value), 0); err != nil {
+		return fmt.Errorf("failed to set %s: %w", name, err)
+	}
+	return nil
+}
+
+// configure makes the given command start directly in the cgroup.
+func (cg *cgroup) configure(cmd *exec.Cmd) {
+	attrs := syscall.SysProcAttr{}
+	if cmd.SysProcAttr != nil {
+		attrs = *cmd.SysProcAttr
+	}
+	attrs.UseCgroupFD = true
+	attrs.CgroupFD = int(cg.dir.Fd())
+	cmd.SysProcAttr = &attrs
+}
+
+// oomKills returns the number of processes in the cgroup killed by the OOM killer.
+func (cg *cgroup) oomKills() (uint64, error) {
+	f, err := os.Open(filepath.Join(cg.path, "memory.events"))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), " ")
+		if !ok || key != "oom_kill" {
+			continue
+		}
+		return strconv.ParseUint(value, 10, 64)
+	}
+	return 0, scanner.Err()
+}
+
+// destroy kills any remaining processes in the cgroup and removes it.
+func (cg *cgroup) destroy() {
+	if cg.dir != nil {
+		_ = cg.dir.Close()
+	}
+
+	// Not supported by older kernels, in which case the cgroup can only be removed once all of the
+	// processes have exited on their own.
+	_ = cg.write("cgroup.kill", "1")
+
+	// Killing is asynchronous so the cgroup may still be busy for a short while.
+	for range cgroupRemoveRetries {
+		if err := os.Remove(cg.path); err == nil || errors.Is(err, os.ErrNotExist) {
+			return
+		}
+		time.Sleep(cgroupRemoveInterval)
+	}
+}
@@ -0,0 +1,61 @@
+package process
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCgroupNoRoot(t *testing.T) {
+	_, err := newCgroup(&CgroupConfig{
+		Name:      "test",
+		MemoryMax: 64 * 1024 * 1024,
+	})
+	require.ErrorContains(t, err, "cgroup root not configured")
+}
+
+func TestCgroup(t *testing.T) {
+	root := os.Getenv("OASIS_TEST_RUNTIME_HOST_CGROUP_ROOT")
+	if root == "" {
+		t.Skip("skipping as OASIS_TEST_RUNTIME_HOST_CGROUP_ROOT is not set")
+	}
+
+	t.Run("Limits", func(t *testing.T) {
+		require := require.New(t)
+
+		p, err := NewNaked(Config{
+			Path: "/bin/true",
+			Cgroup: &CgroupConfig{
+				Root:      root,
+				Name:      "test",
+				MemoryMax: 64 * 1024 * 1024,
+				CPUMax:    0.5,
+				PidsMax:   16,
+			},
+		})
+		require.NoError(err, "NewNaked")
+
+		<-p.Wait()
+		require.NoError(p.Error(), "process should execute successfully")
+	})
+
+	t.Run("OutOfMemory", func(t *testing.T) {
+		require := require.New(t)
+
+		// Reading a long line makes tail buffer it in memory.
+		p, err := NewNaked(Config{
+			Path: "/bin/sh",
+			Args: []string{"-c", "head -c 256M /dev/zero | tail -n 1"},
+			Cgroup: &CgroupConfig{
+				Root:      root,
+				Name:      "test",
+				MemoryMax: 32 * 1024 * 1024,
+			},
+		})
+		require.NoError(err, "NewNaked")
+
+		<-p.Wait()
+		require.ErrorIs(p.Error(), ErrOutOfMemory)
+	})
+}
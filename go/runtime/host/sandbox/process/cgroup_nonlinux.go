@@ -0,0 +1,22 @@
+//go:build !linux
+
+package process
+
+import (
+	"errors"
+	"os/exec"
+)
+
+type cgroup struct{}
+
+func newCgroup(*CgroupConfig) (*cgroup, error) {
+	return nil, errors.New("cgroups are only supported on Linux")
+}
+
+func (cg *cgroup) configure(*exec.Cmd) {}
+
+func (cg *cgroup) oomKills() (uint64, error) {
+	return 0, nil
+}
+
+func (cg *cgroup) destroy() {}
@@ -15,7 +15,8 @@ import (
 type naked struct {
 	sync.Mutex
 
-	cmd *exec.Cmd
+	cmd    *exec.Cmd
+	cgroup *cgroup
 
 	err    error
 	waitCh chan struct{}
@@ -59,6 +60,19 @@ func (n *naked) wait() error {
 	return nil
 }
 
+// checkOutOfMemory wraps the given process termination error with ErrOutOfMemory in case any
+// process in the cgroup has been killed due to exceeding the memory limit.
+func (n *naked) checkOutOfMemory(err error) error {
+	kills, cerr := n.cgroup.oomKills()
+	if cerr != nil || kills == 0 {
+		return err
+	}
+	if err == nil {
+		return ErrOutOfMemory
+	}
+	return fmt.Errorf("%w: %w", ErrOutOfMemory, err)
+}
+
 // NewNaked creates a naked "sandbox" which performs no sandboxing and runs the given binary as a
 // regular child process.
 func NewNaked(cfg Config) (Process, error) {
@@ -100,16 +114,34 @@ func NewNaked(cfg Config) (Process, error) {
 		}
 	}
 
+	// Confine the process to a cgroup when resource limits are configured.
+	var cg *cgroup
+	if cfg.Cgroup != nil {
+		var err error
+		if cg, err = newCgroup(cfg.Cgroup); err != nil {
+			return nil, err
+		}
+		cg.configure(cmd)
+	}
+
 	if err := cmd.Start(); err != nil {
+		if cg != nil {
+			cg.destroy()
+		}
 		return nil, err
 	}
 
 	n := &naked{
 		cmd:    cmd,
+		cgroup: cg,
 		waitCh: make(chan struct{}),
 	}
 	go func() {
 		err := n.wait()
+		if n.cgroup != nil {
+			err = n.checkOutOfMemory(err)
+			n.cgroup.destroy()
+		}
 
 		n.Lock()
 		n.err = err
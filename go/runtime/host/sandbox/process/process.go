@@ -2,10 +2,15 @@
 package process
 
 import (
+	"errors"
 	"io"
 	"os"
 )
 
+// ErrOutOfMemory is the error returned when the process has been killed due to exceeding its
+// memory limit.
+var ErrOutOfMemory = errors.New("process killed due to exceeding its memory limit")
+
 // Config contains the sandbox configuration.
 //
 // This is similar to the os/exec.Cmd structure.
@@ -45,9 +50,30 @@ type Config struct {
 	// AllowNetwork specifies whether network access should be allowed.
 	AllowNetwork bool
 
+	// Cgroup is the optional cgroup v2 configuration used to limit the resources of the process.
+	Cgroup *CgroupConfig
+
 	extraFiles []*os.File
 }
 
+// CgroupConfig is the cgroup v2 configuration used to limit the resources of a process.
+type CgroupConfig struct {
+	// Root is the path to the cgroup v2 directory under which the process cgroup is created.
+	Root string
+
+	// Name is the prefix of the process cgroup name.
+	Name string
+
+	// MemoryMax is the maximum amount of memory in bytes. Zero means no limit.
+	MemoryMax uint64
+
+	// CPUMax is the maximum number of CPUs. Zero means no limit.
+	CPUMax float64
+
+	// PidsMax is the maximum number of processes. Zero means no limit.
+	PidsMax uint64
+}
+
 // Process is a sandboxed process.
 type Process interface {
 	// GetPID returns the process identifier of the sandbox running the given process.
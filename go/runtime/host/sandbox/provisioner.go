@@ -8,6 +8,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	cmSync "github.com/oasisprotocol/oasis-core/go/common/sync"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox/process"
@@ -16,6 +17,17 @@ import (
 // GetSandboxConfigFunc is the function used to generate the sandbox configuration.
 type GetSandboxConfigFunc func(cfg host.Config, conn Connector, runtimeDir string) (process.Config, error)
 
+// GetCgroupConfigFunc is the function used to generate the sandbox cgroup configuration.
+type GetCgroupConfigFunc func(cfg host.Config) (*process.CgroupConfig, error)
+
+// NewTraceRecorderFunc is the function used to create a trace recorder for the Runtime Host
+// Protocol connection with the given runtime.
+type NewTraceRecorderFunc func(cfg host.Config) (*protocol.Recorder, error)
+
+// GetResourceUsageFunc is the function used to retrieve the resource usage of the given runtime
+// process.
+type GetResourceUsageFunc func(p process.Process) (*host.ResourceUsage, error)
+
 // CleanupFunc is the runtime cleanup function.
 type CleanupFunc func(cfg host.Config)
 
@@ -32,6 +44,18 @@ type Config struct {
 	// Cleanup is a function that gets called when the runtime is cleaned up.
 	Cleanup CleanupFunc
 
+	// GetCgroupConfig is an optional function that returns the cgroup configuration used to limit
+	// the resources of the given runtime. In case it returns nil, no limits are applied.
+	GetCgroupConfig GetCgroupConfigFunc
+
+	// NewTraceRecorder is an optional function that creates a recorder for tracing the messages
+	// exchanged with the given runtime. In case it returns nil, messages are not traced.
+	NewTraceRecorder NewTraceRecorderFunc
+
+	// GetResourceUsage is an optional function that returns the resource usage of the given
+	// runtime process. In case it is not specified, resource usage is not reported.
+	GetResourceUsage GetResourceUsageFunc
+
 	// HostInfo provides information about the host environment.
 	HostInfo *protocol.HostInfo
 
@@ -48,6 +72,9 @@ type Config struct {
 
 	// InsecureNoSandbox disables the sandbox and runs the runtime binary directly.
 	InsecureNoSandbox bool
+
+	// Restart is the policy for restarting runtimes that terminate or fail to start.
+	Restart rtConfig.RestartConfig
 }
 
 type sandboxProvisioner struct {
@@ -95,6 +122,7 @@ func (p *sandboxProvisioner) NewRuntime(cfg host.Config) (host.Runtime, error) {
 		id:                          cfg.ID,
 		startOne:                    cmSync.NewOne(),
 		ctrlCh:                      make(chan any, ctrlChannelBufferSize),
+		restarts:                    newRestartTracker(p.cfg.Restart),
 		notifier:                    pubsub.NewBroker(false),
 		notifyUpdateCapabilityTEECh: make(chan struct{}, 1),
 		logger:                      p.cfg.Logger.With("runtime_id", cfg.ID),
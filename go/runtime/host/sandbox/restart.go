@@ -0,0 +1,86 @@
+package sandbox
+
+import (
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	cmnBackoff "github.com/oasisprotocol/oasis-core/go/common/backoff"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+)
+
+const (
+	defaultRestartInitialInterval = 500 * time.Millisecond
+	defaultRestartMaxInterval     = time.Hour
+)
+
+// restartTracker tracks runtime crashes and decides whether a runtime should be restarted based on
+// the configured restart policy.
+type restartTracker struct {
+	cfg rtConfig.RestartConfig
+
+	// consecutive is the number of crashes since the runtime last ran without interruption.
+	consecutive uint64
+	// halted is true iff the runtime should no longer be restarted.
+	halted bool
+	// crashLooping is true iff restarts were halted due to repeated crashes.
+	crashLooping bool
+}
+
+func newRestartTracker(cfg rtConfig.RestartConfig) *restartTracker {
+	return &restartTracker{cfg: cfg}
+}
+
+// newBackOff creates a new backoff for spacing out restarts.
+func (t *restartTracker) newBackOff() backoff.BackOff {
+	bo := cmnBackoff.NewExponentialBackOff()
+	bo.InitialInterval = defaultRestartInitialInterval
+	if t.cfg.InitialInterval > 0 {
+		bo.InitialInterval = t.cfg.InitialInterval
+	}
+	bo.MaxInterval = defaultRestartMaxInterval
+	if t.cfg.MaxInterval > 0 {
+		bo.MaxInterval = t.cfg.MaxInterval
+	}
+	bo.Reset()
+	return bo
+}
+
+// crashed records that the runtime terminated unexpectedly or failed to start with the given error
+// (nil in case the runtime exited cleanly) and returns true iff the runtime should be restarted.
+func (t *restartTracker) crashed(err error) bool {
+	t.consecutive++
+
+	switch t.cfg.Policy {
+	case rtConfig.RestartPolicyNever:
+		t.halted = true
+	case rtConfig.RestartPolicyOnFailure:
+		switch {
+		case err == nil:
+			t.halted = true
+		case t.cfg.MaxRetries > 0 && t.consecutive > t.cfg.MaxRetries:
+			t.halted = true
+			t.crashLooping = true
+		}
+	default:
+	}
+
+	if !t.halted && t.cfg.CrashLoopThreshold > 0 && t.consecutive >= t.cfg.CrashLoopThreshold {
+		t.halted = true
+		t.crashLooping = true
+	}
+
+	return !t.halted
+}
+
+// stable records that the runtime has been running without interruption for a while.
+func (t *restartTracker) stable() {
+	t.consecutive = 0
+}
+
+// reset clears the crash state after an operator explicitly restarted the runtime.
+func (t *restartTracker) reset() {
+	t.consecutive = 0
+	t.halted = false
+	t.crashLooping = false
+}
@@ -0,0 +1,55 @@
+package sandbox
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+)
+
+func TestRestartTracker(t *testing.T) {
+	require := require.New(t)
+	errCrash := errors.New("crash")
+
+	// Always restart until the crash loop threshold is reached.
+	rt := newRestartTracker(rtConfig.RestartConfig{
+		Policy:             rtConfig.RestartPolicyAlways,
+		CrashLoopThreshold: 3,
+	})
+	require.True(rt.crashed(nil))
+	require.True(rt.crashed(errCrash))
+	rt.stable()
+	require.True(rt.crashed(errCrash))
+	require.True(rt.crashed(errCrash))
+	require.False(rt.crashed(errCrash))
+	require.True(rt.crashLooping)
+	rt.reset()
+	require.False(rt.halted)
+	require.False(rt.crashLooping)
+	require.True(rt.crashed(errCrash))
+
+	// Only restart on failure, up to the maximum number of retries.
+	rt = newRestartTracker(rtConfig.RestartConfig{
+		Policy:     rtConfig.RestartPolicyOnFailure,
+		MaxRetries: 2,
+	})
+	require.True(rt.crashed(errCrash))
+	require.True(rt.crashed(errCrash))
+	require.False(rt.crashed(errCrash))
+	require.True(rt.crashLooping)
+
+	rt = newRestartTracker(rtConfig.RestartConfig{
+		Policy: rtConfig.RestartPolicyOnFailure,
+	})
+	require.False(rt.crashed(nil))
+	require.False(rt.crashLooping)
+
+	// Never restart.
+	rt = newRestartTracker(rtConfig.RestartConfig{
+		Policy: rtConfig.RestartPolicyNever,
+	})
+	require.False(rt.crashed(errCrash))
+	require.False(rt.crashLooping)
+}
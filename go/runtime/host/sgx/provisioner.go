@@ -21,6 +21,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/sgx/sigstruct"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox"
@@ -70,6 +71,17 @@ type Config struct {
 	// SandboxBinaryPath is the path to the sandbox support binary.
 	SandboxBinaryPath string
 
+	// GetCgroupConfig is an optional function that returns the cgroup configuration used to limit
+	// the resources of the given runtime.
+	GetCgroupConfig sandbox.GetCgroupConfigFunc
+
+	// NewTraceRecorder is an optional function that creates a recorder for tracing the messages
+	// exchanged with the given runtime.
+	NewTraceRecorder sandbox.NewTraceRecorderFunc
+
+	// Restart is the policy for restarting runtimes that terminate or fail to start.
+	Restart rtConfig.RestartConfig
+
 	// InsecureNoSandbox disables the sandbox and runs the loader directly.
 	InsecureNoSandbox bool
 	// InsecureMock runs non-SGX binaries but treats it as if it would be running in an enclave,
@@ -115,10 +127,13 @@ func NewProvisioner(cfg Config) (host.Provisioner, error) {
 	}
 	sp, err := sandbox.NewProvisioner(sandbox.Config{
 		GetSandboxConfig:  p.getSandboxConfig,
+		GetCgroupConfig:   cfg.GetCgroupConfig,
+		NewTraceRecorder:  cfg.NewTraceRecorder,
 		HostInfo:          cfg.HostInfo,
 		HostInitializer:   p.hostInitializer,
 		InsecureNoSandbox: cfg.InsecureNoSandbox,
 		Logger:            p.logger,
+		Restart:           cfg.Restart,
 	})
 	if err != nil {
 		return nil, err
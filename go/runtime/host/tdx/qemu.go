@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/mdlayher/vsock"
+	"github.com/prometheus/procfs"
 
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
@@ -21,6 +22,7 @@ import (
 	sgxQuote "github.com/oasisprotocol/oasis-core/go/common/sgx/quote"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox"
@@ -66,6 +68,13 @@ type QemuConfig struct {
 	// RuntimeAttestInterval is the interval for periodic runtime re-attestation. If not specified
 	// a default will be used.
 	RuntimeAttestInterval time.Duration
+
+	// Restart is the policy for restarting runtimes that terminate or fail to start.
+	Restart rtConfig.RestartConfig
+
+	// NewTraceRecorder is an optional function that creates a recorder for tracing the messages
+	// exchanged with the given runtime.
+	NewTraceRecorder sandbox.NewTraceRecorderFunc
 }
 
 // QemuExtraConfig is the per-runtime QEMU-specific extra configuration.
@@ -111,6 +120,9 @@ func NewQemuProvisioner(cfg QemuConfig) (host.Provisioner, error) {
 		HostInitializer:   p.hostInitializer,
 		InsecureNoSandbox: true, // No sandbox is needed for TDX.
 		Logger:            p.logger,
+		Restart:           cfg.Restart,
+		NewTraceRecorder:  cfg.NewTraceRecorder,
+		GetResourceUsage:  p.getResourceUsage,
 	})
 	if err != nil {
 		return nil, err
@@ -151,6 +163,23 @@ func (p *qemuProvisioner) Name() string {
 	return "tdx-qemu"
 }
 
+// getResourceUsage returns the resource usage of the QEMU process running the VM.
+func (p *qemuProvisioner) getResourceUsage(proc process.Process) (*host.ResourceUsage, error) {
+	pp, err := procfs.NewProc(proc.GetPID())
+	if err != nil {
+		return nil, err
+	}
+	stat, err := pp.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	return &host.ResourceUsage{
+		CPUTime: time.Duration(stat.CPUTime() * float64(time.Second)),
+		Memory:  uint64(stat.ResidentMemory()),
+	}, nil
+}
+
 func (p *qemuProvisioner) cleanup(cfg host.Config) {
 	cid := cfg.Extra.(*QemuExtraConfig).CID // Ensured above.
 	if !p.cidPool.Release(cid) {
@@ -223,6 +252,9 @@ func (p *qemuProvisioner) getSandboxConfig(cfg host.Config, _ sandbox.Connector,
 				if !ok {
 					return process.Config{}, fmt.Errorf("volume for '%s' not attached", tdxCfg.Stage2Image)
 				}
+				if err := volume.CheckQuota(); err != nil {
+					return process.Config{}, err
+				}
 
 				var err error
 				stage2Image, err = p.createPersistentOverlayImage(stage2Image, stage2Format, volume)
@@ -0,0 +1,128 @@
+package log
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+// Entry is a structured log entry.
+type Entry struct {
+	// Timestamp is the time at which the entry was logged.
+	Timestamp time.Time `json:"ts"`
+
+	// Level is the log level of the entry.
+	Level string `json:"level"`
+
+	// Component is the identifier of the component that emitted the entry.
+	Component component.ID `json:"component"`
+
+	// Module is the module within the component that emitted the entry.
+	Module string `json:"module,omitempty"`
+
+	// Message is the log message.
+	Message string `json:"msg"`
+
+	// Fields are any additional fields of the entry in their JSON encoding.
+	Fields map[string]json.RawMessage `json:"fields,omitempty"`
+}
+
+// entryWire is the serialized form of a log entry which preserves the sub-second precision of
+// the timestamp.
+type entryWire struct {
+	*plainEntry
+
+	// Timestamp is the time at which the entry was logged in nanoseconds since the Unix epoch.
+	Timestamp int64 `json:"ts"`
+}
+
+// plainEntry is a log entry without the custom serialization methods.
+type plainEntry Entry
+
+// MarshalCBOR encodes the log entry into CBOR.
+func (e *Entry) MarshalCBOR() ([]byte, error) {
+	wire := entryWire{
+		plainEntry: (*plainEntry)(e),
+	}
+	if !e.Timestamp.IsZero() {
+		wire.Timestamp = e.Timestamp.UnixNano()
+	}
+	return cbor.Marshal(wire), nil
+}
+
+// UnmarshalCBOR decodes the log entry from CBOR.
+func (e *Entry) UnmarshalCBOR(data []byte) error {
+	wire := entryWire{
+		plainEntry: (*plainEntry)(e),
+	}
+	if err := cbor.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	e.Timestamp = time.Time{}
+	if wire.Timestamp != 0 {
+		e.Timestamp = time.Unix(0, wire.Timestamp).UTC()
+	}
+	return nil
+}
+
+// parseEntry parses the given log line into a structured log entry.
+//
+// Lines which are not valid JSON are treated as messages without any fields.
+func parseEntry(line string) *Entry {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return &Entry{
+			Level:   "info",
+			Message: line,
+		}
+	}
+
+	var entry Entry
+	for k, dst := range map[string]any{
+		"ts":     &entry.Timestamp,
+		"level":  &entry.Level,
+		"module": &entry.Module,
+		"msg":    &entry.Message,
+	} {
+		if raw, ok := fields[k]; ok {
+			_ = json.Unmarshal(raw, dst)
+			delete(fields, k)
+		}
+	}
+	if len(fields) > 0 {
+		entry.Fields = fields
+	}
+
+	return &entry
+}
+
+// level returns the log level of the entry.
+//
+// Entries with an unknown log level are treated as informative.
+func (e *Entry) level() logging.Level {
+	var lvl logging.Level
+	if err := lvl.Set(e.Level); err != nil {
+		return logging.LevelInfo
+	}
+	return lvl
+}
+
+// matches returns true iff the given log line and its parsed entry match the filters of the
+// watch options, apart from the upper time bound.
+func (opts *WatchOptions) matches(line string, entry *Entry) bool {
+	if !entry.Timestamp.After(opts.Since) {
+		return false
+	}
+	if entry.level() < opts.Level {
+		return false
+	}
+	if opts.Contains != "" && !strings.Contains(line, opts.Contains) {
+		return false
+	}
+	return true
+}
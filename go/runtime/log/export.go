@@ -0,0 +1,185 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+)
+
+const (
+	// defaultExportBatchSize is the default maximum number of log entries sent in a single batch.
+	defaultExportBatchSize = 100
+	// defaultExportFlushInterval is the default maximum amount of time a log entry is buffered
+	// before being sent.
+	defaultExportFlushInterval = time.Second
+	// defaultExportBufferSize is the default maximum number of buffered log entries.
+	defaultExportBufferSize = 1000
+	// exportMaxRetryInterval is the maximum interval between attempts to send a batch.
+	exportMaxRetryInterval = 30 * time.Second
+)
+
+// exportRecord is a log entry of a runtime component that is being exported.
+type exportRecord struct {
+	*Entry
+
+	// RuntimeID is the identifier of the runtime that emitted the entry.
+	RuntimeID common.Namespace `json:"runtime_id"`
+}
+
+// sink is a destination to which log entries are exported.
+type sink interface {
+	// send sends the given batch of log entries to the sink.
+	send(ctx context.Context, batch []*exportRecord) error
+
+	// close releases any resources held by the sink.
+	close()
+}
+
+func newSink(cfg *rtConfig.LogExportConfig) (sink, error) {
+	switch cfg.Kind {
+	case rtConfig.LogExportKindSyslog:
+		return newSyslogSink(cfg)
+	case rtConfig.LogExportKindUnix:
+		return newUnixSink(cfg), nil
+	case rtConfig.LogExportKindHTTP:
+		return newHTTPSink(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown log export kind '%s'", cfg.Kind)
+	}
+}
+
+// exporter forwards runtime component logs to a sink.
+//
+// Entries are sent in batches. In case the sink is unavailable, sending is retried with
+// exponential backoff and reading of the logs is paused once the buffer is full, so that entries
+// are only lost when the logs are rotated in the meantime.
+type exporter struct {
+	cfg  rtConfig.LogExportConfig
+	sink sink
+
+	batchSize     int
+	flushInterval time.Duration
+	queue         chan *exportRecord
+
+	logger *logging.Logger
+}
+
+func newExporter(cfg rtConfig.LogExportConfig) (*exporter, error) {
+	sink, err := newSink(&cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := int(cfg.BatchSize)
+	if batchSize == 0 {
+		batchSize = defaultExportBatchSize
+	}
+	flushInterval := cfg.FlushInterval
+	if flushInterval == 0 {
+		flushInterval = defaultExportFlushInterval
+	}
+	bufferSize := int(cfg.BufferSize)
+	if bufferSize == 0 {
+		bufferSize = defaultExportBufferSize
+	}
+
+	return &exporter{
+		cfg:           cfg,
+		sink:          sink,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		queue:         make(chan *exportRecord, bufferSize),
+		logger:        logging.GetLogger("runtime/log/export").With("kind", cfg.Kind, "address", cfg.Address),
+	}, nil
+}
+
+// watch starts forwarding entries of the given log to the exporter until the log is closed or the
+// context is canceled.
+//
+// Only entries written after the watch has started are forwarded, so that existing entries are
+// not exported again in case the exporter is attached to a log multiple times.
+func (e *exporter) watch(ctx context.Context, runtimeID common.Namespace, log *Log) {
+	opts := WatchOptions{
+		Follow: true,
+		Offset: log.size(),
+	}
+	go e.forward(ctx, runtimeID, log, opts)
+}
+
+func (e *exporter) forward(ctx context.Context, runtimeID common.Namespace, log *Log, opts WatchOptions) {
+	ch := make(chan *Entry)
+	go func() {
+		defer close(ch)
+
+		if err := log.WatchEntries(ctx, ch, opts); err != nil && ctx.Err() == nil {
+			e.logger.Error("failed to watch log",
+				"err", err,
+				"runtime_id", runtimeID,
+				"component_id", log.componentID,
+			)
+		}
+	}()
+
+	for entry := range ch {
+		select {
+		case e.queue <- &exportRecord{Entry: entry, RuntimeID: runtimeID}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// run sends buffered entries to the sink until the context is canceled.
+func (e *exporter) run(ctx context.Context) {
+	defer e.sink.close()
+
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*exportRecord, 0, e.batchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case rec := <-e.queue:
+			batch = append(batch, rec)
+			if len(batch) < e.batchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+
+		if err := e.flush(ctx, batch); err != nil {
+			return
+		}
+		batch = batch[:0]
+	}
+}
+
+// flush sends the given batch to the sink, retrying until it succeeds or the context is
+// canceled.
+func (e *exporter) flush(ctx context.Context, batch []*exportRecord) error {
+	bo := backoff.NewExponentialBackOff()
+	bo.MaxInterval = exportMaxRetryInterval
+	bo.MaxElapsedTime = 0
+
+	return backoff.Retry(func() error {
+		err := e.sink.send(ctx, batch)
+		if err != nil && ctx.Err() == nil {
+			e.logger.Warn("failed to export log entries",
+				"err", err,
+				"entries", len(batch),
+			)
+		}
+		return err
+	}, backoff.WithContext(bo, ctx))
+}
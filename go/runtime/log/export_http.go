@@ -0,0 +1,235 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+)
+
+const (
+	// httpRequestTimeout is the timeout for pushing a batch to an HTTP endpoint.
+	httpRequestTimeout = 30 * time.Second
+	// httpMaxErrorBodySize is the maximum number of bytes of an error response body that are
+	// included in the returned error.
+	httpMaxErrorBodySize = 512
+	// otlpScopeName is the instrumentation scope name of exported OTLP log records.
+	otlpScopeName = "oasis-node"
+)
+
+// httpSink is a sink that pushes batches of log entries to an HTTP endpoint.
+type httpSink struct {
+	url     string
+	format  rtConfig.LogExportFormat
+	headers map[string]string
+
+	client *http.Client
+}
+
+func newHTTPSink(cfg *rtConfig.LogExportConfig) *httpSink {
+	return &httpSink{
+		url:     cfg.Address,
+		format:  cfg.Format,
+		headers: cfg.Headers,
+		client: &http.Client{
+			Timeout: httpRequestTimeout,
+		},
+	}
+}
+
+func (s *httpSink) send(ctx context.Context, batch []*exportRecord) error {
+	var (
+		payload any
+		err     error
+	)
+	switch s.format {
+	case rtConfig.LogExportFormatOTLP:
+		payload = encodeOTLP(batch)
+	case rtConfig.LogExportFormatLoki:
+		payload, err = encodeLoki(batch)
+	default:
+		err = fmt.Errorf("unknown log export format '%s'", s.format)
+	}
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	rsp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(rsp.Body, httpMaxErrorBodySize))
+		return fmt.Errorf("unexpected status code %d: %s", rsp.StatusCode, msg)
+	}
+	_, _ = io.Copy(io.Discard, rsp.Body)
+	return nil
+}
+
+func (s *httpSink) close() {
+	s.client.CloseIdleConnections()
+}
+
+// otlpValue is an OTLP any value.
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// otlpKeyValue is an OTLP attribute.
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+// otlpLogRecord is an OTLP log record.
+type otlpLogRecord struct {
+	TimeUnixNano   string         `json:"timeUnixNano"`
+	SeverityNumber int            `json:"severityNumber"`
+	SeverityText   string         `json:"severityText"`
+	Body           otlpValue      `json:"body"`
+	Attributes     []otlpKeyValue `json:"attributes"`
+}
+
+// otlpScope is an OTLP instrumentation scope.
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+// otlpScopeLogs are OTLP log records of an instrumentation scope.
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+// otlpResourceLogs are OTLP log records of a resource.
+type otlpResourceLogs struct {
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+// otlpLogs is an OTLP/HTTP logs export request.
+type otlpLogs struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+// otlpSeverityNumber returns the OTLP severity number of the given log level.
+func otlpSeverityNumber(level logging.Level) int {
+	switch level {
+	case logging.LevelDebug:
+		return 5
+	case logging.LevelInfo:
+		return 9
+	case logging.LevelWarn:
+		return 13
+	default:
+		return 17
+	}
+}
+
+func encodeOTLP(batch []*exportRecord) *otlpLogs {
+	records := make([]otlpLogRecord, 0, len(batch))
+	for _, rec := range batch {
+		level := rec.level()
+		attrs := []otlpKeyValue{
+			{Key: "runtime_id", Value: otlpValue{rec.RuntimeID.String()}},
+			{Key: "component", Value: otlpValue{componentLabel(rec.Component)}},
+		}
+		if rec.Module != "" {
+			attrs = append(attrs, otlpKeyValue{Key: "module", Value: otlpValue{rec.Module}})
+		}
+		for _, k := range slices.Sorted(maps.Keys(rec.Fields)) {
+			attrs = append(attrs, otlpKeyValue{Key: k, Value: otlpValue{string(rec.Fields[k])}})
+		}
+
+		records = append(records, otlpLogRecord{
+			TimeUnixNano:   strconv.FormatInt(rec.Timestamp.UnixNano(), 10),
+			SeverityNumber: otlpSeverityNumber(level),
+			SeverityText:   level.String(),
+			Body:           otlpValue{rec.Message},
+			Attributes:     attrs,
+		})
+	}
+
+	return &otlpLogs{
+		ResourceLogs: []otlpResourceLogs{{
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: otlpScopeName},
+				LogRecords: records,
+			}},
+		}},
+	}
+}
+
+// componentLabel returns the text form of the given component identifier.
+func componentLabel(id component.ID) string {
+	raw, _ := id.MarshalText()
+	return string(raw)
+}
+
+// lokiStream is a Loki log stream.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// lokiPush is a Loki push API request.
+type lokiPush struct {
+	Streams []*lokiStream `json:"streams"`
+}
+
+func encodeLoki(batch []*exportRecord) (*lokiPush, error) {
+	var push lokiPush
+	streams := make(map[string]*lokiStream)
+	for _, rec := range batch {
+		level := rec.level()
+		labels := map[string]string{
+			"runtime_id": rec.RuntimeID.String(),
+			"component":  componentLabel(rec.Component),
+			"level":      strings.ToLower(level.String()),
+		}
+		key := labels["runtime_id"] + "/" + labels["component"] + "/" + labels["level"]
+
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+			push.Streams = append(push.Streams, stream)
+		}
+
+		line, err := json.Marshal(rec.Entry)
+		if err != nil {
+			return nil, err
+		}
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(rec.Timestamp.UnixNano(), 10),
+			string(line),
+		})
+	}
+	return &push, nil
+}
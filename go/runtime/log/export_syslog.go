@@ -0,0 +1,75 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"net/url"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+)
+
+// syslogTag is the tag of messages sent to syslog.
+const syslogTag = "oasis-runtime"
+
+// syslogSink is a sink that forwards log entries to a syslog daemon.
+type syslogSink struct {
+	network string
+	addr    string
+
+	writer *syslog.Writer
+}
+
+func newSyslogSink(cfg *rtConfig.LogExportConfig) (*syslogSink, error) {
+	var s syslogSink
+	if cfg.Address != "" {
+		u, err := url.Parse(cfg.Address)
+		if err != nil {
+			return nil, fmt.Errorf("malformed syslog address: %w", err)
+		}
+		s.network = u.Scheme
+		s.addr = u.Host
+	}
+	return &s, nil
+}
+
+func (s *syslogSink) send(_ context.Context, batch []*exportRecord) error {
+	if s.writer == nil {
+		writer, err := syslog.Dial(s.network, s.addr, syslog.LOG_INFO|syslog.LOG_DAEMON, syslogTag)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog: %w", err)
+		}
+		s.writer = writer
+	}
+
+	for _, rec := range batch {
+		raw, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		msg := string(raw)
+
+		switch rec.level() {
+		case logging.LevelDebug:
+			err = s.writer.Debug(msg)
+		case logging.LevelInfo:
+			err = s.writer.Info(msg)
+		case logging.LevelWarn:
+			err = s.writer.Warning(msg)
+		default:
+			err = s.writer.Err(msg)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *syslogSink) close() {
+	if s.writer != nil {
+		_ = s.writer.Close()
+	}
+}
@@ -0,0 +1,186 @@
+package log
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+)
+
+// startTestExport starts exporting a new log using the given configuration and writes a few
+// entries into it, skipping an entry written before the export started.
+func startTestExport(t *testing.T, cfg rtConfig.LogExportConfig) {
+	require := require.New(t)
+
+	exporter, err := newExporter(cfg)
+	require.NoError(err, "newExporter")
+
+	log, err := NewLog(filepath.Join(t.TempDir(), "log"), 4096)
+	require.NoError(err, "NewLog")
+	log.componentID = component.ID{Kind: component.ROFL, Name: "app"}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancelFn()
+		log.Close()
+	})
+
+	// Entries written before the exporter is attached should not be exported.
+	logger := log.Logger()
+	logger.Info("existing entry", "ts", "2025-05-26T10:44:06.755286713Z")
+
+	var runtimeID common.Namespace
+	go exporter.run(ctx)
+	exporter.watch(ctx, runtimeID, log)
+
+	logger.Info("hello info world", "ts", "2025-05-26T10:45:06.755286713Z", "round", 42)
+	logger.Error("hello error world", "ts", "2025-05-26T10:47:06.755286713Z")
+}
+
+func TestExportHTTP(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		mu       sync.Mutex
+		attempts int
+	)
+	bodyCh := make(chan []byte, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		fail := attempts == 1
+		mu.Unlock()
+
+		// Fail the first request to test retries.
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		require.Equal("secret", r.Header.Get("Authorization"))
+		body, _ := io.ReadAll(r.Body)
+		bodyCh <- body
+	}))
+	defer server.Close()
+
+	startTestExport(t, rtConfig.LogExportConfig{
+		Kind:          rtConfig.LogExportKindHTTP,
+		Address:       server.URL,
+		Format:        rtConfig.LogExportFormatLoki,
+		Headers:       map[string]string{"Authorization": "secret"},
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	})
+
+	var body []byte
+	select {
+	case body = <-bodyCh:
+	case <-time.After(10 * time.Second):
+		require.FailNow("timed out waiting for export")
+	}
+
+	var push lokiPush
+	err := json.Unmarshal(body, &push)
+	require.NoError(err, "Unmarshal")
+	require.Len(push.Streams, 2)
+	require.Equal("rofl.app", push.Streams[0].Stream["component"])
+	require.Equal("info", push.Streams[0].Stream["level"])
+	require.Equal("error", push.Streams[1].Stream["level"])
+	require.Equal([][2]string{{
+		"1748256306755286713",
+		`{"ts":"2025-05-26T10:45:06.755286713Z","level":"info","component":"rofl.app","msg":"hello info world","fields":{"round":42}}`,
+	}}, push.Streams[0].Values)
+}
+
+func TestEncodeOTLP(t *testing.T) {
+	require := require.New(t)
+
+	entry := parseEntry(`{"level":"warn","ts":"2025-05-26T10:45:06.755286713Z","msg":"hello","module":"runtime","b":"x","a":1}`)
+	logs := encodeOTLP([]*exportRecord{{Entry: entry}})
+	require.Len(logs.ResourceLogs, 1)
+	require.Len(logs.ResourceLogs[0].ScopeLogs, 1)
+
+	records := logs.ResourceLogs[0].ScopeLogs[0].LogRecords
+	require.Len(records, 1)
+	require.Equal("1748256306755286713", records[0].TimeUnixNano)
+	require.Equal(13, records[0].SeverityNumber)
+	require.Equal("WARN", records[0].SeverityText)
+	require.Equal("hello", records[0].Body.StringValue)
+
+	var keys []string
+	for _, attr := range records[0].Attributes {
+		keys = append(keys, attr.Key)
+	}
+	require.Equal([]string{"runtime_id", "component", "module", "a", "b"}, keys)
+}
+
+func TestExportUnix(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "logs.sock")
+	listener, err := net.Listen("unix", path)
+	require.NoError(err, "Listen")
+	defer listener.Close()
+
+	startTestExport(t, rtConfig.LogExportConfig{
+		Kind:          rtConfig.LogExportKindUnix,
+		Address:       path,
+		FlushInterval: 10 * time.Millisecond,
+	})
+
+	conn, err := listener.Accept()
+	require.NoError(err, "Accept")
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+
+	var records []map[string]any
+	scanner := bufio.NewScanner(conn)
+	for len(records) < 2 && scanner.Scan() {
+		var rec map[string]any
+		err = json.Unmarshal(scanner.Bytes(), &rec)
+		require.NoError(err, "Unmarshal")
+		records = append(records, rec)
+	}
+	require.Len(records, 2)
+	require.Equal("hello info world", records[0]["msg"])
+	require.Equal("hello error world", records[1]["msg"])
+	require.Equal(strings.Repeat("0", 64), records[0]["runtime_id"])
+}
+
+func TestExportSyslog(t *testing.T) {
+	require := require.New(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(err, "ListenPacket")
+	defer conn.Close()
+
+	startTestExport(t, rtConfig.LogExportConfig{
+		Kind:          rtConfig.LogExportKindSyslog,
+		Address:       "udp://" + conn.LocalAddr().String(),
+		FlushInterval: 10 * time.Millisecond,
+	})
+
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	buf := make([]byte, 4096)
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(err, "ReadFrom")
+
+	// Info messages of the daemon facility have priority 30.
+	msg := string(buf[:n])
+	require.True(strings.HasPrefix(msg, "<30>"), msg)
+	require.Contains(msg, syslogTag)
+	require.Contains(msg, `"msg":"hello info world"`)
+}
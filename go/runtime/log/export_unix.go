@@ -0,0 +1,67 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+)
+
+// unixWriteTimeout is the timeout for writing a batch to a Unix socket.
+const unixWriteTimeout = 10 * time.Second
+
+// unixSink is a sink that writes newline-delimited JSON log entries to a Unix socket.
+type unixSink struct {
+	path string
+
+	conn net.Conn
+}
+
+func newUnixSink(cfg *rtConfig.LogExportConfig) *unixSink {
+	return &unixSink{
+		path: cfg.Address,
+	}
+}
+
+func (s *unixSink) send(ctx context.Context, batch []*exportRecord) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, rec := range batch {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+
+	if s.conn == nil {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "unix", s.path)
+		if err != nil {
+			return fmt.Errorf("failed to connect to unix socket: %w", err)
+		}
+		s.conn = conn
+	}
+
+	deadline := time.Now().Add(unixWriteTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = s.conn.SetWriteDeadline(deadline)
+
+	if _, err := s.conn.Write(buf.Bytes()); err != nil {
+		// Reconnect on the next attempt.
+		s.close()
+		return err
+	}
+	return nil
+}
+
+func (s *unixSink) close() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
@@ -2,8 +2,8 @@ package log
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sync"
 	"time"
@@ -11,6 +11,7 @@ import (
 	"github.com/nxadm/tail"
 
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 )
 
 // Log is a log file handle.
@@ -22,6 +23,8 @@ type Log struct {
 	file    *os.File
 	closeCh chan struct{}
 
+	componentID component.ID
+
 	maxSize     int
 	currentSize int
 }
@@ -81,6 +84,13 @@ func (l *Log) rotate() error {
 	return nil
 }
 
+// size returns the size of the current log file.
+func (l *Log) size() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int64(l.currentSize)
+}
+
 // Close closes the log file for writing.
 func (l *Log) Close() error {
 	l.mu.Lock()
@@ -93,16 +103,50 @@ func (l *Log) Close() error {
 type WatchOptions struct {
 	// Follow specifies whether to follow the log file for changes.
 	Follow bool
+	// Offset specifies the position in the current log file (in bytes) where to start reading.
+	Offset int64
 	// Since specifies a time offset where to start from (excluding the offset itself).
 	Since time.Time
+	// Until specifies a time offset where to stop (excluding the offset itself). A zero value
+	// means that there is no upper bound.
+	Until time.Time
+	// Level specifies the minimum log level of returned lines.
+	Level logging.Level
+	// Contains specifies a substring that returned lines must contain.
+	Contains string
 }
 
 // Watch starts watching the log file for changes, pushing lines to the passed channel.
 func (l *Log) Watch(ctx context.Context, ch chan<- string, opts WatchOptions) error {
+	return l.watch(ctx, opts, func(line string, _ *Entry) error {
+		select {
+		case ch <- line:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// WatchEntries starts watching the log file for changes, pushing structured log entries to the
+// passed channel.
+func (l *Log) WatchEntries(ctx context.Context, ch chan<- *Entry, opts WatchOptions) error {
+	return l.watch(ctx, opts, func(_ string, entry *Entry) error {
+		select {
+		case ch <- entry:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+func (l *Log) watch(ctx context.Context, opts WatchOptions, emit func(string, *Entry) error) error {
 	watcher, err := tail.TailFile(l.file.Name(), tail.Config{
 		ReOpen:        opts.Follow,
 		Follow:        opts.Follow,
 		CompleteLines: true,
+		Location:      &tail.SeekInfo{Offset: opts.Offset, Whence: io.SeekStart},
 	})
 	if err != nil {
 		return err
@@ -120,43 +164,57 @@ func (l *Log) Watch(ctx context.Context, ch chan<- string, opts WatchOptions) er
 				return nil
 			}
 
-			// Parse line as JSON to get the timestamp and filter based on offset.
-			var lineWithTs struct {
-				Timestamp time.Time `json:"ts"`
+			// Parse line to get the timestamp and other fields used for filtering.
+			entry := parseEntry(line.Text)
+			entry.Component = l.componentID
+			if !opts.Until.IsZero() && !entry.Timestamp.Before(opts.Until) {
+				// Lines are ordered by time so there is nothing more to return.
+				return nil
 			}
-			_ = json.Unmarshal([]byte(line.Text), &lineWithTs)
-			if !lineWithTs.Timestamp.After(opts.Since) {
+			if !opts.matches(line.Text, entry) {
 				continue
 			}
 
-			ch <- line.Text
+			if err = emit(line.Text, entry); err != nil {
+				return err
+			}
 		}
 	}
 }
 
 // Read reads the lines from the log file using the given options, returning the read lines.
 func (l *Log) Read(ctx context.Context, opts WatchOptions) ([]string, error) {
+	return collect(ctx, l.Watch, opts)
+}
+
+// ReadEntries reads the structured log entries from the log file using the given options,
+// returning the read entries.
+func (l *Log) ReadEntries(ctx context.Context, opts WatchOptions) ([]*Entry, error) {
+	return collect(ctx, l.WatchEntries, opts)
+}
+
+func collect[T any](ctx context.Context, watch func(context.Context, chan<- T, WatchOptions) error, opts WatchOptions) ([]T, error) {
 	var (
-		lines []string
+		items []T
 		wg    sync.WaitGroup
 	)
 
-	ch := make(chan string)
+	ch := make(chan T)
 
 	wg.Go(func() {
-		for line := range ch {
-			lines = append(lines, line)
+		for item := range ch {
+			items = append(items, item)
 		}
 	})
 
-	err := l.Watch(ctx, ch, opts)
+	err := watch(ctx, ch, opts)
 	close(ch)
 	wg.Wait()
 	if err != nil {
 		return nil, err
 	}
 
-	return lines, nil
+	return items, nil
 }
 
 // runtimeExcludeFields are the fields to exclude in per-runtime logs.
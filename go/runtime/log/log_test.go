@@ -2,11 +2,16 @@ package log
 
 import (
 	"context"
+	"encoding/json"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 )
 
 func TestLog(t *testing.T) {
@@ -46,3 +51,87 @@ func TestLog(t *testing.T) {
 	}
 	require.Equal(expectedOutput, lines)
 }
+
+func TestLogEntries(t *testing.T) {
+	require := require.New(t)
+
+	tmpDir := t.TempDir()
+	log, err := NewLog(filepath.Join(tmpDir, "log"), 4096)
+	require.NoError(err, "NewLog")
+	defer log.Close()
+	log.componentID = component.ID{Kind: component.ROFL, Name: "app"}
+
+	logger := log.Logger()
+	logger.Debug("starting", "ts", "2025-05-26T10:44:06.755286713Z", "module", "runtime/app")
+	logger.Info("hello info world", "ts", "2025-05-26T10:45:06.755286713Z", "module", "runtime/app", "round", 42)
+	logger.Warn("hello warn world", "ts", "2025-05-26T10:46:06.755286713Z", "module", "runtime/app")
+	logger.Error("hello error world", "ts", "2025-05-26T10:47:06.755286713Z", "module", "runtime/app")
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Second)
+	defer cancelFn()
+	entries, err := log.ReadEntries(ctx, WatchOptions{})
+	require.NoError(err, "log.ReadEntries")
+	require.Len(entries, 4)
+	require.Equal(&Entry{
+		Timestamp: time.Date(2025, 5, 26, 10, 45, 6, 755286713, time.UTC),
+		Level:     "info",
+		Component: log.componentID,
+		Module:    "runtime/app",
+		Message:   "hello info world",
+		Fields:    map[string]json.RawMessage{"round": json.RawMessage("42")},
+	}, entries[1])
+
+	// Test filtering by level.
+	entries, err = log.ReadEntries(ctx, WatchOptions{Level: logging.LevelWarn})
+	require.NoError(err, "log.ReadEntries")
+	require.Len(entries, 2)
+	require.Equal("hello warn world", entries[0].Message)
+	require.Equal("hello error world", entries[1].Message)
+
+	// Test filtering by time range.
+	entries, err = log.ReadEntries(ctx, WatchOptions{
+		Since: time.Date(2025, 5, 26, 10, 44, 30, 0, time.UTC),
+		Until: time.Date(2025, 5, 26, 10, 46, 30, 0, time.UTC),
+	})
+	require.NoError(err, "log.ReadEntries")
+	require.Len(entries, 2)
+	require.Equal("hello info world", entries[0].Message)
+	require.Equal("hello warn world", entries[1].Message)
+
+	// Test filtering by substring.
+	lines, err := log.Read(ctx, WatchOptions{Contains: "warn world"})
+	require.NoError(err, "log.Read")
+	require.Equal([]string{
+		`{"level":"warn","module":"runtime/app","msg":"hello warn world","ts":"2025-05-26T10:46:06.755286713Z"}`,
+	}, lines)
+}
+
+func TestParseEntry(t *testing.T) {
+	require := require.New(t)
+
+	entry := parseEntry("not json")
+	require.Equal(&Entry{Level: "info", Message: "not json"}, entry)
+	require.Equal(logging.LevelInfo, entry.level())
+
+	entry = parseEntry(`{"level":"error","msg":"failed","err":"boom"}`)
+	require.Equal("error", entry.Level)
+	require.Equal(logging.LevelError, entry.level())
+	require.Equal(map[string]json.RawMessage{"err": json.RawMessage(`"boom"`)}, entry.Fields)
+}
+
+func TestEntrySerialization(t *testing.T) {
+	require := require.New(t)
+
+	entry := parseEntry(`{"level":"error","ts":"2025-05-26T10:47:06.755286713Z","msg":"failed","err":{"code":[1,2]}}`)
+	entry.Component = component.ID{Kind: component.ROFL, Name: "app"}
+
+	var decoded Entry
+	err := cbor.UnmarshalRPC(cbor.Marshal(entry), &decoded)
+	require.NoError(err, "UnmarshalRPC")
+	require.Equal(entry, &decoded)
+
+	var empty Entry
+	err = cbor.Unmarshal(cbor.Marshal(&Entry{Message: "no timestamp"}), &empty)
+	require.NoError(err, "Unmarshal")
+	require.True(empty.Timestamp.IsZero())
+}
@@ -1,6 +1,7 @@
 package log
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,20 +23,49 @@ type Manager struct {
 	logsDir string
 	logs    map[common.Namespace]map[component.ID]*Log
 
+	exporters []*exporter
+
+	ctx      context.Context
+	cancelFn context.CancelFunc
+
 	logger *logging.Logger
 }
 
 // NewManager creates a new log manager.
-func NewManager(dataDir string) *Manager {
-	logger := logging.GetLogger("runtime/log/manager")
+func NewManager(dataDir string) (*Manager, error) {
+	var exporters []*exporter
+	for i, cfg := range config.GlobalConfig.Runtime.Log.Exports {
+		exporter, err := newExporter(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create log exporter %d: %w", i, err)
+		}
+		exporters = append(exporters, exporter)
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
 
 	return &Manager{
-		logsDir: GetLogsDir(dataDir),
-		logs:    make(map[common.Namespace]map[component.ID]*Log),
-		logger:  logger,
+		logsDir:   GetLogsDir(dataDir),
+		logs:      make(map[common.Namespace]map[component.ID]*Log),
+		exporters: exporters,
+		ctx:       ctx,
+		cancelFn:  cancelFn,
+		logger:    logging.GetLogger("runtime/log/manager"),
+	}, nil
+}
+
+// Start starts exporting logs to the configured sinks.
+func (m *Manager) Start() {
+	for _, exporter := range m.exporters {
+		go exporter.run(m.ctx)
 	}
 }
 
+// Stop stops exporting logs.
+func (m *Manager) Stop() {
+	m.cancelFn()
+}
+
 // Get returns a log handle for the log of the given component.
 func (m *Manager) Get(runtimeID common.Namespace, componentID component.ID) (*Log, error) {
 	m.mu.Lock()
@@ -55,6 +85,14 @@ func (m *Manager) Get(runtimeID common.Namespace, componentID component.ID) (*Lo
 	if err != nil {
 		return nil, fmt.Errorf("failed to create log handle: %w", err)
 	}
+	log.componentID = componentID
+
+	for _, exporter := range m.exporters {
+		if !exporter.cfg.Matches(runtimeID, componentID) {
+			continue
+		}
+		exporter.watch(m.ctx, runtimeID, log)
+	}
 
 	logs, ok := m.logs[runtimeID]
 	if !ok {
@@ -65,6 +103,15 @@ func (m *Manager) Get(runtimeID common.Namespace, componentID component.ID) (*Lo
 	return log, nil
 }
 
+// Lookup returns an existing log handle for the log of the given component.
+func (m *Manager) Lookup(runtimeID common.Namespace, componentID component.ID) (*Log, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	log, ok := m.logs[runtimeID][componentID]
+	return log, ok
+}
+
 // Remove closes a log handle and removes any logs of the given component.
 func (m *Manager) Remove(runtimeID common.Namespace, componentID component.ID) {
 	m.mu.Lock()
@@ -14,6 +14,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/composite"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
 	runtimeKeymanager "github.com/oasisprotocol/oasis-core/go/runtime/keymanager/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
@@ -43,6 +44,9 @@ type RuntimeHostHandlerEnvironment interface {
 
 	// GetROFLNotifier returns the ROFL notifier.
 	GetROFLNotifier() (*ROFLNotifier, error)
+
+	// GetHostedRuntime returns the hosted runtime.
+	GetHostedRuntime() *composite.Host
 }
 
 // RuntimeHostHandler is a runtime host handler suitable for compute runtimes. It provides the
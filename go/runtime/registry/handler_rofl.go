@@ -167,7 +167,7 @@ func (rh *roflHostHandler) handleHostRPCCall(
 			},
 		}, nil
 	case rofl.LocalRPCEndpointBundleManager, rofl.LocalRPCEndpointVolumeManager, rofl.LocalRPCEndpointLogManager,
-		rofl.LocalRPCEndpointAttestation:
+		rofl.LocalRPCEndpointAttestation, rofl.LocalRPCEndpointMetrics:
 		// Route management requests to handler.
 		if rq.HostRPCCallRequest.Kind != enclaverpc.KindLocalQuery {
 			return nil, fmt.Errorf("endpoint not supported")
@@ -191,6 +191,8 @@ func (rh *roflHostHandler) handleHostRPCCall(
 			rsp, err = rh.handleLogManagement(ctx, &rpcRq)
 		case rofl.LocalRPCEndpointAttestation:
 			rsp, err = rh.handleAttestation(&rpcRq)
+		case rofl.LocalRPCEndpointMetrics:
+			rsp, err = rh.handleMetrics(&rpcRq)
 		default:
 			return nil, fmt.Errorf("endpoint not supported")
 		}
@@ -17,6 +17,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 	runtimeConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
 	enclaverpc "github.com/oasisprotocol/oasis-core/go/runtime/enclaverpc/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	"github.com/oasisprotocol/oasis-core/go/runtime/log"
 	rofl "github.com/oasisprotocol/oasis-core/go/runtime/rofl/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/volume"
@@ -85,6 +86,27 @@ func (rh *roflHostHandler) handleVolumeManagement(rq *enclaverpc.Request) (any,
 			return nil, err
 		}
 		return rh.handleVolumeList(&args)
+	case rofl.MethodVolumeSnapshot:
+		// Create a volume snapshot.
+		var args rofl.VolumeSnapshotRequest
+		if err := cbor.Unmarshal(rq.Args, &args); err != nil {
+			return nil, err
+		}
+		return rh.handleVolumeSnapshot(&args)
+	case rofl.MethodVolumeRestore:
+		// Restore a volume from a snapshot.
+		var args rofl.VolumeRestoreRequest
+		if err := cbor.Unmarshal(rq.Args, &args); err != nil {
+			return nil, err
+		}
+		return rh.handleVolumeRestore(&args)
+	case rofl.MethodVolumeSnapshotRemove:
+		// Remove a volume snapshot.
+		var args rofl.VolumeSnapshotRemoveRequest
+		if err := cbor.Unmarshal(rq.Args, &args); err != nil {
+			return nil, err
+		}
+		return rh.handleVolumeSnapshotRemove(&args)
 	default:
 		return nil, fmt.Errorf("method not supported")
 	}
@@ -105,6 +127,21 @@ func (rh *roflHostHandler) handleLogManagement(ctx context.Context, rq *enclaver
 	}
 }
 
+// handleMetrics handles metrics local RPCs.
+func (rh *roflHostHandler) handleMetrics(rq *enclaverpc.Request) (any, error) {
+	switch rq.Method {
+	case rofl.MethodMetricsPush:
+		// Push metrics.
+		var args rofl.MetricsPushRequest
+		if err := cbor.Unmarshal(rq.Args, &args); err != nil {
+			return nil, err
+		}
+		return rh.handleMetricsPush(&args)
+	default:
+		return nil, fmt.Errorf("method not supported")
+	}
+}
+
 func (rh *roflHostHandler) handleBundleWrite(rq *rofl.BundleWriteRequest) (*rofl.BundleWriteResponse, error) {
 	if err := rh.ensureComponentPermissions(runtimeConfig.PermissionBundleAdd); err != nil {
 		return nil, err
@@ -191,15 +228,20 @@ func (rh *roflHostHandler) handleBundleList(rq *rofl.BundleListRequest) (*rofl.B
 
 	// Populate bundle information.
 	var bundles []*rofl.BundleInfo
+	hostedRuntime := rh.parent.env.GetHostedRuntime()
 	for _, manifest := range rh.parent.env.GetRuntimeRegistry().GetBundleRegistry().ManifestsWithLabels(labels) {
 		var bi rofl.BundleInfo
 		bi.ManifestHash = manifest.Hash()
 		bi.Labels = manifest.Labels
 
 		for _, comp := range manifest.Components {
-			bi.Components = append(bi.Components, &rofl.ComponentInfo{
+			ci := rofl.ComponentInfo{
 				Name: comp.Name,
-			})
+			}
+			if status := getComponentStatus(hostedRuntime, comp.ID(), comp.Version); status != nil {
+				ci.Status = newROFLComponentStatus(status, time.Now())
+			}
+			bi.Components = append(bi.Components, &ci)
 		}
 
 		bundles = append(bundles, &bi)
@@ -219,6 +261,8 @@ func (rh *roflHostHandler) handleVolumeAdd(rq *rofl.VolumeAddRequest) (*rofl.Vol
 	labels := make(map[string]string)
 	maps.Copy(labels, rq.Labels)
 	maps.Copy(labels, rh.getBundleManagementLabels())
+	// Account the volume towards the runtime's quota.
+	labels[volume.LabelRuntimeID] = rh.parent.runtime.ID().String()
 
 	volume, err := rh.getVolumeManager().Create(labels)
 	if err != nil {
@@ -263,6 +307,24 @@ func (rh *roflHostHandler) handleVolumeList(rq *rofl.VolumeListRequest) (*rofl.V
 		vi.ID = volume.ID
 		vi.Labels = volume.Labels
 
+		size, err := rh.getVolumeManager().Usage(volume.ID)
+		if err != nil {
+			return nil, err
+		}
+		vi.Size = size
+
+		snapshots, err := rh.getVolumeManager().Snapshots(volume.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, snapshot := range snapshots {
+			vi.Snapshots = append(vi.Snapshots, &rofl.VolumeSnapshotInfo{
+				Name:      snapshot.Name,
+				CreatedAt: uint64(snapshot.CreatedAt.Unix()),
+				Size:      snapshot.Size,
+			})
+		}
+
 		volumes = append(volumes, &vi)
 	}
 
@@ -271,6 +333,57 @@ func (rh *roflHostHandler) handleVolumeList(rq *rofl.VolumeListRequest) (*rofl.V
 	}, nil
 }
 
+func (rh *roflHostHandler) handleVolumeSnapshot(rq *rofl.VolumeSnapshotRequest) (*rofl.VolumeSnapshotResponse, error) {
+	if err := rh.ensureComponentPermissions(runtimeConfig.PermissionVolumeSnapshot); err != nil {
+		return nil, err
+	}
+	if err := rh.ensureVolumeAccess(rq.ID); err != nil {
+		return nil, err
+	}
+
+	if err := rh.getVolumeManager().CreateSnapshot(rq.ID, rq.Name); err != nil {
+		return nil, err
+	}
+	return &rofl.VolumeSnapshotResponse{}, nil
+}
+
+func (rh *roflHostHandler) handleVolumeRestore(rq *rofl.VolumeRestoreRequest) (*rofl.VolumeRestoreResponse, error) {
+	if err := rh.ensureComponentPermissions(runtimeConfig.PermissionVolumeSnapshot); err != nil {
+		return nil, err
+	}
+	if err := rh.ensureVolumeAccess(rq.ID); err != nil {
+		return nil, err
+	}
+
+	if err := rh.getVolumeManager().RestoreSnapshot(rq.ID, rq.Name); err != nil {
+		return nil, err
+	}
+	return &rofl.VolumeRestoreResponse{}, nil
+}
+
+func (rh *roflHostHandler) handleVolumeSnapshotRemove(rq *rofl.VolumeSnapshotRemoveRequest) (*rofl.VolumeSnapshotRemoveResponse, error) {
+	if err := rh.ensureComponentPermissions(runtimeConfig.PermissionVolumeSnapshot); err != nil {
+		return nil, err
+	}
+	if err := rh.ensureVolumeAccess(rq.ID); err != nil {
+		return nil, err
+	}
+
+	if err := rh.getVolumeManager().RemoveSnapshot(rq.ID, rq.Name); err != nil {
+		return nil, err
+	}
+	return &rofl.VolumeSnapshotRemoveResponse{}, nil
+}
+
+// ensureVolumeAccess ensures that the volume exists and is accessible to the component.
+func (rh *roflHostHandler) ensureVolumeAccess(id string) error {
+	volume, ok := rh.getVolumeManager().Get(id)
+	if !ok || !volume.HasLabels(rh.getBundleManagementLabels()) {
+		return fmt.Errorf("volume '%s' not found", id)
+	}
+	return nil
+}
+
 func (rh *roflHostHandler) handleLogGet(ctx context.Context, rq *rofl.LogGetRequest) (*rofl.LogGetResponse, error) {
 	if err := rh.ensureComponentPermissions(runtimeConfig.PermissionLogView); err != nil {
 		return nil, err
@@ -322,6 +435,36 @@ func (rh *roflHostHandler) handleLogGet(ctx context.Context, rq *rofl.LogGetRequ
 	}, nil
 }
 
+func (rh *roflHostHandler) handleMetricsPush(rq *rofl.MetricsPushRequest) (*rofl.MetricsPushResponse, error) {
+	if err := rh.ensureComponentPermissions(runtimeConfig.PermissionMetricsPush); err != nil {
+		return nil, err
+	}
+
+	if err := roflMetrics.push(rh.parent.runtime.ID(), rh.id, rq.Metrics); err != nil {
+		return nil, err
+	}
+
+	return &rofl.MetricsPushResponse{}, nil
+}
+
+// newROFLComponentStatus converts the given component status into its ROFL API representation.
+func newROFLComponentStatus(status *host.Status, now time.Time) *rofl.ComponentStatus {
+	cs := rofl.ComponentStatus{
+		State:   string(status.State),
+		Crashes: status.Crashes,
+		Uptime:  uint64(status.Uptime(now).Seconds()),
+		RAK:     status.RAK,
+	}
+	if res := status.Resources; res != nil {
+		cs.CPUTime = uint64(res.CPUTime.Milliseconds())
+		cs.Memory = res.Memory
+	}
+	if !status.AttestedAt.IsZero() {
+		cs.LastAttestation = uint64(status.AttestedAt.Unix())
+	}
+	return &cs
+}
+
 // ensureComponentPermissions ensures that the component has all of the specified permissions.
 func (rh *roflHostHandler) ensureComponentPermissions(perms ...runtimeConfig.ComponentPermission) error {
 	compCfg, ok := config.GlobalConfig.Runtime.GetComponent(rh.parent.runtime.ID(), rh.id)
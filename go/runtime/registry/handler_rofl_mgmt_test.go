@@ -0,0 +1,50 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	rofl "github.com/oasisprotocol/oasis-core/go/runtime/rofl/api"
+)
+
+func TestROFLComponentStatus(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1748256306, 0)
+	var rak signature.PublicKey
+	status := newROFLComponentStatus(&host.Status{
+		State:     host.StateRunning,
+		Crashes:   2,
+		StartedAt: now.Add(-90 * time.Second),
+		Resources: &host.ResourceUsage{
+			CPUTime: 1500 * time.Millisecond,
+			Memory:  1 << 30,
+		},
+		AttestedAt: now.Add(-time.Minute),
+		RAK:        &rak,
+	}, now)
+	require.Equal(&rofl.ComponentStatus{
+		State:           "running",
+		Crashes:         2,
+		Uptime:          90,
+		CPUTime:         1500,
+		Memory:          1 << 30,
+		LastAttestation: 1748256246,
+		RAK:             &rak,
+	}, status)
+
+	// Stopped components have no uptime or resource usage.
+	status = newROFLComponentStatus(&host.Status{
+		State:     host.StateCrashLooping,
+		Crashes:   5,
+		StartedAt: now.Add(-time.Hour),
+	}, now)
+	require.Equal(&rofl.ComponentStatus{
+		State:   "crash_looping",
+		Crashes: 5,
+	}, status)
+}
@@ -108,6 +108,7 @@ func (n *RuntimeHostNode) RemoveHostedRuntimeComponent(id component.ID) error {
 
 	if id.Kind == component.ROFL {
 		delete(n.rofls, id)
+		roflMetrics.remove(n.runtime.ID(), id)
 	}
 
 	return nil
@@ -118,6 +119,15 @@ func (n *RuntimeHostNode) GetHostedRuntime() *composite.Host {
 	return n.host
 }
 
+// GetHostedRuntimeComponentStatus returns the status of the given version of a hosted runtime
+// component.
+//
+// It returns nil in case the given version of the component is not hosted and active or if its
+// status is not available.
+func (n *RuntimeHostNode) GetHostedRuntimeComponentStatus(id component.ID, version version.Version) *host.Status {
+	return getComponentStatus(n.host, id, version)
+}
+
 // GetHostedRuntimeActiveVersion returns the version of the active runtime.
 func (n *RuntimeHostNode) GetHostedRuntimeActiveVersion() (*version.Version, error) {
 	return n.host.GetActiveVersion()
@@ -233,3 +243,21 @@ func (n *RuntimeHostNode) isComponentWanted(comp *bundle.ExplodedComponent) bool
 	// By default honor the status of the component itself.
 	return !comp.Disabled
 }
+
+// getComponentStatus returns the status of the given version of a component hosted by the given
+// composite runtime, if available.
+func getComponentStatus(h *composite.Host, id component.ID, version version.Version) *host.Status {
+	comp, ok := h.Component(id)
+	if !ok {
+		return nil
+	}
+	active, err := comp.GetActiveVersion()
+	if err != nil || *active != version {
+		return nil
+	}
+	status, err := comp.GetStatus()
+	if err != nil {
+		return nil
+	}
+	return status
+}
@@ -0,0 +1,235 @@
+package registry
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/metrics"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+const (
+	// roflMetricsNamespace is the prefix of the names of metrics pushed by ROFL components.
+	roflMetricsNamespace = "oasis_rofl"
+	// roflMetricsLabelRuntime is the label for the runtime identifier of metrics pushed by ROFL
+	// components.
+	roflMetricsLabelRuntime = "runtime"
+	// roflMetricsMaxSeries is the maximum number of series a ROFL component can push.
+	roflMetricsMaxSeries = 1000
+	// roflMetricsMaxLabels is the maximum number of labels of a series pushed by a ROFL component.
+	roflMetricsMaxLabels = 8
+	// roflMetricsMaxLabelValueLength is the maximum length of a label value of a series pushed by
+	// a ROFL component.
+	roflMetricsMaxLabelValueLength = 128
+)
+
+var (
+	roflMetrics = newROFLMetricsCollector()
+
+	roflMetricsOnce sync.Once
+
+	invalidMetricNameCharactersRegexp = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// initROFLMetrics registers the ROFL metrics collector if metrics are enabled.
+func initROFLMetrics() {
+	if !metrics.Enabled() {
+		return
+	}
+
+	roflMetricsOnce.Do(func() {
+		prometheus.MustRegister(roflMetrics)
+	})
+}
+
+type roflMetricsKey struct {
+	runtimeID   common.Namespace
+	componentID component.ID
+}
+
+// roflMetricFamily describes a metric family pushed by a ROFL component.
+type roflMetricFamily struct {
+	help string
+	kind dto.MetricType
+}
+
+// roflMetricsSet is a collector of a fixed set of metrics pushed by a ROFL component.
+type roflMetricsSet []prometheus.Metric
+
+// Describe implements prometheus.Collector.
+func (s roflMetricsSet) Describe(chan<- *prometheus.Desc) {
+}
+
+// Collect implements prometheus.Collector.
+func (s roflMetricsSet) Collect(ch chan<- prometheus.Metric) {
+	for _, m := range s {
+		ch <- m
+	}
+}
+
+// roflMetricsCollector is a collector that re-exports metrics pushed by ROFL components.
+type roflMetricsCollector struct {
+	mu         sync.RWMutex
+	components map[roflMetricsKey]roflMetricsSet
+}
+
+func newROFLMetricsCollector() *roflMetricsCollector {
+	return &roflMetricsCollector{
+		components: make(map[roflMetricsKey]roflMetricsSet),
+	}
+}
+
+// Describe implements prometheus.Collector.
+//
+// The collector is unchecked as the set of metrics depends on what the components push.
+func (c *roflMetricsCollector) Describe(chan<- *prometheus.Desc) {
+}
+
+// Collect implements prometheus.Collector.
+func (c *roflMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, metrics := range c.components {
+		metrics.Collect(ch)
+	}
+}
+
+// push replaces the metrics of the given component with metrics parsed from the given
+// Prometheus text exposition.
+func (c *roflMetricsCollector) push(runtimeID common.Namespace, componentID component.ID, text string) error {
+	var parser expfmt.TextParser
+	parsed, err := parser.TextToMetricFamilies(strings.NewReader(text))
+	if err != nil {
+		return fmt.Errorf("malformed metrics: %w", err)
+	}
+
+	prefix := roflMetricsNamespace + "_" + invalidMetricNameCharactersRegexp.ReplaceAllString(componentID.Name, "_") + "_"
+	var metrics roflMetricsSet
+	for _, name := range slices.Sorted(maps.Keys(parsed)) {
+		mf := parsed[name]
+		fqName := prefix + name
+		family := roflMetricFamily{
+			help: mf.GetHelp(),
+			kind: mf.GetType(),
+		}
+
+		for _, m := range mf.GetMetric() {
+			if len(metrics) >= roflMetricsMaxSeries {
+				return fmt.Errorf("too many series (maximum: %d)", roflMetricsMaxSeries)
+			}
+
+			metric, err := newROFLMetric(runtimeID, fqName, family, m)
+			if err != nil {
+				return fmt.Errorf("metric '%s': %w", name, err)
+			}
+			metrics = append(metrics, metric)
+		}
+	}
+
+	key := roflMetricsKey{runtimeID, componentID}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Ensure the metrics can be gathered together with the ones pushed by other components, as
+	// otherwise duplicate or inconsistent series would break gathering of all node metrics.
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics)
+	for otherKey, other := range c.components {
+		if otherKey == key {
+			continue
+		}
+		registry.MustRegister(other)
+	}
+	mfs, err := registry.Gather()
+	if err != nil {
+		return fmt.Errorf("inconsistent metrics: %w", err)
+	}
+	if err = checkLabelNamesConsistency(mfs); err != nil {
+		return fmt.Errorf("inconsistent metrics: %w", err)
+	}
+
+	c.components[key] = metrics
+	return nil
+}
+
+// remove removes any metrics pushed by the given component.
+func (c *roflMetricsCollector) remove(runtimeID common.Namespace, componentID component.ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.components, roflMetricsKey{runtimeID, componentID})
+}
+
+// checkLabelNamesConsistency ensures that all series of each metric family have the same label
+// names, which gathering does not verify for unchecked collectors.
+func checkLabelNamesConsistency(mfs []*dto.MetricFamily) error {
+	for _, mf := range mfs {
+		var expected []string
+		for i, m := range mf.GetMetric() {
+			names := make([]string, 0, len(m.GetLabel()))
+			for _, label := range m.GetLabel() {
+				names = append(names, label.GetName())
+			}
+			if i == 0 {
+				expected = names
+				continue
+			}
+			if !slices.Equal(names, expected) {
+				return fmt.Errorf("metric '%s' has inconsistent label names", mf.GetName())
+			}
+		}
+	}
+	return nil
+}
+
+func newROFLMetric(runtimeID common.Namespace, fqName string, family roflMetricFamily, m *dto.Metric) (prometheus.Metric, error) {
+	labels := m.GetLabel()
+	if len(labels) > roflMetricsMaxLabels {
+		return nil, fmt.Errorf("too many labels (maximum: %d)", roflMetricsMaxLabels)
+	}
+
+	labelNames := []string{roflMetricsLabelRuntime}
+	labelValues := []string{runtimeID.String()}
+	for _, label := range labels {
+		if label.GetName() == roflMetricsLabelRuntime {
+			return nil, fmt.Errorf("label '%s' is reserved", roflMetricsLabelRuntime)
+		}
+		if len(label.GetValue()) > roflMetricsMaxLabelValueLength {
+			return nil, fmt.Errorf("value of label '%s' too long (maximum: %d)", label.GetName(), roflMetricsMaxLabelValueLength)
+		}
+		labelNames = append(labelNames, label.GetName())
+		labelValues = append(labelValues, label.GetValue())
+	}
+	desc := prometheus.NewDesc(fqName, family.help, labelNames, nil)
+
+	switch family.kind {
+	case dto.MetricType_COUNTER:
+		return prometheus.NewConstMetric(desc, prometheus.CounterValue, m.GetCounter().GetValue(), labelValues...)
+	case dto.MetricType_GAUGE:
+		return prometheus.NewConstMetric(desc, prometheus.GaugeValue, m.GetGauge().GetValue(), labelValues...)
+	case dto.MetricType_HISTOGRAM:
+		h := m.GetHistogram()
+		buckets := make(map[float64]uint64)
+		for _, b := range h.GetBucket() {
+			if math.IsInf(b.GetUpperBound(), 1) {
+				continue
+			}
+			buckets[b.GetUpperBound()] = b.GetCumulativeCount()
+		}
+		return prometheus.NewConstHistogram(desc, h.GetSampleCount(), h.GetSampleSum(), buckets, labelValues...)
+	default:
+		return nil, fmt.Errorf("unsupported metric type '%s'", family.kind)
+	}
+}
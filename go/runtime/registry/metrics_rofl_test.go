@@ -0,0 +1,115 @@
+package registry
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+func TestROFLMetrics(t *testing.T) {
+	require := require.New(t)
+
+	collector := newROFLMetricsCollector()
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+
+	var runtimeID common.Namespace
+	err := runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	require.NoError(err)
+	appID := component.ID{Kind: component.ROFL, Name: "my-app"}
+
+	err = collector.push(runtimeID, appID, `
+# HELP requests Number of processed requests.
+# TYPE requests counter
+requests{method="get"} 10
+requests{method="post"} 2
+# HELP queue_size Size of the queue.
+# TYPE queue_size gauge
+queue_size 3
+# HELP latency Request latency.
+# TYPE latency histogram
+latency_bucket{le="0.1"} 1
+latency_bucket{le="1"} 3
+latency_bucket{le="+Inf"} 4
+latency_sum 2.5
+latency_count 4
+`)
+	require.NoError(err, "push")
+
+	err = testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP oasis_rofl_my_app_latency Request latency.
+# TYPE oasis_rofl_my_app_latency histogram
+oasis_rofl_my_app_latency_bucket{runtime="8000000000000000000000000000000000000000000000000000000000000000",le="0.1"} 1
+oasis_rofl_my_app_latency_bucket{runtime="8000000000000000000000000000000000000000000000000000000000000000",le="1"} 3
+oasis_rofl_my_app_latency_bucket{runtime="8000000000000000000000000000000000000000000000000000000000000000",le="+Inf"} 4
+oasis_rofl_my_app_latency_sum{runtime="8000000000000000000000000000000000000000000000000000000000000000"} 2.5
+oasis_rofl_my_app_latency_count{runtime="8000000000000000000000000000000000000000000000000000000000000000"} 4
+# HELP oasis_rofl_my_app_queue_size Size of the queue.
+# TYPE oasis_rofl_my_app_queue_size gauge
+oasis_rofl_my_app_queue_size{runtime="8000000000000000000000000000000000000000000000000000000000000000"} 3
+# HELP oasis_rofl_my_app_requests Number of processed requests.
+# TYPE oasis_rofl_my_app_requests counter
+oasis_rofl_my_app_requests{method="get",runtime="8000000000000000000000000000000000000000000000000000000000000000"} 10
+oasis_rofl_my_app_requests{method="post",runtime="8000000000000000000000000000000000000000000000000000000000000000"} 2
+`))
+	require.NoError(err, "GatherAndCompare")
+
+	// Pushing replaces previously pushed metrics.
+	err = collector.push(runtimeID, appID, "# TYPE queue_size gauge\nqueue_size 5\n")
+	require.NoError(err, "push")
+	require.Equal(1, testutil.CollectAndCount(collector))
+
+	// Malformed or disallowed metrics should be rejected without replacing existing ones.
+	for _, tc := range []struct {
+		text string
+		err  string
+	}{
+		{"queue_size{", "malformed metrics"},
+		{"# TYPE x gauge\nx{runtime=\"other\"} 1\n", "label 'runtime' is reserved"},
+		{"# TYPE x summary\nx{quantile=\"0.5\"} 1\n", "unsupported metric type"},
+		{"x 1\n", "unsupported metric type"},
+		{"# TYPE x gauge\nx{l=\"" + strings.Repeat("a", 129) + "\"} 1\n", "too long"},
+		{"# TYPE x gauge\nx{a=\"1\",b=\"1\",c=\"1\",d=\"1\",e=\"1\",f=\"1\",g=\"1\",h=\"1\",i=\"1\"} 1\n", "too many labels"},
+	} {
+		err = collector.push(runtimeID, appID, tc.text)
+		require.ErrorContains(err, tc.err, tc.text)
+	}
+	require.Equal(1, testutil.CollectAndCount(collector))
+
+	// Duplicate and inconsistent series within a push should be rejected.
+	for _, text := range []string{
+		"# TYPE x gauge\nx{a=\"1\"} 1\nx{a=\"1\"} 2\n",
+		"# TYPE x gauge\nx{a=\"1\"} 1\nx{b=\"1\"} 2\n",
+	} {
+		err = collector.push(runtimeID, appID, text)
+		require.ErrorContains(err, "inconsistent metrics", text)
+	}
+
+	// Conflicting families of other components should be rejected.
+	var otherRuntimeID common.Namespace
+	for _, text := range []string{
+		"# TYPE queue_size counter\nqueue_size 5\n",
+		"# HELP queue_size Other help.\n# TYPE queue_size gauge\nqueue_size 5\n",
+		"# TYPE queue_size gauge\nqueue_size{a=\"1\"} 5\n",
+	} {
+		err = collector.push(otherRuntimeID, appID, text)
+		require.ErrorContains(err, "inconsistent metrics", text)
+	}
+	err = collector.push(otherRuntimeID, appID, "# TYPE queue_size gauge\nqueue_size 7\n")
+	require.NoError(err, "push")
+	require.Equal(2, testutil.CollectAndCount(collector))
+
+	// The node registry should still be able to gather all metrics.
+	_, err = registry.Gather()
+	require.NoError(err, "Gather")
+
+	collector.remove(runtimeID, appID)
+	collector.remove(otherRuntimeID, appID)
+	require.Equal(0, testutil.CollectAndCount(collector))
+}
@@ -640,6 +640,7 @@ func (r *runtimeRegistry) Name() string {
 func (r *runtimeRegistry) Start() error {
 	r.bundleManager.Start()
 	r.volumeManager.Start()
+	r.logManager.Start()
 
 	r.RLock()
 	defer r.RUnlock()
@@ -657,6 +658,7 @@ func (r *runtimeRegistry) Start() error {
 func (r *runtimeRegistry) Stop() {
 	r.bundleManager.Stop()
 	r.volumeManager.Stop()
+	r.logManager.Stop()
 
 	r.RLock()
 	defer r.RUnlock()
@@ -713,21 +715,28 @@ func New(
 		return nil, err
 	}
 
+	// Create bundle registry.
+	bundleRegistry := bundle.NewRegistry()
+
 	// Create volume manager.
-	volumeManager, err := volume.NewManager(dataDir)
+	volumeManager, err := volume.NewManager(dataDir, bundleRegistry)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create bundle registry and discovery.
-	bundleRegistry := bundle.NewRegistry()
+	// Create bundle discovery.
 	bundleManager, err := bundle.NewManager(dataDir, runtimeIDs, bundleRegistry, volumeManager)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create log manager.
-	logManager := log.NewManager(dataDir)
+	logManager, err := log.NewManager(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	initROFLMetrics()
 
 	// Create history keeper factory.
 	historyFactory, err := createHistoryFactory()
@@ -1,6 +1,9 @@
 package api
 
-import "github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+import (
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
 
 const (
 	// LocalRPCEndpointBundleManager is the name of the local RPC endpoint for the bundle manager.
@@ -90,4 +93,26 @@ type BundleInfo struct {
 type ComponentInfo struct {
 	// Name is the component name.
 	Name string `json:"name"`
+	// Status is the status of the component in case it is hosted.
+	Status *ComponentStatus `json:"status,omitempty"`
+}
+
+// ComponentStatus is the status of a hosted component.
+type ComponentStatus struct {
+	// State is the component state. It is one of `starting`, `running`, `stopped` or
+	// `crash_looping`.
+	State string `json:"state"`
+	// Crashes is the number of times the component terminated unexpectedly or failed to start.
+	Crashes uint64 `json:"crashes,omitempty"`
+	// Uptime is the number of seconds the component has been running for.
+	Uptime uint64 `json:"uptime,omitempty"`
+	// CPUTime is the CPU time in milliseconds consumed by the component since it was last
+	// started.
+	CPUTime uint64 `json:"cpu_time,omitempty"`
+	// Memory is the amount of resident memory used by the component in bytes.
+	Memory uint64 `json:"memory,omitempty"`
+	// LastAttestation is the UNIX timestamp of the last successful attestation of the component.
+	LastAttestation uint64 `json:"last_attestation,omitempty"`
+	// RAK is the runtime attestation key of the component.
+	RAK *signature.PublicKey `json:"rak,omitempty"`
 }
@@ -0,0 +1,25 @@
+package api
+
+const (
+	// LocalRPCEndpointMetrics is the name of the local RPC endpoint for metrics.
+	LocalRPCEndpointMetrics = "metrics"
+
+	// MethodMetricsPush is the name of the MetricsPush method.
+	MethodMetricsPush = "MetricsPush"
+)
+
+// MetricsPushRequest is a request to host to publish metrics of the component.
+//
+// The pushed metrics replace any metrics previously pushed by the component. They are re-exported
+// by the node with names prefixed by `oasis_rofl_<component-name>_` and with the `runtime` label
+// set to the runtime identifier. Metrics with duplicate series, inconsistent label names or
+// conflicting with metrics pushed by other components are rejected.
+//
+// The `PermissionMetricsPush` permission is required to call this method.
+type MetricsPushRequest struct {
+	// Metrics are the counters, gauges and histograms in the Prometheus text exposition format.
+	Metrics string `json:"metrics"`
+}
+
+// MetricsPushResponse is a response from the MetricsPush method.
+type MetricsPushResponse struct{}
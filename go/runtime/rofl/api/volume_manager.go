@@ -10,6 +10,12 @@ const (
 	MethodVolumeRemove = "VolumeRemove"
 	// MethodVolumeList is the name of the VolumeList method.
 	MethodVolumeList = "VolumeList"
+	// MethodVolumeSnapshot is the name of the VolumeSnapshot method.
+	MethodVolumeSnapshot = "VolumeSnapshot"
+	// MethodVolumeRestore is the name of the VolumeRestore method.
+	MethodVolumeRestore = "VolumeRestore"
+	// MethodVolumeSnapshotRemove is the name of the VolumeSnapshotRemove method.
+	MethodVolumeSnapshotRemove = "VolumeSnapshotRemove"
 )
 
 // VolumeAddRequest is a request to add a volume.
@@ -56,4 +62,61 @@ type VolumeInfo struct {
 	ID string `json:"id"`
 	// Labels is a set of labels assigned to this volume.
 	Labels map[string]string `json:"labels,omitempty"`
+	// Size is the disk space used by the volume in bytes, including its snapshots.
+	Size uint64 `json:"size,omitempty"`
+	// Snapshots are the snapshots of this volume.
+	Snapshots []*VolumeSnapshotInfo `json:"snapshots,omitempty"`
 }
+
+// VolumeSnapshotInfo is the volume snapshot information.
+type VolumeSnapshotInfo struct {
+	// Name is the name of the snapshot.
+	Name string `json:"name"`
+	// CreatedAt is the UNIX timestamp when the snapshot was created.
+	CreatedAt uint64 `json:"created_at"`
+	// Size is the size of the snapshot in bytes.
+	Size uint64 `json:"size,omitempty"`
+}
+
+// VolumeSnapshotRequest is a request to create a point-in-time snapshot of a volume.
+//
+// To obtain a consistent snapshot, the volume should not be in use by any component.
+//
+// The `PermissionVolumeSnapshot` permission is required to call this method.
+type VolumeSnapshotRequest struct {
+	// ID is the unique volume identifier.
+	ID string `json:"id"`
+	// Name is the name of the snapshot.
+	Name string `json:"name"`
+}
+
+// VolumeSnapshotResponse is a response from the VolumeSnapshot method.
+type VolumeSnapshotResponse struct{}
+
+// VolumeRestoreRequest is a request to restore a volume from a snapshot.
+//
+// The volume must not be in use by any component while it is being restored.
+//
+// The `PermissionVolumeSnapshot` permission is required to call this method.
+type VolumeRestoreRequest struct {
+	// ID is the unique volume identifier.
+	ID string `json:"id"`
+	// Name is the name of the snapshot.
+	Name string `json:"name"`
+}
+
+// VolumeRestoreResponse is a response from the VolumeRestore method.
+type VolumeRestoreResponse struct{}
+
+// VolumeSnapshotRemoveRequest is a request to remove a volume snapshot.
+//
+// The `PermissionVolumeSnapshot` permission is required to call this method.
+type VolumeSnapshotRemoveRequest struct {
+	// ID is the unique volume identifier.
+	ID string `json:"id"`
+	// Name is the name of the snapshot.
+	Name string `json:"name"`
+}
+
+// VolumeSnapshotRemoveResponse is a response from the VolumeSnapshotRemove method.
+type VolumeSnapshotRemoveResponse struct{}
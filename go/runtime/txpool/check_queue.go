@@ -83,6 +83,17 @@ func (q *checkTxQueue) pop() []*PendingCheckTransaction {
 	return batch
 }
 
+func (q *checkTxQueue) all() []*PendingCheckTransaction {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pcts := make([]*PendingCheckTransaction, 0, q.txs.Len())
+	for i := range q.txs.Len() {
+		pcts = append(pcts, q.txs.At(i))
+	}
+	return pcts
+}
+
 func (q *checkTxQueue) size() int {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -1,7 +1,21 @@
 // Package config implements the txpool configuration options.
 package config
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// SchedulingPolicyPriority schedules transactions by descending priority.
+	SchedulingPolicyPriority = "priority"
+	// SchedulingPolicyFairShare schedules transactions of different senders in a round-robin
+	// fashion, ordering transactions within each round by descending priority.
+	SchedulingPolicyFairShare = "fair-share"
+	// SchedulingPolicySenderCap schedules transactions by descending priority while limiting the
+	// number of transactions of a single sender in each batch.
+	SchedulingPolicySenderCap = "sender-cap"
+)
 
 // Config is the runtime transaction pool configuration structure.
 type Config struct {
@@ -15,4 +29,35 @@ type Config struct {
 	RecheckInterval uint64 `yaml:"recheck_interval"`
 	// Republish interval.
 	RepublishInterval time.Duration
+
+	// Transaction scheduling policy (priority, fair-share, sender-cap).
+	SchedulingPolicy string `yaml:"scheduling_policy,omitempty"`
+	// Maximum number of transactions of a single sender in each batch (sender-cap policy).
+	MaxSenderTxsPerBatch uint64 `yaml:"max_sender_txs_per_batch,omitempty"`
+
+	// Enable the persistent journal of locally submitted transactions which is replayed on startup.
+	JournalEnabled bool `yaml:"journal_enabled,omitempty"`
+	// Also journal all transactions in the main queue, not only locally submitted ones.
+	JournalMainQueue bool `yaml:"journal_main_queue,omitempty"`
+	// Maximum number of journaled transactions.
+	JournalMaxSize uint64 `yaml:"journal_max_size,omitempty"`
+	// Maximum age of journaled transactions, older transactions are not replayed.
+	JournalExpiry time.Duration `yaml:"journal_expiry,omitempty"`
+
+	// Allow node operators to evict transactions from the pool via the control API.
+	AllowEviction bool `yaml:"allow_eviction,omitempty"`
+}
+
+// ValidateScheduling validates the transaction scheduling configuration.
+func (c *Config) ValidateScheduling() error {
+	switch c.SchedulingPolicy {
+	case "", SchedulingPolicyPriority, SchedulingPolicyFairShare:
+	case SchedulingPolicySenderCap:
+		if c.MaxSenderTxsPerBatch == 0 {
+			return fmt.Errorf("max_sender_txs_per_batch must be set for the %s scheduling policy", c.SchedulingPolicy)
+		}
+	default:
+		return fmt.Errorf("unknown transaction scheduling policy: %s", c.SchedulingPolicy)
+	}
+	return nil
 }
@@ -0,0 +1,67 @@
+package txpool
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+// TxEventKind is the kind of a transaction pool event.
+type TxEventKind uint8
+
+const (
+	// TxEventQueued is emitted when a new transaction is queued for scheduling.
+	TxEventQueued TxEventKind = 1
+	// TxEventScheduled is emitted when a transaction is scheduled for execution.
+	TxEventScheduled TxEventKind = 2
+	// TxEventExpired is emitted when a transaction is removed from the pool as it can no longer
+	// be executed because its sender's sequence number has already been used.
+	TxEventExpired TxEventKind = 3
+	// TxEventEvicted is emitted when a transaction is removed from the pool for any other reason.
+	TxEventEvicted TxEventKind = 4
+)
+
+// String returns a string representation of a transaction event kind.
+func (k TxEventKind) String() string {
+	switch k {
+	case TxEventQueued:
+		return "queued"
+	case TxEventScheduled:
+		return "scheduled"
+	case TxEventExpired:
+		return "expired"
+	case TxEventEvicted:
+		return "evicted"
+	default:
+		return fmt.Sprintf("[unknown: %d]", k)
+	}
+}
+
+// TxEvent is an event about a transaction in the transaction pool.
+type TxEvent struct {
+	// Kind is the kind of the event.
+	Kind TxEventKind
+	// Hash is the transaction hash.
+	Hash hash.Hash
+	// Round is the round the transaction has been scheduled for (scheduled events only).
+	Round uint64
+	// Reason is the reason for removing the transaction (expired and evicted events only).
+	Reason string
+}
+
+// Transaction removal reasons.
+const (
+	reasonCheckFailed       = "check failed"
+	reasonUnderpriced       = "transaction underpriced"
+	reasonReplaced          = "replaced by a higher priority transaction"
+	reasonSeqUsed           = "sender sequence number already used"
+	reasonRejected          = "rejected during block processing"
+	reasonEvictedByOperator = "evicted by node operator"
+)
+
+func (t *txPool) publishTxEvents(events []*TxEvent) {
+	if len(events) == 0 {
+		return
+	}
+	t.txEventNotifier.Broadcast(events)
+}
@@ -120,7 +120,8 @@ func (h *minPriorityTxHeap) replace(new, old *mainQueueTransaction) {
 	heap.Fix(h, new.minHeapIndex)
 }
 
-// maxPriorityTxHeap is a heap of transactions ordered by descending priority.
+// maxPriorityTxHeap is a heap of transactions ordered by ascending scheduling round and
+// descending priority.
 type maxPriorityTxHeap []*mainQueueTransaction
 
 func (h maxPriorityTxHeap) Len() int {
@@ -128,6 +129,9 @@ func (h maxPriorityTxHeap) Len() int {
 }
 
 func (h maxPriorityTxHeap) Less(i, j int) bool {
+	if h[i].round != h[j].round {
+		return h[i].round < h[j].round
+	}
 	return h[i].priority > h[j].priority
 }
 
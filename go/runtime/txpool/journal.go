@@ -0,0 +1,212 @@
+package txpool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+const (
+	// journalFilename is the name of the transaction journal file in the runtime data directory.
+	journalFilename = "txpool.journal"
+	// journalRotateInterval is the interval at which the journal is regenerated from the current
+	// contents of the transaction pool.
+	journalRotateInterval = 10 * time.Minute
+)
+
+// errJournalFull is the error returned when an entry can't be appended since the journal is full.
+var errJournalFull = errors.New("txpool: journal is full")
+
+// journalEntry is a single transaction stored in the journal.
+type journalEntry struct {
+	// Tx is the raw transaction.
+	Tx []byte `json:"tx"`
+	// Local indicates whether the transaction was submitted by our own node.
+	Local bool `json:"local,omitempty"`
+	// FirstSeen is the UNIX timestamp when the transaction was first seen.
+	FirstSeen int64 `json:"first_seen"`
+}
+
+func newJournalEntry(tx *TxQueueMeta) *journalEntry {
+	return &journalEntry{
+		Tx:        tx.Raw(),
+		Local:     tx.local,
+		FirstSeen: tx.FirstSeen().Unix(),
+	}
+}
+
+func (e *journalEntry) firstSeen() time.Time {
+	return time.Unix(e.FirstSeen, 0)
+}
+
+// journal is an append-only on-disk log of transactions that is replayed on startup in order for
+// pending transactions to survive node restarts.
+//
+// Since transactions are never removed from the log, the journal is periodically regenerated
+// from the current contents of the transaction pool.
+type journal struct {
+	mu sync.Mutex
+
+	path    string
+	maxSize int
+	expiry  time.Duration
+
+	w    *os.File
+	size int
+
+	logger *logging.Logger
+}
+
+func newJournal(path string, maxSize int, expiry time.Duration) *journal {
+	return &journal{
+		path:    path,
+		maxSize: maxSize,
+		expiry:  expiry,
+		logger:  logging.GetLogger("runtime/txpool/journal"),
+	}
+}
+
+// load reads all non-expired entries from the journal. In case there are more entries than the
+// journal size limit, only the most recent ones are returned.
+func (j *journal) load(now time.Time) ([]*journalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := os.Open(j.path)
+	switch {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("txpool: failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []*journalEntry
+	seen := make(map[hash.Hash]struct{})
+	dec := cbor.NewDecoder(f)
+	for {
+		var entry journalEntry
+		if err = dec.Decode(&entry); err != nil {
+			if !errors.Is(err, io.EOF) {
+				// The last entry may have been partially written in case of a crash.
+				j.logger.Warn("failed to decode journal entry, ignoring the rest of the journal",
+					"err", err,
+					"num_entries", len(entries),
+				)
+			}
+			break
+		}
+		if j.expiry > 0 && now.Sub(entry.firstSeen()) > j.expiry {
+			continue
+		}
+		// Transactions may be journaled multiple times in case they were resubmitted.
+		h := hash.NewFromBytes(entry.Tx)
+		if _, ok := seen[h]; ok {
+			continue
+		}
+		seen[h] = struct{}{}
+		entries = append(entries, &entry)
+	}
+
+	if j.maxSize > 0 && len(entries) > j.maxSize {
+		entries = entries[len(entries)-j.maxSize:]
+	}
+	return entries, nil
+}
+
+// insert appends a new entry to the journal.
+//
+// In case the journal is full, errJournalFull is returned and the entry will only be included
+// on the next rotation if there is space.
+func (j *journal) insert(entry *journalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.maxSize > 0 && j.size >= j.maxSize {
+		return errJournalFull
+	}
+
+	if j.w == nil {
+		w, err := os.OpenFile(j.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("txpool: failed to open journal: %w", err)
+		}
+		j.w = w
+	}
+
+	if _, err := j.w.Write(cbor.Marshal(entry)); err != nil {
+		return fmt.Errorf("txpool: failed to write journal entry: %w", err)
+	}
+	j.size++
+	return nil
+}
+
+// rotate regenerates the journal so that it only contains the non-expired entries returned by
+// the given snapshot function.
+//
+// The journal lock is held while taking the snapshot and rewriting the journal, so any entries
+// inserted concurrently are either part of the snapshot or appended to the new journal.
+func (j *journal) rotate(snapshot func() []*journalEntry, now time.Time) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries := snapshot()
+
+	if j.w != nil {
+		_ = j.w.Close()
+		j.w = nil
+	}
+
+	tmpPath := j.path + ".new"
+	w, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("txpool: failed to create journal: %w", err)
+	}
+
+	var size int
+	for _, entry := range entries {
+		if j.maxSize > 0 && size >= j.maxSize {
+			break
+		}
+		if j.expiry > 0 && now.Sub(entry.firstSeen()) > j.expiry {
+			continue
+		}
+		if _, err = w.Write(cbor.Marshal(entry)); err != nil {
+			_ = w.Close()
+			return fmt.Errorf("txpool: failed to write journal entry: %w", err)
+		}
+		size++
+	}
+	if err = w.Sync(); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("txpool: failed to sync journal: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("txpool: failed to close journal: %w", err)
+	}
+	if err = os.Rename(tmpPath, j.path); err != nil {
+		return fmt.Errorf("txpool: failed to replace journal: %w", err)
+	}
+	j.size = size
+
+	return nil
+}
+
+// close closes the journal.
+func (j *journal) close() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.w != nil {
+		_ = j.w.Close()
+		j.w = nil
+	}
+}
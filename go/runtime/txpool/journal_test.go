@@ -0,0 +1,191 @@
+package txpool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
+)
+
+func TestJournal(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), journalFilename)
+	now := time.Now()
+
+	j := newJournal(path, 4, time.Hour)
+
+	// Loading a missing journal should succeed.
+	entries, err := j.load(now)
+	require.NoError(err, "load")
+	require.Empty(entries)
+
+	// Insert some entries.
+	for _, e := range []*journalEntry{
+		{Tx: []byte("a"), Local: true, FirstSeen: now.Unix()},
+		{Tx: []byte("b"), FirstSeen: now.Add(-2 * time.Hour).Unix()},
+		{Tx: []byte("a"), Local: true, FirstSeen: now.Unix()},
+		{Tx: []byte("c"), FirstSeen: now.Unix()},
+	} {
+		err = j.insert(e)
+		require.NoError(err, "insert")
+	}
+	err = j.insert(&journalEntry{Tx: []byte("d"), FirstSeen: now.Unix()})
+	require.ErrorIs(err, errJournalFull, "insert into a full journal")
+	j.close()
+
+	// Expired and duplicate entries should be skipped, the last entry did not fit.
+	entries, err = j.load(now)
+	require.NoError(err, "load")
+	require.Len(entries, 2)
+	require.Equal([]byte("a"), entries[0].Tx)
+	require.True(entries[0].Local)
+	require.Equal(now.Unix(), entries[0].firstSeen().Unix())
+	require.Equal([]byte("c"), entries[1].Tx)
+
+	// Simulate a partially written entry.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	require.NoError(err, "OpenFile")
+	_, err = f.Write([]byte{0xa3, 0x62})
+	require.NoError(err, "Write")
+	require.NoError(f.Close())
+
+	entries, err = j.load(now)
+	require.NoError(err, "load with truncated tail")
+	require.Len(entries, 2)
+
+	// Rotation should only keep the given non-expired entries up to the size limit.
+	err = j.rotate(func() []*journalEntry {
+		return []*journalEntry{
+			{Tx: []byte("e"), FirstSeen: now.Unix()},
+			{Tx: []byte("f"), FirstSeen: now.Add(-2 * time.Hour).Unix()},
+			{Tx: []byte("g"), FirstSeen: now.Unix()},
+			{Tx: []byte("h"), FirstSeen: now.Unix()},
+			{Tx: []byte("i"), FirstSeen: now.Unix()},
+			{Tx: []byte("j"), FirstSeen: now.Unix()},
+		}
+	}, now)
+	require.NoError(err, "rotate")
+
+	entries, err = j.load(now)
+	require.NoError(err, "load after rotate")
+	require.Len(entries, 4)
+	require.Equal([]byte("e"), entries[0].Tx)
+	require.Equal([]byte("g"), entries[1].Tx)
+	require.Equal([]byte("h"), entries[2].Tx)
+	require.Equal([]byte("i"), entries[3].Tx)
+
+	// Journal is full after rotation.
+	err = j.insert(&journalEntry{Tx: []byte("k"), FirstSeen: now.Unix()})
+	require.ErrorIs(err, errJournalFull, "insert into a full journal")
+	j.close()
+
+	entries, err = j.load(now)
+	require.NoError(err, "load")
+	require.Len(entries, 4)
+
+	// Entries should expire over time.
+	entries, err = j.load(now.Add(2 * time.Hour))
+	require.NoError(err, "load")
+	require.Empty(entries)
+}
+
+func TestJournalInsertDuringRotate(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), journalFilename)
+	now := time.Now()
+
+	j := newJournal(path, 0, time.Hour)
+
+	err := j.insert(&journalEntry{Tx: []byte("a"), FirstSeen: now.Unix()})
+	require.NoError(err, "insert")
+
+	// Insert an entry concurrently with the rotation, after the snapshot has been taken.
+	insertCh := make(chan error, 1)
+	err = j.rotate(func() []*journalEntry {
+		go func() {
+			insertCh <- j.insert(&journalEntry{Tx: []byte("b"), FirstSeen: now.Unix()})
+		}()
+		return []*journalEntry{{Tx: []byte("a"), FirstSeen: now.Unix()}}
+	}, now)
+	require.NoError(err, "rotate")
+	require.NoError(<-insertCh, "insert during rotate")
+	j.close()
+
+	// Both entries should survive a reload.
+	j = newJournal(path, 0, time.Hour)
+	entries, err := j.load(now)
+	require.NoError(err, "load")
+	require.Len(entries, 2)
+	require.Equal([]byte("a"), entries[0].Tx)
+	require.Equal([]byte("b"), entries[1].Tx)
+}
+
+func TestJournalReplay(t *testing.T) {
+	require := require.New(t)
+
+	dataDir := t.TempDir()
+	now := time.Now()
+	cfg := config.Config{
+		MaxPoolSize:          10,
+		MaxLastSeenCacheSize: 10,
+		MaxCheckTxBatchSize:  10,
+		JournalEnabled:       true,
+		JournalMainQueue:     true,
+		JournalExpiry:        time.Hour,
+	}
+
+	loadJournal := func() [][]byte {
+		entries, err := newJournal(filepath.Join(dataDir, journalFilename), 0, time.Hour).load(now)
+		require.NoError(err, "load")
+		var txs [][]byte
+		for _, entry := range entries {
+			txs = append(txs, entry.Tx)
+		}
+		return txs
+	}
+
+	// Journal a remote transaction which will be replayed on startup.
+	j := newJournal(filepath.Join(dataDir, journalFilename), 0, time.Hour)
+	err := j.insert(&journalEntry{Tx: []byte("replayed"), FirstSeen: now.Unix()})
+	require.NoError(err, "insert")
+	j.close()
+
+	tp, err := New(common.Namespace{}, dataDir, cfg, nil, nil, nil)
+	require.NoError(err, "New")
+	pool := tp.(*txPool)
+
+	// Submit a local transaction before the journal is first rotated.
+	raw := []byte("submitted")
+	_, err = pool.submitTxMeta(&TxQueueMeta{
+		raw:       raw,
+		hash:      hash.NewFromBytes(raw),
+		firstSeen: now,
+		local:     true,
+	}, false, false)
+	require.NoError(err, "submitTxMeta")
+
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		pool.journalWorker()
+	}()
+
+	// Both the submitted and the not yet checked replayed transaction should be kept.
+	require.Eventually(func() bool {
+		return len(loadJournal()) == 2
+	}, 10*time.Second, 10*time.Millisecond)
+
+	pool.Stop()
+	<-doneCh
+
+	// Both transactions should survive a restart.
+	require.ElementsMatch([][]byte{[]byte("submitted"), []byte("replayed")}, loadJournal())
+}
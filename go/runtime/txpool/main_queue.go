@@ -21,6 +21,10 @@ type mainQueueTransaction struct {
 	// priority defines the transaction's priority as specified by the runtime.
 	priority uint64
 
+	// round is the scheduling round assigned by the scheduling policy while the transaction
+	// is pending schedule.
+	round uint64
+
 	// seqHeapIndex is the position in the sender's sequence heap where
 	// transactions are sorted by sequence number.
 	seqHeapIndex int
@@ -55,13 +59,24 @@ type mainQueue struct {
 	scheduler *mainQueueScheduler
 }
 
-// newMainQueue creates a new main queue with the given capacity.
-func newMainQueue(capacity int) *mainQueue {
+// newMainQueue creates a new main queue with the given capacity and scheduling policy.
+func newMainQueue(capacity int, policy schedulingPolicy) *mainQueue {
 	return &mainQueue{
-		scheduler: newMainQueueScheduler(capacity),
+		scheduler: newMainQueueScheduler(capacity, policy),
 	}
 }
 
+// SetEvictHandler sets the callback invoked for every transaction that is removed from the queue
+// as it has been replaced, expired or was underpriced.
+//
+// The callback is invoked while holding the queue lock and must not block.
+func (q *mainQueue) SetEvictHandler(fn func(*TxEvent)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.scheduler.onEvict = fn
+}
+
 // Size returns the current number of transactions in the queue.
 func (q *mainQueue) Size() int {
 	q.mu.Lock()
@@ -128,6 +143,29 @@ func (q *mainQueue) Drain() []*TxQueueMeta {
 	return q.scheduler.drain()
 }
 
+// Evict removes the transaction with the given hash from the queue. It returns false in case the
+// transaction is not in the queue.
+func (q *mainQueue) Evict(hash hash.Hash) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	tx, ok := q.scheduler.get(hash)
+	if !ok {
+		return false
+	}
+	q.scheduler.delete(tx)
+	return true
+}
+
+// Status returns the sequence number state of all senders and the status of all transactions
+// currently in the queue.
+func (q *mainQueue) Status() ([]*SenderStatus, []*TransactionStatus) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.scheduler.status()
+}
+
 // All returns all transactions currently in the queue.
 func (q *mainQueue) All() []*TxQueueMeta {
 	q.mu.Lock()
@@ -1,8 +1,11 @@
 package txpool
 
 import (
+	"container/heap"
 	"fmt"
+	"maps"
 	"math"
+	"slices"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 )
@@ -11,12 +14,22 @@ import (
 // at once.
 const maxBatchSize = 100
 
+var (
+	errTxExpired                = fmt.Errorf("transaction expired")
+	errTxReplacementUnderpriced = fmt.Errorf("replacement transaction underpriced")
+	errTxUnderpriced            = fmt.Errorf("transaction underpriced")
+)
+
 // mainQueueScheduler manages and prepares transactions for scheduling.
 type mainQueueScheduler struct {
 	// capacity is the maximum number of transactions that can be stored
 	// in the scheduler.
 	capacity int
 
+	// policy determines the order in which transactions of different
+	// senders are scheduled.
+	policy schedulingPolicy
+
 	// txs contains all transactions for quick lookup by transaction hash.
 	txs map[hash.Hash]*mainQueueTransaction
 
@@ -38,17 +51,27 @@ type mainQueueScheduler struct {
 	// with the sequence number of their most recent transaction that has been
 	// scheduled for execution.
 	scheduled map[string]uint64
+
+	// counts is a temporary per-schedule map that associates each sender
+	// with the number of their transactions that have been scheduled.
+	counts map[string]int
+
+	// onEvict is an optional callback invoked for every transaction that was
+	// already in the scheduler and has been removed without being used.
+	onEvict func(*TxEvent)
 }
 
 // newMainQueueScheduler creates a new transaction scheduler for the main queue.
-func newMainQueueScheduler(capacity int) *mainQueueScheduler {
+func newMainQueueScheduler(capacity int, policy schedulingPolicy) *mainQueueScheduler {
 	return &mainQueueScheduler{
 		capacity:  capacity,
+		policy:    policy,
 		txs:       make(map[hash.Hash]*mainQueueTransaction),
 		senders:   make(map[string]*senderTxHeap),
 		minHeap:   make(minPriorityTxHeap, 0),
 		maxHeap:   make(maxPriorityTxHeap, 0),
 		scheduled: make(map[string]uint64),
+		counts:    make(map[string]int),
 	}
 }
 
@@ -72,6 +95,52 @@ func (s *mainQueueScheduler) all() []*TxQueueMeta {
 	return txs
 }
 
+// status returns the sequence number state of all senders and the status of all transactions
+// currently in the scheduler, both ordered by sender and sequence number.
+func (s *mainQueueScheduler) status() ([]*SenderStatus, []*TransactionStatus) {
+	senders := make([]*SenderStatus, 0, len(s.senders))
+	txs := make([]*TransactionStatus, 0, len(s.txs))
+
+	for _, sender := range slices.Sorted(maps.Keys(s.senders)) {
+		seqHeap := s.senders[sender]
+
+		ss := &SenderStatus{
+			Sender:       []byte(sender),
+			StateSeq:     seqHeap.seq,
+			Transactions: len(seqHeap.txs),
+		}
+		for seq := seqHeap.seq; ; seq++ {
+			if _, ok := seqHeap.get(seq); !ok {
+				if ss.Schedulable < ss.Transactions {
+					ss.MissingSeq = &seq
+				}
+				break
+			}
+			ss.Schedulable++
+			if seq == math.MaxUint64 {
+				break
+			}
+		}
+		senders = append(senders, ss)
+
+		for _, seq := range slices.Sorted(maps.Keys(seqHeap.txs)) {
+			tx := seqHeap.txs[seq]
+			txs = append(txs, &TransactionStatus{
+				Hash:      tx.meta.Hash(),
+				Size:      tx.meta.Size(),
+				FirstSeen: tx.meta.FirstSeen(),
+				Local:     tx.meta.local,
+				Rechecks:  tx.meta.Rechecks(),
+				Sender:    []byte(tx.sender),
+				Seq:       tx.seq,
+				Priority:  tx.priority,
+			})
+		}
+	}
+
+	return senders, txs
+}
+
 // clear removes all transactions from the scheduler.
 func (s *mainQueueScheduler) clear() {
 	// Do not clear the schedule, as the scheduler needs to keep track of what
@@ -104,16 +173,17 @@ func (s *mainQueueScheduler) add(tx *mainQueueTransaction, seq uint64) error {
 
 	// Reject expired transaction.
 	if tx.seq < seqHeap.seq {
-		return fmt.Errorf("transaction expired")
+		return errTxExpired
 	}
 
 	// Replace existing transaction.
 	if old, ok := seqHeap.get(tx.seq); ok {
 		if old.priority >= tx.priority {
-			return fmt.Errorf("replacement transaction underpriced")
+			return errTxReplacementUnderpriced
 		}
 
 		s.replace(tx, old, seqHeap)
+		s.notifyEvicted(old, TxEventEvicted, reasonReplaced)
 		return nil
 	}
 
@@ -123,8 +193,9 @@ func (s *mainQueueScheduler) add(tx *mainQueueTransaction, seq uint64) error {
 	// Remove transaction with the lowest priority if limit reached.
 	if lowest, ok := s.trim(); ok {
 		if tx == lowest {
-			return fmt.Errorf("transaction underpriced")
+			return errTxUnderpriced
 		}
+		s.notifyEvicted(lowest, TxEventEvicted, reasonUnderpriced)
 	}
 
 	return nil
@@ -171,6 +242,7 @@ func (s *mainQueueScheduler) forward(sender string, seq uint64) {
 		}
 
 		s.remove(tx, seqHeap)
+		s.notifyEvicted(tx, TxEventExpired, reasonSeqUsed)
 	}
 }
 
@@ -211,25 +283,44 @@ func (s *mainQueueScheduler) scheduleOne() (*TxQueueMeta, bool) {
 		return nil, false
 	}
 
-	if next, ok := s.nextSchedulable(highest); ok {
+	s.scheduled[highest.sender] = highest.seq
+	s.counts[highest.sender]++
+
+	if next, ok := s.nextSchedulable(highest); ok && s.policy.admit(s.counts[highest.sender]) {
+		next.round = s.policy.round(s.counts[highest.sender])
 		s.maxHeap.replace(next, highest)
 	} else {
 		s.maxHeap.remove(highest)
 	}
 
-	s.scheduled[highest.sender] = highest.seq
-
 	return highest.meta, true
 }
 
 // reset resets the ongoing schedule and restores schedulable transactions
 // in the max heap which were removed or replaced during scheduling.
 func (s *mainQueueScheduler) reset() {
+	if len(s.scheduled) == 0 {
+		return
+	}
+
 	for sender, seq := range s.scheduled {
 		s.restoreMaxHeap(sender, seq)
 	}
 
 	clear(s.scheduled)
+	clear(s.counts)
+
+	// Reset scheduling rounds as all senders start from scratch in the next schedule.
+	var changed bool
+	for _, tx := range s.maxHeap {
+		if tx.round != 0 {
+			tx.round = 0
+			changed = true
+		}
+	}
+	if changed {
+		heap.Init(&s.maxHeap)
+	}
 }
 
 // restoreMaxHeap restores the sender's schedulable transaction
@@ -245,6 +336,11 @@ func (s *mainQueueScheduler) restoreMaxHeap(sender string, seq uint64) {
 	}
 
 	if seq < math.MaxUint64 && seqHeap.seq == seq+1 {
+		// The next transaction is already pending, unless the scheduling
+		// policy did not admit it.
+		if first, _ := seqHeap.peek(); first.seq == seqHeap.seq && !isPendingSchedule(first) {
+			s.maxHeap.push(first)
+		}
 		return
 	}
 
@@ -253,9 +349,13 @@ func (s *mainQueueScheduler) restoreMaxHeap(sender string, seq uint64) {
 		first = nil
 	}
 
+	// The next transaction may not be pending in case the scheduling policy
+	// did not admit it.
 	var current *mainQueueTransaction
 	if seq < math.MaxUint64 {
-		current, _ = seqHeap.get(seq + 1)
+		if tx, ok := seqHeap.get(seq + 1); ok && isPendingSchedule(tx) {
+			current = tx
+		}
 	}
 
 	switch {
@@ -301,6 +401,7 @@ func (s *mainQueueScheduler) insert(tx *mainQueueTransaction, seqHeap *senderTxH
 	seqHeap.push(tx)
 	s.minHeap.push(tx)
 	if s.isSchedulable(tx, seqHeap) {
+		tx.round = s.policy.round(s.counts[tx.sender])
 		s.maxHeap.push(tx)
 	}
 }
@@ -313,6 +414,7 @@ func (s *mainQueueScheduler) replace(new, old *mainQueueTransaction, seqHeap *se
 	seqHeap.replace(new, old)
 	s.minHeap.replace(new, old)
 	if isPendingSchedule(old) {
+		new.round = old.round
 		s.maxHeap.replace(new, old)
 	}
 }
@@ -343,18 +445,31 @@ func (s *mainQueueScheduler) nextSchedulable(tx *mainQueueTransaction) (*mainQue
 // A transaction is schedulable if:
 //   - No schedule is in progress, and this transaction is the first
 //     pending transaction for the sender.
-//   - A schedule is in progress, and this transaction's sequence number
-//     follows the last scheduled transaction for the same sender.
+//   - A schedule is in progress, this transaction's sequence number
+//     follows the last scheduled transaction for the same sender and
+//     the scheduling policy admits another transaction of the sender.
 func (s *mainQueueScheduler) isSchedulable(tx *mainQueueTransaction, seqHeap *senderTxHeap) bool {
 	if last, ok := s.scheduled[tx.sender]; ok {
 		if last == math.MaxUint64 {
 			return false
 		}
-		return tx.seq == last+1
+		return tx.seq == last+1 && s.policy.admit(s.counts[tx.sender])
 	}
 	return tx.seq == seqHeap.seq
 }
 
+// notifyEvicted invokes the eviction callback, if any, for the given transaction.
+func (s *mainQueueScheduler) notifyEvicted(tx *mainQueueTransaction, kind TxEventKind, reason string) {
+	if s.onEvict == nil {
+		return
+	}
+	s.onEvict(&TxEvent{
+		Kind:   kind,
+		Hash:   tx.meta.hash,
+		Reason: reason,
+	})
+}
+
 // isPendingSchedule returns true if the transaction is in the max heap
 // waiting to be scheduled.
 func isPendingSchedule(tx *mainQueueTransaction) bool {
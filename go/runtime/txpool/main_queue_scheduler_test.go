@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
 )
 
 var testTransactionID int64
@@ -44,7 +45,7 @@ func testPrepareSchedule() []*TxQueueMeta {
 }
 
 func testPrepareMainQueueScheduler(capacity int, txs []*mainQueueTransaction) (*mainQueueScheduler, error) {
-	s := newMainQueueScheduler(capacity)
+	s := newMainQueueScheduler(capacity, priorityPolicy{})
 	for _, tx := range txs {
 		if err := s.add(tx, 0); err != nil {
 			return nil, err
@@ -77,7 +78,7 @@ var (
 
 func TestSize(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
-		s := newMainQueueScheduler(10)
+		s := newMainQueueScheduler(10, priorityPolicy{})
 
 		require.Equal(t, 0, s.size())
 	})
@@ -92,7 +93,7 @@ func TestSize(t *testing.T) {
 
 func TestGet(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
-		s := newMainQueueScheduler(10)
+		s := newMainQueueScheduler(10, priorityPolicy{})
 
 		_, ok := s.get(testTxs[0].meta.hash)
 		require.False(t, ok)
@@ -118,7 +119,7 @@ func TestGet(t *testing.T) {
 
 func TestAll(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
-		s := newMainQueueScheduler(10)
+		s := newMainQueueScheduler(10, priorityPolicy{})
 
 		txs := s.all()
 		require.Equal(t, 0, len(txs))
@@ -138,7 +139,7 @@ func TestAll(t *testing.T) {
 
 func TestClear(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
-		s := newMainQueueScheduler(10)
+		s := newMainQueueScheduler(10, priorityPolicy{})
 
 		s.clear()
 		require.Equal(t, 0, s.size())
@@ -155,7 +156,7 @@ func TestClear(t *testing.T) {
 
 func TestDrain(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
-		s := newMainQueueScheduler(10)
+		s := newMainQueueScheduler(10, priorityPolicy{})
 
 		txs := s.drain()
 		require.Equal(t, 0, len(txs))
@@ -175,7 +176,7 @@ func TestDrain(t *testing.T) {
 
 func TestAdd(t *testing.T) {
 	t.Run("Multiple transactions", func(t *testing.T) {
-		s := newMainQueueScheduler(10)
+		s := newMainQueueScheduler(10, priorityPolicy{})
 
 		for i, tx := range testTxs {
 			err := s.add(tx, 0)
@@ -189,7 +190,7 @@ func TestAdd(t *testing.T) {
 	})
 
 	t.Run("Duplicate transaction", func(t *testing.T) {
-		s := newMainQueueScheduler(10)
+		s := newMainQueueScheduler(10, priorityPolicy{})
 		tx := newTestTransaction(0, 0, 0)
 
 		// Add transaction.
@@ -209,7 +210,7 @@ func TestAdd(t *testing.T) {
 	})
 
 	t.Run("Replace transaction", func(t *testing.T) {
-		s := newMainQueueScheduler(10)
+		s := newMainQueueScheduler(10, priorityPolicy{})
 
 		// Transactions with the same sequence number but different priorities.
 		txs := []*mainQueueTransaction{
@@ -257,7 +258,7 @@ func TestAdd(t *testing.T) {
 	})
 
 	t.Run("Full queue", func(t *testing.T) {
-		s := newMainQueueScheduler(5)
+		s := newMainQueueScheduler(5, priorityPolicy{})
 
 		// Fill the queue.
 		txs := make([]*mainQueueTransaction, 0, 5)
@@ -292,7 +293,7 @@ func TestAdd(t *testing.T) {
 
 func TestForward(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
-		s := newMainQueueScheduler(10)
+		s := newMainQueueScheduler(10, priorityPolicy{})
 
 		s.forward(testTxs[0].sender, 10)
 		require.Equal(t, 0, s.size())
@@ -333,7 +334,7 @@ func TestForward(t *testing.T) {
 
 func TestHandleTxUsed(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
-		s := newMainQueueScheduler(10)
+		s := newMainQueueScheduler(10, priorityPolicy{})
 
 		hash := hash.Hash{1}
 		s.handleTxUsed(hash)
@@ -418,7 +419,7 @@ func TestSchedule(t *testing.T) {
 
 func TestReset(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
-		s := newMainQueueScheduler(10)
+		s := newMainQueueScheduler(10, priorityPolicy{})
 
 		s.reset()
 		s.reset()
@@ -437,3 +438,222 @@ func TestReset(t *testing.T) {
 		}
 	})
 }
+
+func TestStatus(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		s := newMainQueueScheduler(10, priorityPolicy{})
+
+		senders, txs := s.status()
+		require.Empty(t, senders)
+		require.Empty(t, txs)
+	})
+
+	t.Run("Gap", func(t *testing.T) {
+		txs := []*mainQueueTransaction{
+			newTestTransaction(0, 0, 10),
+			newTestTransaction(0, 1, 10),
+			newTestTransaction(0, 3, 10),
+			newTestTransaction(1, 0, 20),
+		}
+		s, err := testPrepareMainQueueScheduler(10, txs)
+		require.NoError(t, err)
+
+		senders, statuses := s.status()
+		require.Len(t, senders, 2)
+		require.Equal(t, []byte("sender-0"), senders[0].Sender)
+		require.Equal(t, 3, senders[0].Transactions)
+		require.Equal(t, 2, senders[0].Schedulable)
+		require.NotNil(t, senders[0].MissingSeq)
+		require.EqualValues(t, 2, *senders[0].MissingSeq)
+		require.Equal(t, []byte("sender-1"), senders[1].Sender)
+		require.Equal(t, 1, senders[1].Schedulable)
+		require.Nil(t, senders[1].MissingSeq)
+
+		require.Len(t, statuses, 4)
+		require.Equal(t, txs[0].meta.Hash(), statuses[0].Hash)
+		require.Equal(t, txs[2].meta.Hash(), statuses[2].Hash)
+		require.EqualValues(t, 3, statuses[2].Seq)
+		require.EqualValues(t, 20, statuses[3].Priority)
+	})
+}
+
+func TestEvictHandler(t *testing.T) {
+	require := require.New(t)
+
+	var events []*TxEvent
+	s := newMainQueueScheduler(3, priorityPolicy{})
+	s.onEvict = func(ev *TxEvent) {
+		events = append(events, ev)
+	}
+
+	txs := []*mainQueueTransaction{
+		newTestTransaction(0, 0, 10),
+		newTestTransaction(0, 1, 10),
+		newTestTransaction(1, 0, 5),
+	}
+	for _, tx := range txs {
+		require.NoError(s.add(tx, 0))
+	}
+	require.Empty(events)
+
+	// Replacing a transaction evicts the old one.
+	replacement := newTestTransaction(0, 1, 20)
+	require.NoError(s.add(replacement, 0))
+	require.Len(events, 1)
+	require.Equal(TxEventEvicted, events[0].Kind)
+	require.Equal(txs[1].meta.hash, events[0].Hash)
+	require.Equal(reasonReplaced, events[0].Reason)
+
+	// Exceeding the capacity evicts the transaction with the lowest priority.
+	tx := newTestTransaction(2, 0, 30)
+	require.NoError(s.add(tx, 0))
+	require.Len(events, 2)
+	require.Equal(TxEventEvicted, events[1].Kind)
+	require.Equal(txs[2].meta.hash, events[1].Hash)
+	require.Equal(reasonUnderpriced, events[1].Reason)
+
+	// Rejected transactions are not reported as they were never in the scheduler.
+	err := s.add(newTestTransaction(3, 0, 0), 0)
+	require.ErrorIs(err, errTxUnderpriced)
+	require.Len(events, 2)
+
+	// Forwarding the sender expires transactions with used sequence numbers.
+	s.forward(txs[0].sender, 2)
+	require.Len(events, 4)
+	for i, h := range []hash.Hash{txs[0].meta.hash, replacement.meta.hash} {
+		require.Equal(TxEventExpired, events[2+i].Kind)
+		require.Equal(h, events[2+i].Hash)
+		require.Equal(reasonSeqUsed, events[2+i].Reason)
+	}
+
+	// Used transactions are not reported.
+	s.handleTxUsed(tx.meta.hash)
+	require.Len(events, 4)
+
+	err = s.add(newTestTransaction(0, 1, 10), 2)
+	require.ErrorIs(err, errTxExpired)
+}
+
+func testScheduleSenders(txs []*TxQueueMeta, senders map[hash.Hash]int) []int {
+	order := make([]int, 0, len(txs))
+	for _, tx := range txs {
+		order = append(order, senders[tx.Hash()])
+	}
+	return order
+}
+
+func TestSchedulingPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		policy   schedulingPolicy
+		expected []int
+	}{
+		{"Priority", priorityPolicy{}, []int{0, 0, 0, 0, 1, 1, 2, 2}},
+		{"FairShare", fairSharePolicy{}, []int{0, 1, 2, 0, 1, 2, 0, 0}},
+		{"SenderCap", senderCapPolicy{maxTxs: 2}, []int{0, 0, 1, 1, 2, 2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// The first sender has many high priority transactions, the others have a few lower
+			// priority transactions each.
+			var txs []*mainQueueTransaction
+			senders := make(map[hash.Hash]int)
+			for sender, n := range []int{4, 2, 2} {
+				for seq := range n {
+					tx := newTestTransaction(sender, uint64(seq), uint64(100-10*sender-seq))
+					txs = append(txs, tx)
+					senders[tx.meta.Hash()] = sender
+				}
+			}
+
+			s := newMainQueueScheduler(10, tc.policy)
+			for _, tx := range txs {
+				err := s.add(tx, 0)
+				require.NoError(t, err)
+			}
+
+			// Repeat to ensure the schedule is correctly reset.
+			for range 2 {
+				scheduled := s.schedule(100)
+				require.Equal(t, tc.expected, testScheduleSenders(scheduled, senders))
+
+				s.reset()
+			}
+
+			// Scheduling in multiple steps should give the same result.
+			scheduled := s.schedule(3)
+			scheduled = append(scheduled, s.schedule(100)...)
+			require.Equal(t, tc.expected, testScheduleSenders(scheduled, senders))
+			s.reset()
+
+			// The next transactions of a capped sender should be scheduled after the first ones
+			// have been used.
+			_ = s.schedule(100)
+			s.handleTxUsed(txs[0].meta.Hash())
+			s.handleTxUsed(txs[1].meta.Hash())
+			s.reset()
+
+			scheduled = s.schedule(100)
+			require.Len(t, scheduled, len(txs)-2)
+			require.Equal(t, 0, senders[scheduled[0].Hash()])
+			s.reset()
+		})
+	}
+}
+
+func TestNewSchedulingPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		cfg      config.Config
+		expected schedulingPolicy
+		valid    bool
+	}{
+		{config.Config{}, priorityPolicy{}, true},
+		{config.Config{SchedulingPolicy: config.SchedulingPolicyPriority}, priorityPolicy{}, true},
+		{config.Config{SchedulingPolicy: config.SchedulingPolicyFairShare}, fairSharePolicy{}, true},
+		{config.Config{SchedulingPolicy: config.SchedulingPolicySenderCap, MaxSenderTxsPerBatch: 5}, senderCapPolicy{maxTxs: 5}, true},
+		{config.Config{SchedulingPolicy: config.SchedulingPolicySenderCap}, nil, false},
+		{config.Config{SchedulingPolicy: "unknown"}, nil, false},
+	} {
+		policy, err := newSchedulingPolicy(&tc.cfg)
+		if !tc.valid {
+			require.Error(t, err, "newSchedulingPolicy(%s)", tc.cfg.SchedulingPolicy)
+			continue
+		}
+		require.NoError(t, err, "newSchedulingPolicy(%s)", tc.cfg.SchedulingPolicy)
+		require.Equal(t, tc.expected, policy)
+	}
+}
+
+func benchmarkSchedule(b *testing.B, policy schedulingPolicy) {
+	const (
+		numSenders = 1_000
+		numTxs     = 50_000
+	)
+
+	s := newMainQueueScheduler(numTxs, policy)
+	for i := range numTxs {
+		tx := newTestTransaction(i%numSenders, uint64(i/numSenders), uint64(i%997))
+		if err := s.add(tx, 0); err != nil {
+			b.Fatalf("failed to add transaction: %s", err)
+		}
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		for range 10 {
+			_ = s.schedule(maxBatchSize)
+		}
+		s.reset()
+	}
+}
+
+func BenchmarkSchedulePriority(b *testing.B) {
+	benchmarkSchedule(b, priorityPolicy{})
+}
+
+func BenchmarkScheduleFairShare(b *testing.B) {
+	benchmarkSchedule(b, fairSharePolicy{})
+}
+
+func BenchmarkScheduleSenderCap(b *testing.B) {
+	benchmarkSchedule(b, senderCapPolicy{maxTxs: 1})
+}
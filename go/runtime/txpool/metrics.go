@@ -45,12 +45,20 @@ var (
 		},
 		[]string{labelRuntime},
 	)
+	journalOverflows = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_txpool_journal_overflows",
+			Help: "Number of transactions that could not be journaled due to the journal being full.",
+		},
+		[]string{labelRuntime},
+	)
 	txpoolCollectors = []prometheus.Collector{
 		pendingCheckSize,
 		mainQueueSize,
 		rimQueueSize,
 		rejectedTransactions,
 		acceptedTransactions,
+		journalOverflows,
 	}
 
 	metricsOnce sync.Once
@@ -1,6 +1,7 @@
 package txpool
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
@@ -15,6 +16,11 @@ type TxQueueMeta struct {
 	// receiving from txSync) leave this in its default value. Transactions from those sources, however, only move
 	// through a limited area in the tx pool.
 	firstSeen time.Time
+	// local indicates whether the transaction was submitted by our own node.
+	local bool
+	// rechecks is the number of times the transaction has been rechecked. It must be accessed
+	// atomically.
+	rechecks uint64
 }
 
 // Raw returns the raw transaction data.
@@ -37,6 +43,11 @@ func (t *TxQueueMeta) FirstSeen() time.Time {
 	return t.firstSeen
 }
 
+// Rechecks returns the number of times the transaction has been rechecked.
+func (t *TxQueueMeta) Rechecks() uint64 {
+	return atomic.LoadUint64(&t.rechecks)
+}
+
 // UsableTransactionSource is a place to retrieve txs that are "good enough." "Good enough" variously means CheckTx'd,
 // came from roothash incoming message, or came from our own node.
 type UsableTransactionSource interface {
@@ -0,0 +1,78 @@
+package txpool
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
+)
+
+// schedulingPolicy determines how the main queue scheduler orders pending transactions of
+// different senders within a single schedule.
+//
+// The scheduler always respects sender sequence numbers and only considers the first pending
+// transaction of each sender. Pending transactions are assigned a scheduling round and are
+// scheduled in ascending round order, transactions within the same round are scheduled in
+// descending priority order.
+type schedulingPolicy interface {
+	// round returns the scheduling round of the next transaction of a sender which already has
+	// the given number of transactions scheduled in the current schedule.
+	round(scheduled int) uint64
+
+	// admit returns true iff another transaction of a sender which already has the given number
+	// of transactions scheduled in the current schedule may be scheduled.
+	admit(scheduled int) bool
+}
+
+// priorityPolicy schedules transactions by descending priority, regardless of the sender.
+type priorityPolicy struct{}
+
+func (priorityPolicy) round(int) uint64 {
+	return 0
+}
+
+func (priorityPolicy) admit(int) bool {
+	return true
+}
+
+// fairSharePolicy schedules transactions of different senders in a round-robin fashion, so that
+// each sender gets one transaction scheduled per round. Within a round, transactions are
+// scheduled by descending priority.
+type fairSharePolicy struct{}
+
+func (fairSharePolicy) round(scheduled int) uint64 {
+	return uint64(scheduled)
+}
+
+func (fairSharePolicy) admit(int) bool {
+	return true
+}
+
+// senderCapPolicy schedules transactions by descending priority while limiting the number of
+// transactions of a single sender in each schedule.
+type senderCapPolicy struct {
+	maxTxs int
+}
+
+func (senderCapPolicy) round(int) uint64 {
+	return 0
+}
+
+func (p senderCapPolicy) admit(scheduled int) bool {
+	return scheduled < p.maxTxs
+}
+
+// newSchedulingPolicy creates a new scheduling policy from the given configuration.
+func newSchedulingPolicy(cfg *config.Config) (schedulingPolicy, error) {
+	if err := cfg.ValidateScheduling(); err != nil {
+		return nil, fmt.Errorf("txpool: %w", err)
+	}
+
+	switch cfg.SchedulingPolicy {
+	case config.SchedulingPolicyFairShare:
+		return fairSharePolicy{}, nil
+	case config.SchedulingPolicySenderCap:
+		return senderCapPolicy{maxTxs: int(cfg.MaxSenderTxsPerBatch)}, nil
+	default:
+		return priorityPolicy{}, nil
+	}
+}
@@ -0,0 +1,61 @@
+package txpool
+
+import (
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+// Status is the transaction pool status.
+type Status struct {
+	// CheckQueueSize is the number of transactions waiting to be checked.
+	CheckQueueSize int `json:"check_queue_size"`
+	// MainQueueSize is the number of checked transactions in the main queue.
+	MainQueueSize int `json:"main_queue_size"`
+	// RimQueueSize is the number of transactions from roothash incoming messages.
+	RimQueueSize int `json:"rim_queue_size"`
+	// ProposedSize is the number of transactions in the current proposal.
+	ProposedSize int `json:"proposed_size"`
+
+	// Senders is the sequence number state of each sender with transactions in the main queue.
+	Senders []*SenderStatus `json:"senders,omitempty"`
+	// Transactions are the transactions in the main queue.
+	Transactions []*TransactionStatus `json:"transactions,omitempty"`
+}
+
+// SenderStatus is the sequence number state of a transaction sender.
+type SenderStatus struct {
+	// Sender is the sender identifier as specified by the runtime.
+	Sender []byte `json:"sender"`
+	// StateSeq is the sender's sequence number as of the latest checked state.
+	StateSeq uint64 `json:"state_seq"`
+	// Transactions is the number of the sender's transactions in the main queue.
+	Transactions int `json:"transactions"`
+	// Schedulable is the number of the sender's transactions that have consecutive sequence
+	// numbers starting at the state sequence number and can therefore be scheduled.
+	Schedulable int `json:"schedulable"`
+	// MissingSeq is the first missing sequence number in case some of the sender's transactions
+	// cannot be scheduled because of a sequence number gap.
+	MissingSeq *uint64 `json:"missing_seq,omitempty"`
+}
+
+// TransactionStatus is the status of a transaction in the main queue.
+type TransactionStatus struct {
+	// Hash is the transaction hash.
+	Hash hash.Hash `json:"hash"`
+	// Size is the size of the raw transaction (bytes).
+	Size int `json:"size"`
+	// FirstSeen is the time the transaction was first seen.
+	FirstSeen time.Time `json:"first_seen"`
+	// Local indicates whether the transaction was submitted by our own node.
+	Local bool `json:"local,omitempty"`
+	// Rechecks is the number of times the transaction has been rechecked.
+	Rechecks uint64 `json:"rechecks"`
+
+	// Sender is the sender identifier as specified by the runtime.
+	Sender []byte `json:"sender"`
+	// Seq is the sender's sequence number as specified by the runtime.
+	Seq uint64 `json:"seq"`
+	// Priority is the transaction priority as specified by the runtime.
+	Priority uint64 `json:"priority"`
+}
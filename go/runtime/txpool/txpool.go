@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/eapache/channels"
@@ -44,6 +46,16 @@ const (
 	republishLimitReinvokeTimeout = time.Second
 )
 
+var (
+	// ErrEvictionDisabled is the error returned when transaction eviction has not been enabled
+	// in the transaction pool configuration.
+	ErrEvictionDisabled = errors.New("txpool: transaction eviction is disabled")
+
+	// ErrNoSuchTransaction is the error returned when the transaction to evict is not in the
+	// main queue.
+	ErrNoSuchTransaction = errors.New("txpool: no such transaction")
+)
+
 // TransactionPool is an interface for managing a pool of transactions.
 type TransactionPool interface {
 	// Start starts the service.
@@ -118,6 +130,20 @@ type TransactionPool interface {
 	// WatchCheckedTransactions subscribes to notifications about new transactions being available
 	// in the transaction pool for scheduling.
 	WatchCheckedTransactions() (<-chan []*PendingCheckTransaction, pubsub.ClosableSubscription)
+
+	// WatchTxEvents subscribes to notifications about transactions being queued, scheduled and
+	// removed from the transaction pool.
+	WatchTxEvents() (<-chan []*TxEvent, pubsub.ClosableSubscription)
+
+	// GetStatus returns the transaction pool status.
+	GetStatus() *Status
+
+	// EvictTx removes the transaction with the given hash from the main queue.
+	//
+	// Eviction must be explicitly enabled in the configuration, otherwise ErrEvictionDisabled is
+	// returned. The transaction remains in the already seen cache so it is not immediately
+	// resubmitted.
+	EvictTx(hash hash.Hash) error
 }
 
 // TransactionPublisher is an interface representing a mechanism for publishing transactions.
@@ -151,6 +177,7 @@ type txPool struct {
 	checkTxCh       *channels.RingChannel
 	checkTxQueue    *checkTxQueue
 	checkTxNotifier *pubsub.Broker
+	txEventNotifier *pubsub.Broker
 	recheckTxCh     *channels.RingChannel
 
 	usableSources []UsableTransactionSource
@@ -166,12 +193,17 @@ type txPool struct {
 	lastRecheckRound          uint64
 
 	republishCh *channels.RingChannel
+
+	journal *journal
 }
 
 func (t *txPool) Start() error {
 	go t.checkWorker()
 	go t.republishWorker()
 	go t.recheckWorker()
+	if t.journal != nil {
+		go t.journalWorker()
+	}
 	return nil
 }
 
@@ -228,10 +260,19 @@ func (t *txPool) SubmitTxNoWait(tx []byte, local bool) error {
 }
 
 func (t *txPool) submitTx(tx []byte, local bool, discard bool, wait bool) (*PendingCheckTransaction, error) {
+	meta := &TxQueueMeta{
+		raw:       tx,
+		hash:      hash.NewFromBytes(tx),
+		firstSeen: time.Now(),
+		local:     local,
+	}
+	return t.submitTxMeta(meta, discard, wait)
+}
+
+func (t *txPool) submitTxMeta(meta *TxQueueMeta, discard bool, wait bool) (*PendingCheckTransaction, error) {
 	// Skip recently seen transactions.
-	hash := hash.NewFromBytes(tx)
-	if _, seen := t.seenCache.Peek(hash); seen {
-		t.logger.Debug("ignoring already seen transaction", "hash", hash)
+	if _, seen := t.seenCache.Peek(meta.Hash()); seen {
+		t.logger.Debug("ignoring already seen transaction", "hash", meta.Hash())
 		return nil, fmt.Errorf("duplicate transaction")
 	}
 
@@ -241,15 +282,9 @@ func (t *txPool) submitTx(tx []byte, local bool, discard bool, wait bool) (*Pend
 		notifyCh = make(chan *protocol.CheckTxResult, 1)
 	}
 
-	meta := &TxQueueMeta{
-		raw:       tx,
-		hash:      hash,
-		firstSeen: time.Now(),
-	}
-
 	pct := &PendingCheckTransaction{
 		TxQueueMeta: meta,
-		local:       local,
+		local:       meta.local,
 		discard:     discard,
 		notifyCh:    notifyCh,
 	}
@@ -330,11 +365,41 @@ func (t *txPool) ClearProposedBatch() {
 }
 
 func (t *txPool) GetSchedulingSuggestion(limit int) []*TxQueueMeta {
-	return t.mainQueue.Schedule(limit)
+	txs := t.mainQueue.Schedule(limit)
+	t.publishScheduled(txs)
+	return txs
 }
 
 func (t *txPool) GetSchedulingExtra(offset *hash.Hash, limit int) []*TxQueueMeta {
-	return t.mainQueue.ScheduleExtra(limit)
+	txs := t.mainQueue.ScheduleExtra(limit)
+	t.publishScheduled(txs)
+	return txs
+}
+
+// publishScheduled emits scheduled events for the given transactions. Transactions are scheduled
+// for the round following the latest known runtime block.
+//
+// Scheduling suggestions are only requested on nodes acting as the transaction scheduler, so no
+// scheduled events are emitted on other nodes (e.g., client nodes).
+func (t *txPool) publishScheduled(txs []*TxQueueMeta) {
+	if len(txs) == 0 {
+		return
+	}
+
+	var round uint64
+	if di, _, err := t.getCurrentDispatchInfo(); err == nil {
+		round = di.BlockInfo.RuntimeBlock.Header.Round + 1
+	}
+
+	events := make([]*TxEvent, 0, len(txs))
+	for _, tx := range txs {
+		events = append(events, &TxEvent{
+			Kind:  TxEventScheduled,
+			Hash:  tx.Hash(),
+			Round: round,
+		})
+	}
+	t.publishTxEvents(events)
 }
 
 func (t *txPool) RejectTxs(hashes []hash.Hash) {
@@ -345,6 +410,16 @@ func (t *txPool) RejectTxs(hashes []hash.Hash) {
 	}
 
 	t.HandleTxsUsed(hashes)
+
+	events := make([]*TxEvent, 0, len(hashes))
+	for _, h := range hashes {
+		events = append(events, &TxEvent{
+			Kind:   TxEventEvicted,
+			Hash:   h,
+			Reason: reasonRejected,
+		})
+	}
+	t.publishTxEvents(events)
 }
 
 func (t *txPool) HandleTxsUsed(hashes []hash.Hash) {
@@ -434,6 +509,51 @@ func (t *txPool) WatchCheckedTransactions() (<-chan []*PendingCheckTransaction,
 	return ch, sub
 }
 
+func (t *txPool) WatchTxEvents() (<-chan []*TxEvent, pubsub.ClosableSubscription) {
+	sub := t.txEventNotifier.Subscribe()
+	ch := make(chan []*TxEvent)
+	sub.Unwrap(ch)
+	return ch, sub
+}
+
+func (t *txPool) GetStatus() *Status {
+	senders, txs := t.mainQueue.Status()
+
+	t.proposedTxsLock.Lock()
+	proposedSize := len(t.proposedTxs)
+	t.proposedTxsLock.Unlock()
+
+	return &Status{
+		CheckQueueSize: t.checkTxQueue.size(),
+		MainQueueSize:  len(txs),
+		RimQueueSize:   t.rimQueue.size(),
+		ProposedSize:   proposedSize,
+		Senders:        senders,
+		Transactions:   txs,
+	}
+}
+
+func (t *txPool) EvictTx(hash hash.Hash) error {
+	if !t.cfg.AllowEviction {
+		return ErrEvictionDisabled
+	}
+	if !t.mainQueue.Evict(hash) {
+		return ErrNoSuchTransaction
+	}
+
+	t.logger.Info("evicted transaction",
+		"hash", hash,
+	)
+	t.publishTxEvents([]*TxEvent{{
+		Kind:   TxEventEvicted,
+		Hash:   hash,
+		Reason: reasonEvictedByOperator,
+	}})
+	mainQueueSize.With(t.getMetricLabels()).Set(float64(t.mainQueue.Size()))
+
+	return nil
+}
+
 func (t *txPool) All() [][]byte {
 	var txs [][]byte
 	for _, q := range t.usableSources {
@@ -536,6 +656,11 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 		pct.notifyCh <- &results[i]
 	}
 
+	var events []*TxEvent
+	defer func() {
+		t.publishTxEvents(events)
+	}()
+
 	newTxs := make([]*PendingCheckTransaction, 0, len(results))
 	goodPcts := make([]*PendingCheckTransaction, 0, len(results))
 	batchIndices := make([]int, 0, len(results))
@@ -553,6 +678,12 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 			// become valid in the future.
 			t.seenCache.Remove(batch[i].Hash())
 
+			events = append(events, &TxEvent{
+				Kind:   TxEventEvicted,
+				Hash:   batch[i].Hash(),
+				Reason: fmt.Sprintf("%s: %s", reasonCheckFailed, res.Error.String()),
+			})
+
 			// We won't be sending this tx on to its destination queue.
 			notifySubmitter(i)
 			continue
@@ -616,12 +747,29 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 				Message: err.Error(),
 			}
 			notifySubmitter(idx)
+
+			kind := TxEventEvicted
+			if errors.Is(err, errTxExpired) {
+				kind = TxEventExpired
+			}
+			events = append(events, &TxEvent{
+				Kind:   kind,
+				Hash:   pct.Hash(),
+				Reason: err.Error(),
+			})
 			continue
 		}
 
 		// Notify submitter of success.
 		notifySubmitter(idx)
 
+		if !pct.checked {
+			events = append(events, &TxEvent{
+				Kind: TxEventQueued,
+				Hash: pct.Hash(),
+			})
+		}
+
 		if !pct.checked {
 			// Mark new transactions as never having been published. The republish worker will
 			// publish these immediately.
@@ -635,6 +783,8 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 			// Put cannot fail as seenCache's LRU capacity is not in bytes and the only case where it
 			// can error is if the capacity is in bytes and the value size is over capacity.
 			_ = t.seenCache.Put(pct.Hash(), publishTime)
+
+			t.journalTx(pct.TxQueueMeta)
 		}
 	}
 
@@ -845,6 +995,8 @@ func (t *txPool) recheck() {
 	var pcts []*PendingCheckTransaction
 	var results []chan *protocol.CheckTxResult
 	for _, tx := range t.mainQueue.Drain() {
+		atomic.AddUint64(&tx.rechecks, 1)
+
 		notifyCh := make(chan *protocol.CheckTxResult, 1)
 		pcts = append(pcts, &PendingCheckTransaction{
 			TxQueueMeta: tx,
@@ -882,16 +1034,125 @@ func (t *txPool) recheck() {
 	}
 }
 
+// journalTx appends the given newly accepted transaction to the journal, if enabled.
+func (t *txPool) journalTx(tx *TxQueueMeta) {
+	if t.journal == nil || !(tx.local || t.cfg.JournalMainQueue) {
+		return
+	}
+	err := t.journal.insert(newJournalEntry(tx))
+	switch {
+	case err == nil:
+	case errors.Is(err, errJournalFull):
+		journalOverflows.With(t.getMetricLabels()).Inc()
+	default:
+		t.logger.Warn("failed to journal transaction",
+			"err", err,
+			"hash", tx.Hash(),
+		)
+	}
+}
+
+// journalEntries returns the journal entries for all journaled transactions currently in the
+// main queue and locally submitted or replayed transactions still pending checks, with locally
+// submitted transactions first.
+func (t *txPool) journalEntries(replayed map[hash.Hash]*journalEntry) []*journalEntry {
+	var local, remote []*journalEntry
+	for _, pct := range t.checkTxQueue.all() {
+		if pct.local {
+			local = append(local, newJournalEntry(pct.TxQueueMeta))
+			continue
+		}
+		if entry, ok := replayed[pct.Hash()]; ok {
+			remote = append(remote, entry)
+		}
+	}
+	for _, tx := range t.mainQueue.All() {
+		switch {
+		case tx.local:
+			local = append(local, newJournalEntry(tx))
+		case t.cfg.JournalMainQueue:
+			remote = append(remote, newJournalEntry(tx))
+		}
+	}
+	return append(local, remote...)
+}
+
+func (t *txPool) rotateJournal(snapshot func() []*journalEntry) {
+	if err := t.journal.rotate(snapshot, time.Now()); err != nil {
+		t.logger.Error("failed to rotate transaction journal",
+			"err", err,
+		)
+	}
+}
+
+func (t *txPool) journalWorker() {
+	defer t.journal.close()
+
+	// Replay journaled transactions. These are queued for checks which will start once the
+	// transaction pool is initialized.
+	entries, err := t.journal.load(time.Now())
+	if err != nil {
+		t.logger.Error("failed to load transaction journal",
+			"err", err,
+		)
+	}
+	replayed := make(map[hash.Hash]*journalEntry)
+	for _, entry := range entries {
+		meta := &TxQueueMeta{
+			raw:       entry.Tx,
+			hash:      hash.NewFromBytes(entry.Tx),
+			firstSeen: entry.firstSeen(),
+			local:     entry.Local,
+		}
+		if _, err = t.submitTxMeta(meta, false, false); err != nil {
+			continue
+		}
+		replayed[meta.Hash()] = entry
+	}
+	t.logger.Info("replayed journaled transactions",
+		"num_txs", len(replayed),
+		"num_journaled", len(entries),
+	)
+
+	// Keep the replayed transactions in the journal until they have been checked, together with
+	// any transactions submitted in the meantime, while discarding any expired entries.
+	snapshot := func() []*journalEntry {
+		return t.journalEntries(replayed)
+	}
+	t.rotateJournal(snapshot)
+
+	ticker := time.NewTicker(journalRotateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.stopCh:
+			t.rotateJournal(snapshot)
+			return
+		case <-ticker.C:
+			t.rotateJournal(snapshot)
+		}
+	}
+}
+
 // New creates a new transaction pool instance.
+//
+// In case the transaction journal is enabled, it is stored in the given data directory.
 func New(
 	runtimeID common.Namespace,
+	dataDir string,
 	cfg config.Config,
 	runtime host.Runtime,
 	history history.History,
 	txPublisher TransactionPublisher,
-) TransactionPool {
+) (TransactionPool, error) {
 	initMetrics()
 
+	policy, err := newSchedulingPolicy(&cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	seenCache := lru.New(lru.Capacity(cfg.MaxLastSeenCacheSize, false))
 
 	// The transaction check queue should be 10% larger than the transaction pool to allow for some
@@ -899,9 +1160,14 @@ func New(
 	maxCheckTxQueueSize := int((110 * cfg.MaxPoolSize) / 100)
 
 	rq := newRimQueue()
-	mq := newMainQueue(int(cfg.MaxPoolSize))
+	mq := newMainQueue(int(cfg.MaxPoolSize), policy)
 
-	return &txPool{
+	var jr *journal
+	if cfg.JournalEnabled {
+		jr = newJournal(filepath.Join(dataDir, journalFilename), int(cfg.JournalMaxSize), cfg.JournalExpiry)
+	}
+
+	t := &txPool{
 		logger:          logging.GetLogger("runtime/txpool"),
 		stopCh:          make(chan struct{}),
 		quitCh:          make(chan struct{}),
@@ -915,11 +1181,18 @@ func New(
 		checkTxQueue:    newCheckTxQueue(maxCheckTxQueueSize, int(cfg.MaxCheckTxBatchSize)),
 		checkTxCh:       channels.NewRingChannel(1),
 		checkTxNotifier: pubsub.NewBroker(false),
+		txEventNotifier: pubsub.NewBroker(false),
 		recheckTxCh:     channels.NewRingChannel(1),
 		usableSources:   []UsableTransactionSource{rq, mq},
 		rimQueue:        rq,
 		mainQueue:       mq,
 		proposedTxs:     make(map[hash.Hash]*TxQueueMeta),
 		republishCh:     channels.NewRingChannel(1),
+		journal:         jr,
 	}
+	mq.SetEvictHandler(func(ev *TxEvent) {
+		t.publishTxEvents([]*TxEvent{ev})
+	})
+
+	return t, nil
 }
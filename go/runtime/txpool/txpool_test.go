@@ -0,0 +1,48 @@
+package txpool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
+)
+
+func TestEvictTx(t *testing.T) {
+	require := require.New(t)
+
+	raw := []byte("transaction")
+	tx := &TxQueueMeta{
+		raw:       raw,
+		hash:      hash.NewFromBytes(raw),
+		firstSeen: time.Now(),
+	}
+
+	mainQueue := newMainQueue(10, priorityPolicy{})
+	err := mainQueue.Add(tx, &protocol.CheckTxMetadata{Sender: []byte("sender")})
+	require.NoError(err, "Add")
+
+	pool := &txPool{
+		logger:          logging.GetLogger("runtime/txpool/test"),
+		mainQueue:       mainQueue,
+		txEventNotifier: pubsub.NewBroker(false),
+	}
+
+	// Eviction should be refused unless explicitly enabled.
+	err = pool.EvictTx(tx.hash)
+	require.ErrorIs(err, ErrEvictionDisabled)
+	require.Equal(1, mainQueue.Size())
+
+	pool.cfg = config.Config{AllowEviction: true}
+	err = pool.EvictTx(tx.hash)
+	require.NoError(err, "EvictTx")
+	require.Equal(0, mainQueue.Size())
+
+	err = pool.EvictTx(tx.hash)
+	require.ErrorIs(err, ErrNoSuchTransaction)
+}
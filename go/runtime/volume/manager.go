@@ -5,15 +5,18 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	cmSync "github.com/oasisprotocol/oasis-core/go/common/sync"
+	"github.com/oasisprotocol/oasis-core/go/config"
 )
 
 const (
@@ -21,8 +24,19 @@ const (
 	descriptorFn = "descriptor.json"
 	// volumeFn is the filename of the volume file.
 	volumeFn = "volume"
+	// quotaCheckInterval is the interval at which volume quotas are checked.
+	quotaCheckInterval = time.Minute
 )
 
+// ErrQuotaExceeded is the error returned when an operation would exceed the volume quota.
+var ErrQuotaExceeded = errors.New("volume quota exceeded")
+
+// ReferenceProvider provides information about which volumes are in use.
+type ReferenceProvider interface {
+	// ReferencedVolumes returns the identifiers of all volumes that are currently referenced.
+	ReferencedVolumes() map[string]struct{}
+}
+
 // Manager is a volume manager.
 type Manager struct {
 	mu       sync.Mutex
@@ -32,20 +46,51 @@ type Manager struct {
 	volumesDir string
 
 	volumes map[string]*Volume
+	refs    ReferenceProvider
+
+	gcInterval    time.Duration
+	gcGracePeriod time.Duration
+	// unreferencedSince tracks when the garbage collector first observed a volume unreferenced.
+	unreferencedSince map[string]time.Time
+
+	maxVolumeSize  uint64
+	maxRuntimeSize uint64
+	maxSnapshots   uint64
 
 	logger *logging.Logger
 }
 
 // NewManager creates a new volume manager.
-func NewManager(dataDir string) (*Manager, error) {
+//
+// The reference provider is used to determine which volumes are no longer referenced and can be
+// garbage collected.
+func NewManager(dataDir string, refs ReferenceProvider) (*Manager, error) {
 	logger := logging.GetLogger("runtime/volume/manager")
 
-	return &Manager{
-		startOne:   cmSync.NewOne(),
-		volumesDir: GetVolumesDir(dataDir),
-		volumes:    make(map[string]*Volume),
-		logger:     logger,
-	}, nil
+	cfg := config.GlobalConfig.Runtime.Volumes
+	m := &Manager{
+		startOne:          cmSync.NewOne(),
+		volumesDir:        GetVolumesDir(dataDir),
+		volumes:           make(map[string]*Volume),
+		refs:              refs,
+		gcInterval:        cfg.GCInterval,
+		gcGracePeriod:     cfg.GCGracePeriod,
+		unreferencedSince: make(map[string]time.Time),
+		maxSnapshots:      cfg.MaxSnapshots,
+		logger:            logger,
+	}
+	if size := cfg.MaxVolumeSize; size != "" {
+		if m.maxVolumeSize = uint64(config.ParseSizeInBytes(size)); m.maxVolumeSize == 0 {
+			return nil, fmt.Errorf("malformed maximum volume size: %s", size)
+		}
+	}
+	if size := cfg.MaxRuntimeSize; size != "" {
+		if m.maxRuntimeSize = uint64(config.ParseSizeInBytes(size)); m.maxRuntimeSize == 0 {
+			return nil, fmt.Errorf("malformed maximum runtime volume size: %s", size)
+		}
+	}
+
+	return m, nil
 }
 
 // Start starts the volume manager.
@@ -68,15 +113,136 @@ func (m *Manager) run(ctx context.Context) {
 		return
 	}
 
+	var gcCh, quotaCh <-chan time.Time
+	if m.gcInterval > 0 && m.refs != nil {
+		ticker := time.NewTicker(m.gcInterval)
+		defer ticker.Stop()
+		gcCh = ticker.C
+	}
+	if m.hasQuotas() {
+		ticker := time.NewTicker(quotaCheckInterval)
+		defer ticker.Stop()
+		quotaCh = ticker.C
+	}
+
 	// Start the main task responsible for managing volumes.
-	for { //nolint: gosimple
+	for {
 		select {
 		case <-ctx.Done():
 			m.logger.Info("stopping")
 			return
+		case <-gcCh:
+			m.collectGarbage(time.Now())
+		case <-quotaCh:
+			m.checkQuotas()
 		}
+	}
+}
 
-		// TODO: Periodically cleanup unreferenced volumes.
+// collectGarbage removes volumes that have been unreferenced for at least the grace period.
+//
+// Only volumes automatically created for bundles are collected. Volumes created explicitly (e.g.,
+// via the ROFL volume management API) are always considered referenced as they are removed by
+// their owners.
+func (m *Manager) collectGarbage(now time.Time) {
+	refs := m.refs.ReferencedVolumes()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id := range m.unreferencedSince {
+		if _, ok := m.volumes[id]; !ok {
+			delete(m.unreferencedSince, id)
+		}
+	}
+
+	for id, volume := range m.volumes {
+		if _, ok := refs[id]; ok || volume.Labels[LabelAutoGenerated] != "true" {
+			delete(m.unreferencedSince, id)
+			continue
+		}
+
+		since, ok := m.unreferencedSince[id]
+		if !ok {
+			m.unreferencedSince[id] = now
+			continue
+		}
+		if now.Sub(since) < m.gcGracePeriod {
+			continue
+		}
+
+		if err := m.removeVolumeLocked(volume); err != nil {
+			m.logger.Error("failed to remove unreferenced volume",
+				"id", id,
+				"err", err,
+			)
+			continue
+		}
+		delete(m.unreferencedSince, id)
+
+		m.logger.Info("removed unreferenced volume",
+			"id", id,
+			"labels", volume.Labels,
+			"unreferenced_since", since,
+		)
+	}
+}
+
+func (m *Manager) hasQuotas() bool {
+	return m.maxVolumeSize > 0 || m.maxRuntimeSize > 0
+}
+
+// checkQuotas marks volumes exceeding their quota or belonging to a runtime whose volumes exceed
+// their quota, so that they are refused when used. Marks are cleared once usage drops below the
+// quotas again.
+func (m *Manager) checkQuotas() {
+	if !m.hasQuotas() {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	usage := make(map[string]uint64)
+	runtimeUsage := make(map[string]uint64)
+	for id, volume := range m.volumes {
+		size, err := m.usageLocked(volume)
+		if err != nil {
+			m.logger.Warn("failed to determine volume size",
+				"id", id,
+				"err", err,
+			)
+			continue
+		}
+		usage[id] = size
+		if runtimeID, ok := volume.Labels[LabelRuntimeID]; ok {
+			runtimeUsage[runtimeID] += size
+		}
+	}
+
+	for id, size := range usage {
+		volume := m.volumes[id]
+		runtimeID := volume.Labels[LabelRuntimeID]
+
+		exceeded := m.maxVolumeSize > 0 && size > m.maxVolumeSize
+		exceeded = exceeded || (m.maxRuntimeSize > 0 && runtimeUsage[runtimeID] > m.maxRuntimeSize)
+
+		switch prev := volume.quotaExceeded.Swap(exceeded); {
+		case exceeded && !prev:
+			m.logger.Warn("volume exceeds its quota, refusing to use it",
+				"id", id,
+				"runtime_id", runtimeID,
+				"size", size,
+				"runtime_size", runtimeUsage[runtimeID],
+				"quota", m.maxVolumeSize,
+				"runtime_quota", m.maxRuntimeSize,
+			)
+		case !exceeded && prev:
+			m.logger.Info("volume no longer exceeds its quota",
+				"id", id,
+				"size", size,
+			)
+		}
 	}
 }
 
@@ -113,6 +279,9 @@ func (m *Manager) loadAndRegisterVolumes() error {
 
 	m.logger.Info("volumes loaded and registered")
 
+	// Make sure volumes exceeding their quotas are refused right away.
+	m.checkQuotas()
+
 	return nil
 }
 
@@ -205,6 +374,10 @@ func (m *Manager) Create(labels map[string]string) (*Volume, error) {
 }
 
 func (m *Manager) createVolumeLocked(labels map[string]string) (*Volume, error) {
+	if err := m.ensureRuntimeQuotaLocked(labels[LabelRuntimeID], 0); err != nil {
+		return nil, err
+	}
+
 	volume, err := m.createVolumeDir(labels)
 	if err != nil {
 		return nil, err
@@ -291,6 +464,87 @@ func (m *Manager) removeVolumeLocked(volume *Volume) error {
 	return nil
 }
 
+// Usage returns the disk space used by the volume with the specified identifier, including its
+// snapshots.
+func (m *Manager) Usage(id string) (uint64, error) {
+	if err := m.ensureInitialized(); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	volume, ok := m.volumes[id]
+	if !ok {
+		return 0, fmt.Errorf("volume '%s' not found", id)
+	}
+	return m.usageLocked(volume)
+}
+
+func (m *Manager) usageLocked(volume *Volume) (uint64, error) {
+	var total uint64
+	for _, path := range []string{
+		volume.Path,
+		filepath.Join(m.volumesDir, volume.ID, snapshotsDir),
+	} {
+		size, err := diskUsage(path)
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return total, nil
+}
+
+// ensureRuntimeQuotaLocked ensures that adding the given number of bytes to the volumes of the
+// given runtime would not exceed the per-runtime quota.
+func (m *Manager) ensureRuntimeQuotaLocked(runtimeID string, extra uint64) error {
+	if m.maxRuntimeSize == 0 || runtimeID == "" {
+		return nil
+	}
+
+	var total uint64
+	for _, volume := range m.volumes {
+		if volume.Labels[LabelRuntimeID] != runtimeID {
+			continue
+		}
+		size, err := m.usageLocked(volume)
+		if err != nil {
+			return err
+		}
+		total += size
+	}
+	if total+extra > m.maxRuntimeSize || (extra == 0 && total >= m.maxRuntimeSize) {
+		return fmt.Errorf("%w: runtime volumes use %d of %d bytes", ErrQuotaExceeded, total, m.maxRuntimeSize)
+	}
+	return nil
+}
+
+// diskUsage returns the total size of all regular files under the given path. A path that does
+// not exist uses no space.
+func diskUsage(path string) (uint64, error) {
+	var total uint64
+	err := filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		switch {
+		case err == nil:
+		case errors.Is(err, os.ErrNotExist):
+			return nil
+		default:
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += uint64(info.Size())
+		return nil
+	})
+	return total, err
+}
+
 // Get retrieves the volume with the specified identifier.
 //
 // If the volume cannot be found, it returns nil
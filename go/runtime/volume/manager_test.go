@@ -0,0 +1,175 @@
+package volume
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockReferenceProvider struct {
+	refs map[string]struct{}
+}
+
+func (p *mockReferenceProvider) ReferencedVolumes() map[string]struct{} {
+	return p.refs
+}
+
+func TestGarbageCollection(t *testing.T) {
+	require := require.New(t)
+
+	refs := &mockReferenceProvider{refs: make(map[string]struct{})}
+	m, err := NewManager(t.TempDir(), refs)
+	require.NoError(err)
+	m.gcGracePeriod = time.Hour
+
+	referenced, err := m.Create(map[string]string{LabelAutoGenerated: "true", "name": "referenced"})
+	require.NoError(err)
+	unreferenced, err := m.Create(map[string]string{LabelAutoGenerated: "true", "name": "unreferenced"})
+	require.NoError(err)
+	explicit, err := m.Create(map[string]string{"name": "explicit"})
+	require.NoError(err)
+	refs.refs[referenced.ID] = struct{}{}
+
+	// The first pass should only mark the volume as unreferenced.
+	now := time.Now()
+	m.collectGarbage(now)
+	_, ok := m.Get(unreferenced.ID)
+	require.True(ok, "unreferenced volume should be kept during the grace period")
+
+	// Volumes that become referenced again should not be removed.
+	refs.refs[unreferenced.ID] = struct{}{}
+	m.collectGarbage(now.Add(2 * time.Hour))
+	_, ok = m.Get(unreferenced.ID)
+	require.True(ok, "referenced volume should be kept")
+
+	delete(refs.refs, unreferenced.ID)
+	m.collectGarbage(now.Add(3 * time.Hour))
+	m.collectGarbage(now.Add(3*time.Hour + 30*time.Minute))
+	_, ok = m.Get(unreferenced.ID)
+	require.True(ok, "unreferenced volume should be kept during the grace period")
+
+	m.collectGarbage(now.Add(4 * time.Hour))
+	_, ok = m.Get(unreferenced.ID)
+	require.False(ok, "unreferenced volume should be removed after the grace period")
+	_, err = os.Stat(unreferenced.Path)
+	require.ErrorIs(err, os.ErrNotExist)
+
+	_, ok = m.Get(referenced.ID)
+	require.True(ok, "referenced volume should be kept")
+	_, ok = m.Get(explicit.ID)
+	require.True(ok, "explicitly created volume should be kept")
+}
+
+func TestSnapshots(t *testing.T) {
+	require := require.New(t)
+
+	m, err := NewManager(t.TempDir(), nil)
+	require.NoError(err)
+	m.maxSnapshots = 2
+
+	volume, err := m.Create(nil)
+	require.NoError(err)
+
+	// Snapshot an uninitialized volume.
+	err = m.CreateSnapshot(volume.ID, "empty")
+	require.NoError(err)
+
+	err = os.WriteFile(volume.Path, []byte("hello world"), 0o600)
+	require.NoError(err)
+	err = m.CreateSnapshot(volume.ID, "hello")
+	require.NoError(err)
+
+	err = m.CreateSnapshot(volume.ID, "hello")
+	require.Error(err, "duplicate snapshot names should be rejected")
+	err = m.CreateSnapshot(volume.ID, "../escape")
+	require.Error(err, "malformed snapshot names should be rejected")
+	err = m.CreateSnapshot(volume.ID, "another")
+	require.ErrorIs(err, ErrQuotaExceeded)
+
+	snapshots, err := m.Snapshots(volume.ID)
+	require.NoError(err)
+	require.Len(snapshots, 2)
+
+	usage, err := m.Usage(volume.ID)
+	require.NoError(err)
+	require.EqualValues(2*len("hello world"), usage)
+
+	err = os.WriteFile(volume.Path, []byte("goodbye"), 0o600)
+	require.NoError(err)
+	err = m.RestoreSnapshot(volume.ID, "hello")
+	require.NoError(err)
+	data, err := os.ReadFile(volume.Path)
+	require.NoError(err)
+	require.Equal("hello world", string(data))
+
+	err = m.RestoreSnapshot(volume.ID, "empty")
+	require.NoError(err)
+	_, err = os.Stat(volume.Path)
+	require.ErrorIs(err, os.ErrNotExist)
+
+	err = m.RestoreSnapshot(volume.ID, "missing")
+	require.Error(err)
+
+	err = m.RemoveSnapshot(volume.ID, "empty")
+	require.NoError(err)
+	err = m.RemoveSnapshot(volume.ID, "empty")
+	require.Error(err)
+	err = m.CreateSnapshot(volume.ID, "another")
+	require.NoError(err)
+}
+
+func TestQuotas(t *testing.T) {
+	require := require.New(t)
+
+	m, err := NewManager(t.TempDir(), nil)
+	require.NoError(err)
+	m.maxSnapshots = 4
+	m.maxVolumeSize = 20
+	m.maxRuntimeSize = 30
+
+	labels := map[string]string{LabelRuntimeID: "runtime"}
+	volume, err := m.Create(labels)
+	require.NoError(err)
+
+	err = os.WriteFile(volume.Path, []byte("0123456789"), 0o600)
+	require.NoError(err)
+	err = m.CreateSnapshot(volume.ID, "first")
+	require.NoError(err)
+	err = m.CreateSnapshot(volume.ID, "second")
+	require.ErrorIs(err, ErrQuotaExceeded, "volume quota should be enforced")
+
+	other, err := m.Create(labels)
+	require.NoError(err)
+	err = os.WriteFile(other.Path, []byte("0123456789"), 0o600)
+	require.NoError(err)
+
+	_, err = m.Create(labels)
+	require.ErrorIs(err, ErrQuotaExceeded, "runtime quota should be enforced")
+	err = m.CreateSnapshot(other.ID, "first")
+	require.ErrorIs(err, ErrQuotaExceeded, "runtime quota should be enforced")
+
+	// Volumes of other runtimes should not be affected.
+	unrelated, err := m.Create(map[string]string{LabelRuntimeID: "other"})
+	require.NoError(err)
+
+	// Volumes exceeding their quotas should be refused.
+	m.checkQuotas()
+	require.NoError(volume.CheckQuota())
+	require.NoError(other.CheckQuota())
+	require.NoError(unrelated.CheckQuota())
+
+	err = os.WriteFile(other.Path, []byte("01234567890123456789"), 0o600)
+	require.NoError(err)
+	m.checkQuotas()
+	require.ErrorIs(volume.CheckQuota(), ErrQuotaExceeded, "runtime quota should be enforced")
+	require.ErrorIs(other.CheckQuota(), ErrQuotaExceeded, "volume quota should be enforced")
+	require.NoError(unrelated.CheckQuota())
+
+	err = os.WriteFile(other.Path, []byte("0123456789"), 0o600)
+	require.NoError(err)
+	m.checkQuotas()
+	require.NoError(volume.CheckQuota())
+	require.NoError(other.CheckQuota())
+}
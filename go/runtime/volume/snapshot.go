@@ -0,0 +1,248 @@
+package volume
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// snapshotsDir is the name of the directory located inside the volume directory which contains
+// the volume snapshots.
+const snapshotsDir = "snapshots"
+
+// snapshotNameRegexp is the regular expression for valid snapshot names.
+var snapshotNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-][a-zA-Z0-9_.-]{0,63}$`)
+
+// Snapshot is a point-in-time copy of a volume.
+type Snapshot struct {
+	// Name is the name of the snapshot.
+	Name string
+	// CreatedAt is the time when the snapshot was created.
+	CreatedAt time.Time
+	// Size is the size of the snapshot in bytes.
+	Size uint64
+}
+
+// CreateSnapshot creates a new snapshot of the volume with the specified identifier.
+//
+// To obtain a consistent snapshot, the volume should not be in use by any component.
+func (m *Manager) CreateSnapshot(id string, name string) error {
+	if err := m.ensureInitialized(); err != nil {
+		return err
+	}
+	if !snapshotNameRegexp.MatchString(name) {
+		return fmt.Errorf("malformed snapshot name '%s'", name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	volume, ok := m.volumes[id]
+	if !ok {
+		return fmt.Errorf("volume '%s' not found", id)
+	}
+
+	snapshots, err := m.snapshotsLocked(volume)
+	if err != nil {
+		return err
+	}
+	if uint64(len(snapshots)) >= m.maxSnapshots {
+		return fmt.Errorf("%w: at most %d snapshots are allowed", ErrQuotaExceeded, m.maxSnapshots)
+	}
+	snapshotPath := m.snapshotPath(volume, name)
+	if _, err = os.Lstat(snapshotPath); err == nil {
+		return fmt.Errorf("snapshot '%s' already exists", name)
+	}
+
+	// Ensure the snapshot fits into the quotas.
+	var size uint64
+	switch fi, err := os.Stat(volume.Path); {
+	case err == nil:
+		size = uint64(fi.Size())
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		return fmt.Errorf("failed to stat volume: %w", err)
+	}
+	if m.maxVolumeSize > 0 {
+		usage, err := m.usageLocked(volume)
+		if err != nil {
+			return err
+		}
+		if usage+size > m.maxVolumeSize {
+			return fmt.Errorf("%w: volume uses %d of %d bytes", ErrQuotaExceeded, usage, m.maxVolumeSize)
+		}
+	}
+	if err = m.ensureRuntimeQuotaLocked(volume.Labels[LabelRuntimeID], size); err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(snapshotPath), 0o700); err != nil {
+		return fmt.Errorf("failed to create snapshots directory: %w", err)
+	}
+	if err = copyFile(volume.Path, snapshotPath); err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	m.logger.Info("volume snapshot created",
+		"id", volume.ID,
+		"snapshot", name,
+		"size", size,
+	)
+
+	return nil
+}
+
+// RestoreSnapshot replaces the contents of the volume with the specified identifier with the
+// contents of the given snapshot.
+//
+// The volume must not be in use by any component while it is being restored.
+func (m *Manager) RestoreSnapshot(id string, name string) error {
+	if err := m.ensureInitialized(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	volume, ok := m.volumes[id]
+	if !ok {
+		return fmt.Errorf("volume '%s' not found", id)
+	}
+	if !snapshotNameRegexp.MatchString(name) {
+		return fmt.Errorf("snapshot '%s' not found", name)
+	}
+	snapshotPath := m.snapshotPath(volume, name)
+	fi, err := os.Lstat(snapshotPath)
+	if err != nil {
+		return fmt.Errorf("snapshot '%s' not found", name)
+	}
+
+	switch fi.Size() {
+	case 0:
+		// Snapshot of a volume that has not been initialized yet.
+		if err = os.Remove(volume.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to restore snapshot: %w", err)
+		}
+	default:
+		if err = copyFile(snapshotPath, volume.Path); err != nil {
+			return fmt.Errorf("failed to restore snapshot: %w", err)
+		}
+	}
+
+	m.logger.Info("volume snapshot restored",
+		"id", volume.ID,
+		"snapshot", name,
+	)
+
+	return nil
+}
+
+// RemoveSnapshot removes the given snapshot of the volume with the specified identifier.
+func (m *Manager) RemoveSnapshot(id string, name string) error {
+	if err := m.ensureInitialized(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	volume, ok := m.volumes[id]
+	if !ok {
+		return fmt.Errorf("volume '%s' not found", id)
+	}
+	if !snapshotNameRegexp.MatchString(name) {
+		return fmt.Errorf("snapshot '%s' not found", name)
+	}
+
+	switch err := os.Remove(m.snapshotPath(volume, name)); {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("snapshot '%s' not found", name)
+	default:
+		return fmt.Errorf("failed to remove snapshot: %w", err)
+	}
+	return nil
+}
+
+// Snapshots returns all snapshots of the volume with the specified identifier.
+func (m *Manager) Snapshots(id string) ([]*Snapshot, error) {
+	if err := m.ensureInitialized(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	volume, ok := m.volumes[id]
+	if !ok {
+		return nil, fmt.Errorf("volume '%s' not found", id)
+	}
+	return m.snapshotsLocked(volume)
+}
+
+func (m *Manager) snapshotsLocked(volume *Volume) ([]*Snapshot, error) {
+	entries, err := os.ReadDir(filepath.Join(m.volumesDir, volume.ID, snapshotsDir))
+	switch {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to read snapshots directory: %w", err)
+	}
+
+	var snapshots []*Snapshot
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !snapshotNameRegexp.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat snapshot: %w", err)
+		}
+
+		snapshots = append(snapshots, &Snapshot{
+			Name:      entry.Name(),
+			CreatedAt: info.ModTime(),
+			Size:      uint64(info.Size()),
+		})
+	}
+	return snapshots, nil
+}
+
+func (m *Manager) snapshotPath(volume *Volume, name string) string {
+	return filepath.Join(m.volumesDir, volume.ID, snapshotsDir, name)
+}
+
+// copyFile atomically replaces the destination file with a copy of the source file. In case the
+// source file does not exist, an empty destination file is created.
+func copyFile(src, dst string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	switch in, err := os.Open(src); {
+	case err == nil:
+		defer in.Close()
+		if _, err = io.Copy(tmp, in); err != nil {
+			return err
+		}
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		return err
+	}
+
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
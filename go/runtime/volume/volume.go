@@ -1,5 +1,10 @@
 package volume
 
+import (
+	"fmt"
+	"sync/atomic"
+)
+
 // Volume is a persistent volume.
 type Volume struct {
 	// ID is the unique volume identifier.
@@ -8,6 +13,20 @@ type Volume struct {
 	Path string
 	// Labels are the labels assigned to the volume.
 	Labels map[string]string
+
+	// quotaExceeded is set by the volume manager in case the volume or the volumes of its runtime
+	// exceed their quota.
+	quotaExceeded atomic.Bool
+}
+
+// CheckQuota returns ErrQuotaExceeded in case the volume or the volumes of its runtime exceeded
+// their quota as of the last quota check. Such volumes must not be used until enough space is
+// freed or the quota is raised.
+func (v *Volume) CheckQuota() error {
+	if v.quotaExceeded.Load() {
+		return fmt.Errorf("%w: volume '%s'", ErrQuotaExceeded, v.ID)
+	}
+	return nil
 }
 
 // HasLabels returns true iff the volume has all of the given labels set.
@@ -0,0 +1,322 @@
+package checkpoint
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+const (
+	// archiveVersion is the current checkpoint archive format version.
+	archiveVersion = 1
+
+	archiveManifestName = "manifest"
+
+	// maxArchiveManifestSize is the maximum size of the archive manifest.
+	maxArchiveManifestSize = 16 * 1024 * 1024
+	// maxArchiveChunkSize is the maximum size of a single chunk in an archive.
+	maxArchiveChunkSize = 256 * 1024 * 1024
+)
+
+// ArchiveManifest is the manifest of a checkpoint archive.
+//
+// A checkpoint archive is an uncompressed tar stream as chunks are already compressed. The first
+// entry is the CBOR-encoded manifest, followed by all chunks of all checkpoints listed in the
+// manifest, in order.
+type ArchiveManifest struct {
+	// Version is the archive format version.
+	Version uint16 `json:"version"`
+
+	// Checkpoints are the metadata of all checkpoints contained in the archive.
+	Checkpoints []*Metadata `json:"checkpoints"`
+}
+
+// Validate checks that the archive manifest is structurally valid.
+func (m *ArchiveManifest) Validate() error {
+	if m.Version != archiveVersion {
+		return fmt.Errorf("unsupported archive version %d", m.Version)
+	}
+	if len(m.Checkpoints) == 0 {
+		return fmt.Errorf("no checkpoints")
+	}
+	for i, cp := range m.Checkpoints {
+		if err := cp.Validate(); err != nil {
+			return fmt.Errorf("invalid checkpoint %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Roots returns the roots of all checkpoints contained in the archive.
+func (m *ArchiveManifest) Roots() []node.Root {
+	roots := make([]node.Root, 0, len(m.Checkpoints))
+	for _, cp := range m.Checkpoints {
+		roots = append(roots, cp.Root)
+	}
+	return roots
+}
+
+func archiveChunkName(cpIdx int, chunkIdx int) string {
+	return strconv.Itoa(cpIdx) + "/" + strconv.Itoa(chunkIdx)
+}
+
+// ExportArchive writes the given checkpoints, fetching their chunks from the given provider,
+// into a single checkpoint archive.
+func ExportArchive(ctx context.Context, provider ChunkProvider, cps []*Metadata, w io.Writer) error {
+	manifest := &ArchiveManifest{
+		Version:     archiveVersion,
+		Checkpoints: cps,
+	}
+	if err := manifest.Validate(); err != nil {
+		return fmt.Errorf("checkpoint: invalid archive manifest: %w", err)
+	}
+
+	tw := tar.NewWriter(w)
+	writeHeader := func(name string, size int64) error {
+		return tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0o600,
+			Size:     size,
+		})
+	}
+
+	data := cbor.Marshal(manifest)
+	if err := writeHeader(archiveManifestName, int64(len(data))); err != nil {
+		return fmt.Errorf("checkpoint: failed to write archive manifest: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("checkpoint: failed to write archive manifest: %w", err)
+	}
+
+	for i, cp := range cps {
+		for idx := range cp.Chunks {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			cm, err := cp.GetChunkMetadata(uint64(idx))
+			if err != nil {
+				return err
+			}
+
+			// Determine the chunk size first so that the chunk can be streamed into the archive
+			// without buffering it in memory.
+			var sw sizeWriter
+			if err = provider.GetCheckpointChunk(ctx, cm, &sw); err != nil {
+				return fmt.Errorf("checkpoint: failed to get chunk %d of checkpoint %s: %w", idx, cp.Root, err)
+			}
+			if err = writeHeader(archiveChunkName(i, idx), sw.size); err != nil {
+				return fmt.Errorf("checkpoint: failed to write archive chunk: %w", err)
+			}
+			if err = provider.GetCheckpointChunk(ctx, cm, tw); err != nil {
+				return fmt.Errorf("checkpoint: failed to write chunk %d of checkpoint %s: %w", idx, cp.Root, err)
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("checkpoint: failed to finish archive: %w", err)
+	}
+	return nil
+}
+
+// sizeWriter is a writer that discards everything written to it, keeping track of the size.
+type sizeWriter struct {
+	size int64
+}
+
+func (w *sizeWriter) Write(p []byte) (int, error) {
+	w.size += int64(len(p))
+	return len(p), nil
+}
+
+// ReadArchive reads a checkpoint archive, verifying that it is complete and that all chunks match
+// the digests in the manifest.
+//
+// The optional onManifest callback is invoked once the manifest has been read and the optional
+// onChunk callback is invoked for each chunk, in order. Chunks are streamed to the callback, so
+// the chunk digest is only verified after the callback returns and the callback itself must
+// verify chunk integrity before relying on the chunk contents.
+func ReadArchive(
+	ctx context.Context,
+	r io.Reader,
+	onManifest func(*ArchiveManifest) error,
+	onChunk func(cpIdx int, chunkIdx uint64, chunk io.Reader) error,
+) (*ArchiveManifest, error) {
+	tr := tar.NewReader(r)
+
+	nextEntry := func(name string, maxSize int64) (*tar.Header, error) {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("checkpoint: failed to read archive entry %s: %w", name, err)
+		}
+		if hdr.Name != name {
+			return nil, fmt.Errorf("checkpoint: unexpected archive entry (expected: %s got: %s)", name, hdr.Name)
+		}
+		if hdr.Size > maxSize {
+			return nil, fmt.Errorf("checkpoint: archive entry %s too large", name)
+		}
+		return hdr, nil
+	}
+
+	hdr, err := nextEntry(archiveManifestName, maxArchiveManifestSize)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(tr, hdr.Size))
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to read archive manifest: %w", err)
+	}
+	var manifest ArchiveManifest
+	if err = cbor.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("checkpoint: corrupted archive manifest: %w", err)
+	}
+	if err = manifest.Validate(); err != nil {
+		return nil, fmt.Errorf("checkpoint: invalid archive manifest: %w", err)
+	}
+	if onManifest != nil {
+		if err = onManifest(&manifest); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, cp := range manifest.Checkpoints {
+		for idx, digest := range cp.Chunks {
+			if err = ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			name := archiveChunkName(i, idx)
+			if _, err = nextEntry(name, maxArchiveChunkSize); err != nil {
+				return nil, err
+			}
+
+			hb := hash.NewBuilder()
+			cr := io.TeeReader(tr, hb)
+			if onChunk != nil {
+				if err = onChunk(i, uint64(idx), cr); err != nil {
+					return nil, err
+				}
+			}
+			// Make sure the whole chunk has been read in order to verify its digest.
+			if _, err = io.Copy(io.Discard, cr); err != nil {
+				return nil, fmt.Errorf("checkpoint: failed to read archive chunk %s: %w", name, err)
+			}
+			if h := hb.Build(); !h.Equal(&digest) {
+				return nil, fmt.Errorf("checkpoint: archive chunk %s: %w", name, ErrChunkCorrupted)
+			}
+		}
+	}
+
+	switch _, err = tr.Next(); err {
+	case io.EOF:
+	case nil:
+		return nil, fmt.Errorf("checkpoint: unexpected trailing archive entries")
+	default:
+		return nil, fmt.Errorf("checkpoint: failed to read archive: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+// VerifyArchive verifies a checkpoint archive without restoring it.
+func VerifyArchive(ctx context.Context, r io.Reader) (*ArchiveManifest, error) {
+	return ReadArchive(ctx, r, nil, nil)
+}
+
+// ImportArchive restores all checkpoints contained in a checkpoint archive into the given node
+// database and finalizes the restored roots.
+//
+// Since the archive manifest is not authenticated, the archive must contain full checkpoints for
+// exactly the given expected roots, which the caller must obtain from a trusted source. The node
+// database must be empty.
+func ImportArchive(ctx context.Context, ndb db.NodeDB, expectedRoots []node.Root, r io.Reader) (*ArchiveManifest, error) {
+	if len(expectedRoots) == 0 {
+		return nil, fmt.Errorf("checkpoint: no expected roots")
+	}
+	for _, root := range expectedRoots {
+		if root.Version != expectedRoots[0].Version {
+			return nil, fmt.Errorf("checkpoint: expected roots for multiple versions")
+		}
+	}
+	if latest, ok := ndb.GetLatestVersion(); ok {
+		return nil, fmt.Errorf("checkpoint: node database is not empty (latest version: %d)", latest)
+	}
+
+	rs, err := NewRestorer(ndb)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		manifest *ArchiveManifest
+		started  bool
+	)
+	defer func() {
+		if started {
+			_ = rs.AbortRestore(ctx)
+			_ = ndb.AbortMultipartInsert()
+		}
+	}()
+
+	onManifest := func(m *ArchiveManifest) error {
+		expected := make(map[node.Root]struct{}, len(expectedRoots))
+		for _, root := range expectedRoots {
+			expected[root] = struct{}{}
+		}
+		for _, cp := range m.Checkpoints {
+			if cp.IsDelta() {
+				return fmt.Errorf("checkpoint: archive contains delta checkpoint for root %s", cp.Root)
+			}
+			if _, ok := expected[cp.Root]; !ok {
+				return fmt.Errorf("checkpoint: archive contains unexpected root %s", cp.Root)
+			}
+			delete(expected, cp.Root)
+		}
+		if len(expected) > 0 {
+			return fmt.Errorf("checkpoint: archive is missing %d expected root(s)", len(expected))
+		}
+		if err := ndb.StartMultipartInsert(m.Checkpoints[0].Root.Version); err != nil {
+			return fmt.Errorf("checkpoint: failed to start multipart insert: %w", err)
+		}
+		manifest = m
+		started = true
+		return nil
+	}
+
+	onChunk := func(cpIdx int, chunkIdx uint64, chunk io.Reader) error {
+		if chunkIdx == 0 {
+			if err := rs.StartRestore(ctx, manifest.Checkpoints[cpIdx]); err != nil {
+				return fmt.Errorf("checkpoint: failed to start restore of checkpoint %d: %w", cpIdx, err)
+			}
+		}
+		// The restorer verifies chunk integrity before applying the chunk.
+		if _, err := rs.RestoreChunk(ctx, chunkIdx, chunk); err != nil {
+			return fmt.Errorf("checkpoint: failed to restore chunk %d of checkpoint %d: %w", chunkIdx, cpIdx, err)
+		}
+		return nil
+	}
+
+	if _, err = ReadArchive(ctx, r, onManifest, onChunk); err != nil {
+		return nil, err
+	}
+
+	if err = ndb.Finalize(manifest.Roots()); err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to finalize restored roots: %w", err)
+	}
+	started = false
+
+	return manifest, nil
+}
@@ -0,0 +1,191 @@
+package checkpoint
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db"
+	dbApi "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	dbTesting "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/testing"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+func TestArchive(t *testing.T) {
+	dbTesting.TestMultipleBackends(t, db.Backends, testArchive)
+}
+
+func testArchive(t *testing.T, factory dbApi.Factory) {
+	require := require.New(t)
+	ctx := t.Context()
+	dir := t.TempDir()
+
+	ndb, err := factory.New(&dbApi.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	// Generate some data for both root types.
+	var roots []node.Root
+	for _, rootType := range []node.RootType{node.RootTypeState, node.RootTypeIO} {
+		tree := mkvs.New(nil, ndb, rootType)
+		for i := 0; i < 1000; i++ {
+			err = tree.Insert(ctx, []byte(strconv.Itoa(i)), []byte(rootType.String()))
+			require.NoError(err, "Insert")
+		}
+		_, rootHash, err := tree.Commit(ctx, testNs, 1)
+		require.NoError(err, "Commit")
+		tree.Close()
+
+		roots = append(roots, node.Root{
+			Namespace: testNs,
+			Version:   1,
+			Type:      rootType,
+			Hash:      rootHash,
+		})
+	}
+	err = ndb.Finalize(roots)
+	require.NoError(err, "Finalize")
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+
+	var cps []*Metadata
+	for _, root := range roots {
+		cp, err := fc.CreateCheckpoint(ctx, root, 1024, 0)
+		require.NoError(err, "CreateCheckpoint")
+		cps = append(cps, cp)
+	}
+
+	// Export the checkpoints into an archive.
+	var archive bytes.Buffer
+	err = ExportArchive(ctx, fc, cps, &archive)
+	require.NoError(err, "ExportArchive")
+
+	// Chunks should be stored as-is in an uncompressed tar stream.
+	tr := tar.NewReader(bytes.NewReader(archive.Bytes()))
+	hdr, err := tr.Next()
+	require.NoError(err, "tar.Next")
+	require.Equal(archiveManifestName, hdr.Name)
+	hdr, err = tr.Next()
+	require.NoError(err, "tar.Next")
+	require.Equal(archiveChunkName(0, 0), hdr.Name)
+	var chunk bytes.Buffer
+	_, err = io.Copy(&chunk, tr)
+	require.NoError(err, "Copy")
+	require.Equal(cps[0].Chunks[0], hash.NewFromBytes(chunk.Bytes()), "chunk should be stored as-is")
+
+	err = ExportArchive(ctx, fc, nil, &bytes.Buffer{})
+	require.Error(err, "ExportArchive should fail without checkpoints")
+
+	// Verify the archive.
+	manifest, err := VerifyArchive(ctx, bytes.NewReader(archive.Bytes()))
+	require.NoError(err, "VerifyArchive")
+	require.Equal(cps, manifest.Checkpoints, "archive should contain all checkpoints")
+	require.Equal(roots, manifest.Roots())
+
+	// Verification of a truncated archive should fail.
+	_, err = VerifyArchive(ctx, bytes.NewReader(archive.Bytes()[:archive.Len()/2]))
+	require.Error(err, "VerifyArchive should fail for a truncated archive")
+
+	// Verification of an archive with a corrupted chunk should fail.
+	var corrupted bytes.Buffer
+	err = ExportArchive(ctx, &corruptingProvider{ChunkProvider: fc}, cps, &corrupted)
+	require.NoError(err, "ExportArchive")
+	_, err = VerifyArchive(ctx, &corrupted)
+	require.Error(err, "VerifyArchive should fail for a corrupted chunk")
+	require.True(errors.Is(err, ErrChunkCorrupted))
+
+	// Importing into a node database for a different namespace should fail.
+	otherNs := common.NewTestNamespaceFromSeed([]byte("oasis mkvs checkpoint test other ns"), 0)
+	ndb2, err := factory.New(&dbApi.Config{
+		DB:           filepath.Join(dir, "db2"),
+		Namespace:    otherNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	defer ndb2.Close()
+
+	otherRoots := make([]node.Root, 0, len(roots))
+	for _, root := range roots {
+		root.Namespace = otherNs
+		otherRoots = append(otherRoots, root)
+	}
+	_, err = ImportArchive(ctx, ndb2, otherRoots, bytes.NewReader(archive.Bytes()))
+	require.ErrorContains(err, "unexpected root", "ImportArchive should fail for a different namespace")
+
+	// Import the archive into a fresh node database.
+	ndb3, err := factory.New(&dbApi.Config{
+		DB:           filepath.Join(dir, "db3"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	defer ndb3.Close()
+
+	// Importing should fail in case the roots don't match the expected roots.
+	_, err = ImportArchive(ctx, ndb3, nil, bytes.NewReader(archive.Bytes()))
+	require.ErrorContains(err, "no expected roots")
+
+	_, err = ImportArchive(ctx, ndb3, roots[:1], bytes.NewReader(archive.Bytes()))
+	require.ErrorContains(err, "unexpected root")
+
+	wrongRoots := append([]node.Root{}, roots...)
+	wrongRoots[0].Hash.FromBytes([]byte("wrong root"))
+	_, err = ImportArchive(ctx, ndb3, wrongRoots, bytes.NewReader(archive.Bytes()))
+	require.ErrorContains(err, "unexpected root")
+
+	extraRoots := append([]node.Root{}, roots...)
+	extraRoots = append(extraRoots, wrongRoots[0])
+	_, err = ImportArchive(ctx, ndb3, extraRoots, bytes.NewReader(archive.Bytes()))
+	require.ErrorContains(err, "missing 1 expected root(s)")
+
+	_, ok := ndb3.GetLatestVersion()
+	require.False(ok, "failed imports should not finalize any roots")
+
+	manifest, err = ImportArchive(ctx, ndb3, roots, bytes.NewReader(archive.Bytes()))
+	require.NoError(err, "ImportArchive")
+	require.Equal(cps, manifest.Checkpoints)
+
+	for _, root := range roots {
+		require.True(ndb3.HasRoot(root), "imported root should exist")
+		err = ensureEqualEntries(ctx, ndb, ndb3, root)
+		require.NoError(err, "ensureEqualEntries")
+	}
+	latest, ok := ndb3.GetLatestVersion()
+	require.True(ok, "GetLatestVersion")
+	require.EqualValues(1, latest, "imported version should be finalized")
+
+	// Importing into a non-empty node database should fail.
+	_, err = ImportArchive(ctx, ndb3, roots, bytes.NewReader(archive.Bytes()))
+	require.ErrorContains(err, "node database is not empty")
+}
+
+// corruptingProvider is a chunk provider that corrupts every chunk.
+type corruptingProvider struct {
+	ChunkProvider
+}
+
+func (cp *corruptingProvider) GetCheckpointChunk(ctx context.Context, chunk *ChunkMetadata, w io.Writer) error {
+	var buf bytes.Buffer
+	if err := cp.ChunkProvider.GetCheckpointChunk(ctx, chunk, &buf); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+	data[len(data)-1] ^= 0xff
+	_, err := w.Write(data)
+	return err
+}
@@ -12,6 +12,17 @@ import (
 
 const moduleName = "storage/mkvs/checkpoint"
 
+// Supported checkpoint versions. Versions only differ in the codec used to compress chunks.
+const (
+	// VersionSnappy is the checkpoint version using snappy-compressed chunks.
+	VersionSnappy uint16 = v1
+	// VersionZstd is the checkpoint version using zstd-compressed chunks.
+	VersionZstd uint16 = v2
+)
+
+// SupportedVersions are all supported checkpoint versions, most preferred first.
+var SupportedVersions = []uint16{VersionZstd, VersionSnappy}
+
 var (
 	// ErrCheckpointNotFound is the error when a checkpoint is not found.
 	ErrCheckpointNotFound = errors.New(moduleName, 1, "checkpoint: not found")
@@ -34,6 +45,9 @@ var (
 
 	// ErrChunkCorrupted is the error when a chunk is corrupted.
 	ErrChunkCorrupted = errors.New(moduleName, 7, "chunk: corrupted chunk")
+
+	// ErrBaseRootNotFound is the error when the base root of a delta checkpoint is not available.
+	ErrBaseRootNotFound = errors.New(moduleName, 8, "checkpoint: base root not found")
 )
 
 // ChunkProvider is a chunk provider.
@@ -53,6 +67,34 @@ type GetCheckpointsRequest struct {
 	// RootVersion specifies an optional root version to limit the request to. If specified, only
 	// checkpoints for roots with the specific version will be considered.
 	RootVersion *uint64 `json:"root_version,omitempty"`
+
+	// IncludeDeltas specifies whether delta checkpoints should be included in the response.
+	IncludeDeltas bool `json:"include_deltas,omitempty"`
+}
+
+// CreateOption is an option for checkpoint creation.
+type CreateOption func(*createOptions)
+
+type createOptions struct {
+	version uint16
+}
+
+func newCreateOptions(opts []CreateOption) *createOptions {
+	o := &createOptions{
+		version: VersionSnappy,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithVersion sets the version of the created checkpoint. The checkpoint version determines the
+// codec used to compress chunks.
+func WithVersion(version uint16) CreateOption {
+	return func(o *createOptions) {
+		o.version = version
+	}
 }
 
 // Creator is a checkpoint creator.
@@ -64,21 +106,36 @@ type Creator interface {
 	// If 0 chunker threads are specified the old (deprecated) sequential algorithm is used.
 	// Otherwise, chunks are created in parallel up to target chunker threads.
 	//
+	// Unless specified otherwise via WithVersion, a VersionSnappy checkpoint is created.
+	//
 	// Warning: Only with same chunk size and chunker threads the generated checkpoint hash for the given root
 	//          will be the same.
-	CreateCheckpoint(ctx context.Context, root node.Root, chunkSize uint64, chunkerThreads uint16) (*Metadata, error)
+	CreateCheckpoint(ctx context.Context, root node.Root, chunkSize uint64, chunkerThreads uint16, opts ...CreateOption) (*Metadata, error)
+
+	// CreateDeltaCheckpoint creates a new delta checkpoint at the given root, containing only the
+	// nodes that are new since the given base root. Both roots must be present in the underlying
+	// node database.
+	//
+	// Unless specified otherwise via WithVersion, a VersionSnappy checkpoint is created.
+	CreateDeltaCheckpoint(ctx context.Context, root node.Root, baseRoot node.Root, chunkSize uint64, opts ...CreateOption) (*Metadata, error)
 
 	// GetCheckpoint retrieves checkpoint metadata for a specific checkpoint.
 	GetCheckpoint(ctx context.Context, version uint16, root node.Root) (*Metadata, error)
 
 	// DeleteCheckpoint deletes a specific checkpoint.
 	DeleteCheckpoint(ctx context.Context, version uint16, root node.Root) error
+
+	// DeleteDeltaCheckpoint deletes a specific delta checkpoint.
+	DeleteDeltaCheckpoint(ctx context.Context, version uint16, root node.Root, baseRoot node.Root) error
 }
 
 // Restorer is a checkpoint restorer.
 type Restorer interface {
 	// StartRestore starts a checkpoint restoration process.
 	//
+	// In case of a delta checkpoint, its base root must already be present in the underlying node
+	// database.
+	//
 	// Multipart management in the underlying database is the responsibility of the caller.
 	StartRestore(ctx context.Context, checkpoint *Metadata) error
 
@@ -316,6 +316,250 @@ func testOversizedChunks(t *testing.T, factory dbApi.Factory) {
 	require.Len(cp.Chunks, 100, "there should be the correct number of chunks")
 }
 
+func TestCompressedCheckpoint(t *testing.T) {
+	dbTesting.TestMultipleBackends(t, db.Backends, testCompressedCheckpoint)
+}
+
+func testCompressedCheckpoint(t *testing.T, factory dbApi.Factory) {
+	require := require.New(t)
+	ctx := t.Context()
+	dir := t.TempDir()
+
+	ndb, err := factory.New(&dbApi.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	tree := mkvs.New(nil, ndb, node.RootTypeState)
+	for i := 0; i < 1000; i++ {
+		err = tree.Insert(ctx, []byte(strconv.Itoa(i)), []byte(fmt.Sprintf("some highly compressible value %d", i)))
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, testNs, 1)
+	require.NoError(err, "Commit")
+	tree.Close()
+	root := node.Root{
+		Namespace: testNs,
+		Version:   1,
+		Type:      node.RootTypeState,
+		Hash:      rootHash,
+	}
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+
+	_, err = fc.CreateCheckpoint(ctx, root, 1024, 0, WithVersion(42))
+	require.Error(err, "CreateCheckpoint should fail for unsupported versions")
+
+	cp, err := fc.CreateCheckpoint(ctx, root, 1024, 0, WithVersion(VersionZstd))
+	require.NoError(err, "CreateCheckpoint")
+	require.EqualValues(VersionZstd, cp.Version, "version should be correct")
+
+	// Checkpoints should only be returned for the requested version.
+	cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: VersionZstd})
+	require.NoError(err, "GetCheckpoints")
+	require.Equal([]*Metadata{cp}, cps)
+	cps, err = fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: VersionSnappy})
+	require.NoError(err, "GetCheckpoints")
+	require.Empty(cps, "there should be no snappy checkpoints")
+	_, err = fc.GetCheckpoint(ctx, VersionSnappy, root)
+	require.Error(err, "GetCheckpoint should fail for a different version")
+	err = fc.DeleteCheckpoint(ctx, VersionSnappy, root)
+	require.Error(err, "DeleteCheckpoint should fail for a different version")
+
+	// Checkpoints of different versions for the same root should not conflict.
+	snappyCp, err := fc.CreateCheckpoint(ctx, root, 1024, 0)
+	require.NoError(err, "CreateCheckpoint")
+	require.EqualValues(VersionSnappy, snappyCp.Version, "version should be correct")
+	cps, err = fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: VersionZstd})
+	require.NoError(err, "GetCheckpoints")
+	require.Equal([]*Metadata{cp}, cps)
+	err = fc.DeleteCheckpoint(ctx, VersionSnappy, root)
+	require.NoError(err, "DeleteCheckpoint")
+	zstdCp, err := fc.GetCheckpoint(ctx, VersionZstd, root)
+	require.NoError(err, "GetCheckpoint")
+	require.Equal(cp, zstdCp)
+
+	// Restoring a checkpoint with an unsupported version should fail.
+	ndb2, err := factory.New(&dbApi.Config{
+		DB:           filepath.Join(dir, "db2"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	defer ndb2.Close()
+
+	rs, err := NewRestorer(ndb2)
+	require.NoError(err, "NewRestorer")
+
+	unsupportedCp := *cp
+	unsupportedCp.Version = 42
+	err = rs.StartRestore(ctx, &unsupportedCp)
+	require.Error(err, "StartRestore should fail for unsupported versions")
+
+	// Restore the compressed checkpoint.
+	err = ndb2.StartMultipartInsert(root.Version)
+	require.NoError(err, "StartMultipartInsert")
+	err = rs.StartRestore(ctx, cp)
+	require.NoError(err, "StartRestore")
+	for i := range cp.Chunks {
+		var cm *ChunkMetadata
+		cm, err = cp.GetChunkMetadata(uint64(i))
+		require.NoError(err, "GetChunkMetadata")
+
+		var buf bytes.Buffer
+		err = fc.GetCheckpointChunk(ctx, cm, &buf)
+		require.NoError(err, "GetCheckpointChunk")
+
+		var done bool
+		done, err = rs.RestoreChunk(ctx, uint64(i), &buf)
+		require.NoError(err, "RestoreChunk")
+		require.Equal(i == len(cp.Chunks)-1, done, "RestoreChunk should signal completion after the last chunk")
+	}
+	err = ndb2.Finalize([]node.Root{root})
+	require.NoError(err, "Finalize")
+
+	err = ensureEqualEntries(ctx, ndb, ndb2, root)
+	require.NoError(err, "ensureEqualEntries")
+
+	err = fc.DeleteCheckpoint(ctx, VersionZstd, root)
+	require.NoError(err, "DeleteCheckpoint")
+}
+
+func TestDeltaCheckpoint(t *testing.T) {
+	dbTesting.TestMultipleBackends(t, db.Backends, testDeltaCheckpoint)
+}
+
+func testDeltaCheckpoint(t *testing.T, factory dbApi.Factory) {
+	require := require.New(t)
+
+	dir, err := os.MkdirTemp("", "mkvs.checkpoint")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := factory.New(&dbApi.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+
+	// Generate some data for the base version.
+	ctx := context.Background()
+	tree := mkvs.New(nil, ndb, node.RootTypeState)
+	for i := 0; i < 1000; i++ {
+		err = tree.Insert(ctx, []byte(strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, testNs, 1)
+	require.NoError(err, "Commit")
+	baseRoot := node.Root{
+		Namespace: testNs,
+		Version:   1,
+		Type:      node.RootTypeState,
+		Hash:      rootHash,
+	}
+	err = ndb.Finalize([]node.Root{baseRoot})
+	require.NoError(err, "Finalize")
+
+	// Modify some of the data in the next version.
+	for i := 0; i < 1000; i += 50 {
+		err = tree.Insert(ctx, []byte(strconv.Itoa(i)), []byte("updated"))
+		require.NoError(err, "Insert")
+		err = tree.Remove(ctx, []byte(strconv.Itoa(i+1)))
+		require.NoError(err, "Remove")
+		err = tree.Insert(ctx, []byte(fmt.Sprintf("new %d", i)), []byte("new"))
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err = tree.Commit(ctx, testNs, 2)
+	require.NoError(err, "Commit")
+	root := node.Root{
+		Namespace: testNs,
+		Version:   2,
+		Type:      node.RootTypeState,
+		Hash:      rootHash,
+	}
+	err = ndb.Finalize([]node.Root{root})
+	require.NoError(err, "Finalize")
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+
+	baseCp, err := fc.CreateCheckpoint(ctx, baseRoot, 1024, 0)
+	require.NoError(err, "CreateCheckpoint")
+	fullCp, err := fc.CreateCheckpoint(ctx, root, 1024, 0)
+	require.NoError(err, "CreateCheckpoint")
+
+	// Creating a delta checkpoint against a later version should fail.
+	_, err = fc.CreateDeltaCheckpoint(ctx, baseRoot, root, 1024)
+	require.Error(err, "CreateDeltaCheckpoint should fail for a later base root")
+
+	// Create a delta checkpoint and check that it has been created correctly.
+	cp, err := fc.CreateDeltaCheckpoint(ctx, root, baseRoot, 1024)
+	require.NoError(err, "CreateDeltaCheckpoint")
+	require.NoError(cp.Validate(), "delta checkpoint metadata should be valid")
+	require.True(cp.IsDelta(), "checkpoint should be a delta checkpoint")
+	require.EqualValues(root, cp.Root, "checkpoint root should be correct")
+	require.EqualValues(&baseRoot, cp.BaseRoot, "checkpoint base root should be correct")
+	require.Less(len(cp.Chunks), len(fullCp.Chunks), "delta checkpoint should be smaller")
+
+	// Re-creating the same delta checkpoint should return the same metadata.
+	existingCp, err := fc.CreateDeltaCheckpoint(ctx, root, baseRoot, 1024)
+	require.NoError(err, "CreateDeltaCheckpoint on an existing root should work")
+	require.Equal(cp, existingCp, "created checkpoint should be correct")
+
+	// Delta checkpoints should only be listed when requested.
+	cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: 1})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(cps, 2, "there should be two full checkpoints")
+	cps, err = fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: 1, IncludeDeltas: true})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(cps, 3, "there should be three checkpoints including deltas")
+
+	chain, err := DeltaChain(cps, root)
+	require.NoError(err, "DeltaChain")
+	require.Equal([]*Metadata{fullCp}, chain, "shortest chain should use the full checkpoint")
+
+	// Restoring a delta checkpoint without the base root should fail.
+	ndb2, err := factory.New(&dbApi.Config{
+		DB:           filepath.Join(dir, "db2"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+
+	rs, err := NewRestorer(ndb2)
+	require.NoError(err, "NewRestorer")
+	err = rs.StartRestore(ctx, cp)
+	require.Error(err, "StartRestore should fail without the base root")
+	require.True(errors.Is(err, ErrBaseRootNotFound))
+
+	// Restore the base checkpoint followed by the delta checkpoint.
+	err = restoreCheckpoint(ctx, ndb2, baseCp, fc, baseRoot)
+	require.NoError(err, "restoreCheckpoint(base)")
+	err = restoreCheckpoint(ctx, ndb2, cp, fc, root)
+	require.NoError(err, "restoreCheckpoint(delta)")
+
+	err = ensureEqualEntries(ctx, ndb, ndb2, root)
+	require.NoError(err, "ensureEqualEntries")
+
+	// Deleting the delta checkpoint should work and leave no empty directories.
+	err = fc.DeleteDeltaCheckpoint(ctx, 1, root, baseRoot)
+	require.NoError(err, "DeleteDeltaCheckpoint")
+	err = fc.DeleteDeltaCheckpoint(ctx, 1, root, baseRoot)
+	require.Error(err, "DeleteDeltaCheckpoint on a non-existent checkpoint should fail")
+
+	_, err = os.Stat(filepath.Join(dir, "checkpoints", deltasDirname, strconv.FormatUint(root.Version, 10)))
+	require.True(os.IsNotExist(err), "there should be no empty directories after deletion")
+
+	cps, err = fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: 1, IncludeDeltas: true})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(cps, 2, "there should be no delta checkpoints")
+}
+
 func TestPruneGapAfterCheckpointRestore(t *testing.T) {
 	dbTesting.TestMultipleBackends(t, db.Backends, testPruneGapAfterCheckpointRestore)
 }
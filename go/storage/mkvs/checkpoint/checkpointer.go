@@ -70,6 +70,22 @@ type CreationParameters struct {
 	// used during checkpoint creation. The actual number may vary at runtime.
 	// Setting it to 0, will use old sequential chunking algorithm.
 	ChunkerThreads uint16
+
+	// Version is the version of created checkpoints, which determines the codec used to compress
+	// chunks. Setting it to 0 will use version 1 (snappy).
+	Version uint16
+
+	// Deltas specifies whether a delta checkpoint against the previous checkpoint should be
+	// created in addition to each full checkpoint.
+	Deltas bool
+}
+
+// checkpointVersion returns the version of created checkpoints.
+func (p *CreationParameters) checkpointVersion() uint16 {
+	if p.Version == 0 {
+		return v1
+	}
+	return p.Version
 }
 
 // Checkpointer is responsible for creating the storage snapshots (checkpoints).
@@ -118,6 +134,8 @@ type checkpointer struct {
 // NewCheckpointer creates a new checkpointer that can be notified of new finalized versions and
 // will automatically generate the configured number of checkpoints.
 func NewCheckpointer(ndb db.NodeDB, creator Creator, cfg CheckpointerConfig) Checkpointer {
+	initMetrics()
+
 	return &checkpointer{
 		cfg:        cfg,
 		ndb:        ndb,
@@ -280,6 +298,8 @@ func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *C
 		)
 	}
 
+	cpVersion := params.checkpointVersion()
+
 	defer func() {
 		if err == nil {
 			return
@@ -287,7 +307,7 @@ func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *C
 
 		// If there is an error, make sure to remove any created checkpoints.
 		for _, root := range roots {
-			_ = c.creator.DeleteCheckpoint(ctx, v1, root)
+			_ = c.creator.DeleteCheckpoint(ctx, cpVersion, root)
 		}
 	}()
 
@@ -302,7 +322,7 @@ func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *C
 			"chunk_size", params.ChunkSize,
 		)
 
-		_, err = c.creator.CreateCheckpoint(ctx, root, params.ChunkSize, params.ChunkerThreads)
+		_, err = c.creator.CreateCheckpoint(ctx, root, params.ChunkSize, params.ChunkerThreads, WithVersion(cpVersion))
 		if err != nil {
 			c.logger.Error("failed to create checkpoint",
 				"root", root,
@@ -311,15 +331,76 @@ func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *C
 			return fmt.Errorf("checkpointer: failed to create checkpoint: %w", err)
 		}
 	}
+
+	if params.Deltas {
+		c.createDeltaCheckpoints(ctx, roots, params)
+	}
 	return nil
 }
 
+// createDeltaCheckpoints creates delta checkpoints for the given roots, based on the roots of the
+// latest earlier full checkpoint.
+//
+// Failing to create a delta checkpoint is not fatal as full checkpoints are always available.
+func (c *checkpointer) createDeltaCheckpoints(ctx context.Context, roots []node.Root, params *CreationParameters) {
+	cps, err := c.getCheckpoints(ctx, false)
+	if err != nil {
+		c.logger.Warn("failed to get existing checkpoints for delta checkpoint creation",
+			"err", err,
+		)
+		return
+	}
+
+	for _, root := range roots {
+		var baseRoot *node.Root
+		for _, cp := range cps {
+			if cp.Root.Type != root.Type || cp.Root.Version >= root.Version {
+				continue
+			}
+			if baseRoot == nil || cp.Root.Version > baseRoot.Version {
+				baseRoot = &cp.Root
+			}
+		}
+		if baseRoot == nil || !c.ndb.HasRoot(*baseRoot) {
+			continue
+		}
+
+		c.logger.Info("creating new delta checkpoint",
+			"root", root,
+			"base_root", baseRoot,
+			"chunk_size", params.ChunkSize,
+		)
+
+		if _, err = c.creator.CreateDeltaCheckpoint(ctx, root, *baseRoot, params.ChunkSize, WithVersion(params.checkpointVersion())); err != nil {
+			c.logger.Warn("failed to create delta checkpoint",
+				"root", root,
+				"base_root", baseRoot,
+				"err", err,
+			)
+		}
+	}
+}
+
+// getCheckpoints returns the existing checkpoints of all supported versions.
+func (c *checkpointer) getCheckpoints(ctx context.Context, includeDeltas bool) ([]*Metadata, error) {
+	var cps []*Metadata
+	for _, version := range SupportedVersions {
+		vcps, err := c.creator.GetCheckpoints(ctx, &GetCheckpointsRequest{
+			Version:       version,
+			Namespace:     c.cfg.Namespace,
+			IncludeDeltas: includeDeltas,
+		})
+		if err != nil {
+			return nil, err
+		}
+		cps = append(cps, vcps...)
+	}
+	return cps, nil
+}
+
 func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, params *CreationParameters) error {
 	// Get a list of all current checkpoints.
-	cps, err := c.creator.GetCheckpoints(ctx, &GetCheckpointsRequest{
-		Version:   v1,
-		Namespace: c.cfg.Namespace,
-	})
+	cps, err := c.getCheckpoints(ctx, true)
 	if err != nil {
 		return fmt.Errorf("checkpointer: failed to get existing checkpoints: %w", err)
 	}
@@ -327,12 +408,17 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 	// Check if we need to create a new checkpoint based on the list of existing checkpoints.
 	var lastCheckpointVersion uint64
 	var cpVersions []uint64
-	cpsByVersion := make(map[uint64][]node.Root)
+	var deltas []*Metadata
+	cpsByVersion := make(map[uint64][]*Metadata)
 	for _, cp := range cps {
+		if cp.IsDelta() {
+			deltas = append(deltas, cp)
+			continue
+		}
 		if cpsByVersion[cp.Root.Version] == nil {
 			cpVersions = append(cpVersions, cp.Root.Version)
 		}
-		cpsByVersion[cp.Root.Version] = append(cpsByVersion[cp.Root.Version], cp.Root)
+		cpsByVersion[cp.Root.Version] = append(cpsByVersion[cp.Root.Version], cp)
 		if len(cpsByVersion[cp.Root.Version]) == c.cfg.RootsPerVersion && cp.Root.Version > lastCheckpointVersion {
 			lastCheckpointVersion = cp.Root.Version
 		}
@@ -389,17 +475,36 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 			"num_kept", params.NumKept,
 		)
 
+		removed := make(map[uint64]struct{})
 		for _, version := range cpVersions[:len(cpVersions)-int(params.NumKept)] {
-			for _, root := range cpsByVersion[version] {
-				if err = c.creator.DeleteCheckpoint(ctx, v1, root); err != nil {
+			removed[version] = struct{}{}
+			for _, cp := range cpsByVersion[version] {
+				if err = c.creator.DeleteCheckpoint(ctx, cp.Version, cp.Root); err != nil {
 					c.logger.Warn("failed to garbage collect checkpoint",
-						"root", root,
+						"root", cp.Root,
 						"err", err,
 					)
 					continue
 				}
 			}
 		}
+
+		// Also remove any delta checkpoints that refer to removed versions.
+		for _, cp := range deltas {
+			_, rootRemoved := removed[cp.Root.Version]
+			_, baseRemoved := removed[cp.BaseRoot.Version]
+			if !rootRemoved && !baseRemoved {
+				continue
+			}
+			if err = c.creator.DeleteDeltaCheckpoint(ctx, cp.Version, cp.Root, *cp.BaseRoot); err != nil {
+				c.logger.Warn("failed to garbage collect delta checkpoint",
+					"root", cp.Root,
+					"base_root", cp.BaseRoot,
+					"err", err,
+				)
+				continue
+			}
+		}
 	}
 
 	return nil
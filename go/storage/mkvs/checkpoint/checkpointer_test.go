@@ -24,7 +24,7 @@ const (
 	testNumKept       = 2
 )
 
-func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, interval uint64, preExistingData bool) {
+func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, interval uint64, preExistingData bool, version uint16, deltas bool) {
 	require := require.New(t)
 
 	var wg sync.WaitGroup
@@ -86,6 +86,8 @@ func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, inte
 			NumKept:        testNumKept,
 			ChunkSize:      16 * 1024,
 			InitialVersion: earliestVersion,
+			Version:        version,
+			Deltas:         deltas,
 		},
 		GetRoots: func(_ context.Context, version uint64) ([]node.Root, error) {
 			if version < earliestVersion {
@@ -133,7 +135,7 @@ func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, inte
 		// Make sure that there are always the correct number of checkpoints.
 		if round > earliestVersion+(testNumKept+1)*interval {
 			cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
-				Version:   v1,
+				Version:   version,
 				Namespace: testNs,
 			})
 			require.NoError(err, "GetCheckpoints")
@@ -149,6 +151,33 @@ func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, inte
 		}
 	}
 
+	if deltas {
+		// Make sure that delta checkpoints have been created and only refer to live checkpoints.
+		cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
+			Version:       version,
+			Namespace:     testNs,
+			IncludeDeltas: true,
+		})
+		require.NoError(err, "GetCheckpoints")
+
+		live := make(map[uint64]bool)
+		var numDeltas int
+		for _, cpm := range cps {
+			if !cpm.IsDelta() {
+				live[cpm.Root.Version] = true
+			}
+		}
+		for _, cpm := range cps {
+			if !cpm.IsDelta() {
+				continue
+			}
+			numDeltas++
+			require.True(live[cpm.Root.Version], "delta checkpoint root should be live")
+			require.True(live[cpm.BaseRoot.Version], "delta checkpoint base root should be live")
+		}
+		require.Equal(len(live)-1, numDeltas, "there should be a delta checkpoint for each non-first checkpoint")
+	}
+
 	// Force a checkpoint at a version outside the regular interval.
 	if interval > 1 {
 		cpVersion := round - interval + 1
@@ -162,7 +191,7 @@ func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, inte
 
 		// Make sure that the correct checkpoint was created.
 		cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
-			Version:   v1,
+			Version:   version,
 			Namespace: testNs,
 		})
 		require.NoError(err, "GetCheckpoints")
@@ -184,18 +213,24 @@ func TestCheckpointer(t *testing.T) {
 
 func testCheckpointerWithBackend(t *testing.T, factory dbApi.Factory) {
 	t.Run("Basic", func(t *testing.T) {
-		testCheckpointer(t, factory, 0, 1, false)
+		testCheckpointer(t, factory, 0, 1, false, v1, false)
 	})
 	t.Run("NonZeroEarliestVersion", func(t *testing.T) {
-		testCheckpointer(t, factory, 1000, 1, false)
+		testCheckpointer(t, factory, 1000, 1, false, v1, false)
 	})
 	t.Run("NonZeroEarliestInitialVersion", func(t *testing.T) {
-		testCheckpointer(t, factory, 100, 1, true)
+		testCheckpointer(t, factory, 100, 1, true, v1, false)
 	})
 	t.Run("MaybeUnderflow", func(t *testing.T) {
-		testCheckpointer(t, factory, 5, 10, true)
+		testCheckpointer(t, factory, 5, 10, true, v1, false)
 	})
 	t.Run("ForceCheckpoint", func(t *testing.T) {
-		testCheckpointer(t, factory, 0, 10, false)
+		testCheckpointer(t, factory, 0, 10, false, v1, false)
+	})
+	t.Run("Deltas", func(t *testing.T) {
+		testCheckpointer(t, factory, 0, 1, false, v1, true)
+	})
+	t.Run("Zstd", func(t *testing.T) {
+		testCheckpointer(t, factory, 0, 1, false, VersionZstd, true)
 	})
 }
@@ -7,7 +7,6 @@ import (
 	"io"
 	"sync"
 
-	"github.com/golang/snappy"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
@@ -48,6 +47,7 @@ type seqChunker struct {
 	ndb       db.NodeDB
 	root      node.Root
 	chunkSize uint64
+	codec     chunkCodec
 }
 
 // chunk implements chunker's chunk method.
@@ -118,7 +118,7 @@ func (sc *seqChunker) createChunk(ctx context.Context, tree mkvs.Tree, offset no
 		nextOffset = it.Key()
 	}
 
-	chunkHash, err = writeChunk(proof, w)
+	chunkHash, err = writeChunk(proof, w, sc.codec)
 	if err != nil {
 		return hash.Hash{}, nil, err
 	}
@@ -139,6 +139,7 @@ type parallelChunker struct {
 	root      node.Root
 	chunkSize uint64
 	threads   uint16
+	codec     chunkCodec
 }
 
 // chunk implements chunker's chunk method.
@@ -215,7 +216,7 @@ func (pc *parallelChunker) createChunks(ctx context.Context, wf writerFactory, t
 		}
 
 		group.Go(func() error {
-			hash, err := task.nextChunk(ctx, w, pc.chunkSize)
+			hash, err := task.nextChunk(ctx, w, pc.chunkSize, pc.codec)
 			if err != nil {
 				return fmt.Errorf("creating new chunk with index %d", idx)
 			}
@@ -246,26 +247,108 @@ func (pc *parallelChunker) filterFinished(tasks []*subtree) []*subtree {
 	return pending
 }
 
-func writeChunk(proof *syncer.Proof, w io.Writer) (hash.Hash, error) {
+// deltaChunker implements chunker interface.
+//
+// Chunks are created by (sequentially) iterating over a subtree that omits all subtrees that are
+// unchanged since the base root. The resulting chunks are regular proofs for the target root,
+// where the omitted subtrees are only referenced by their hashes.
+type deltaChunker struct {
+	ndb       db.NodeDB
+	root      node.Root
+	baseRoot  node.Root
+	chunkSize uint64
+	codec     chunkCodec
+}
+
+// chunk implements chunker's chunk method.
+func (dc *deltaChunker) chunk(ctx context.Context, wf writerFactory) ([]hash.Hash, error) {
+	st, err := newDeltaSubtree(dc.ndb, dc.root, &baseTree{ndb: dc.ndb, root: dc.baseRoot})
+	if err != nil {
+		return nil, err
+	}
+
+	// Always create at least one chunk, even if nothing has changed since the base root.
+	var chunks []hash.Hash
+	for {
+		idx, f, err := wf.next()
+		if err != nil {
+			return nil, fmt.Errorf("chunk: get writer for chunk %d: %w", idx, err)
+		}
+
+		chunkHash, err := st.nextChunk(ctx, f, dc.chunkSize, dc.codec)
+		if err != nil {
+			return nil, fmt.Errorf("chunk: create chunk %d: %w", idx, err)
+		}
+		chunks = append(chunks, chunkHash)
+
+		// Check if we are finished.
+		if st.hasNext() {
+			break
+		}
+	}
+
+	return chunks, nil
+}
+
+func writeChunk(proof *syncer.Proof, w io.Writer, codec chunkCodec) (hash.Hash, error) {
 	hb := hash.NewBuilder()
-	sw := snappy.NewBufferedWriter(io.MultiWriter(w, hb))
-	enc := cbor.NewEncoder(sw)
+	compressed := &countingWriter{w: io.MultiWriter(w, hb)}
+	sw, err := codec.newWriter(compressed)
+	if err != nil {
+		return hash.Hash{}, fmt.Errorf("failed to create chunk writer: %w", err)
+	}
+	uncompressed := &countingWriter{w: sw}
+	enc := cbor.NewEncoder(uncompressed)
 	for _, entry := range proof.Entries {
-		if err := enc.Encode(entry); err != nil {
+		if err = enc.Encode(entry); err != nil {
 			return hash.Hash{}, fmt.Errorf("failed to encode chunk part: %w", err)
 		}
 	}
-	if err := sw.Close(); err != nil {
+	if err = sw.Close(); err != nil {
 		return hash.Hash{}, fmt.Errorf("failed to close chunk: %w", err)
 	}
+	observeChunk(codec, uncompressed.n, compressed.n)
 
 	return hb.Build(), nil
 }
 
-func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io.Reader) error {
+// countingWriter is a writer that counts the number of bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n uint64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += uint64(n)
+	return n, err
+}
+
+// restoreChunk restores the given chunk into the node database.
+//
+// In case of delta chunks, the base tree must be given and subtrees that are unchanged since the
+// base root are restored from the base root, which must already be present in the node database.
+func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io.Reader, base *baseTree) error {
+	if (chunk.BaseRoot != nil) != (base != nil) {
+		return fmt.Errorf("chunk: base tree required iff restoring a delta chunk")
+	}
+	var rootPos *position
+	if base != nil {
+		rootPos = base.rootPosition()
+	}
+
+	codec, err := codecForVersion(chunk.Version)
+	if err != nil {
+		return err
+	}
+
 	hb := hash.NewBuilder()
 	tr := io.TeeReader(r, hb)
-	sr := snappy.NewReader(tr)
+	sr, err := codec.newReader(tr)
+	if err != nil {
+		return fmt.Errorf("chunk: failed to create chunk reader: %w", err)
+	}
+	defer sr.Close()
 	dec := cbor.NewDecoder(sr)
 
 	// Reconstruct the proof.
@@ -330,7 +413,7 @@ func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io
 	}
 	defer batch.Reset()
 
-	if err = doRestoreChunk(ctx, batch, ptr, nil); err != nil {
+	if err = doRestoreChunk(ctx, batch, ptr, nil, base, rootPos); err != nil {
 		return fmt.Errorf("chunk: node import failed: %w", err)
 	}
 	if err = batch.Commit(chunk.Root); err != nil {
@@ -345,6 +428,8 @@ func doRestoreChunk(
 	batch db.Batch,
 	ptr *node.Pointer,
 	parent *node.Pointer,
+	base *baseTree,
+	pos *position,
 ) error {
 	if ptr == nil {
 		return nil
@@ -352,6 +437,13 @@ func doRestoreChunk(
 
 	switch n := ptr.Node.(type) {
 	case nil:
+		if pos.isUnchanged(ptr) && !base.isRestored(ptr.Hash) {
+			if err := base.restoreSubtree(ctx, batch, ptr, pos.base, parent); err != nil {
+				return err
+			}
+			base.copied = append(base.copied, ptr.Hash)
+			return nil
+		}
 		if err := batch.VisitDirtyNode(ptr, parent); err != nil {
 			return err
 		}
@@ -361,12 +453,19 @@ func doRestoreChunk(
 		}
 
 		// Commit internal leaf (considered to be on the same depth as the internal node).
-		if err := doRestoreChunk(ctx, batch, n.LeafNode, ptr); err != nil {
+		if err := doRestoreChunk(ctx, batch, n.LeafNode, ptr, nil, nil); err != nil {
 			return err
 		}
 
-		for _, subNode := range []*node.Pointer{n.Left, n.Right} {
-			if err := doRestoreChunk(ctx, batch, subNode, ptr); err != nil {
+		for slot, subNode := range []*node.Pointer{n.Left, n.Right} {
+			var subPos *position
+			if base != nil && subNode != nil {
+				var err error
+				if subPos, err = base.childPosition(pos, n, childSlot(slot)); err != nil {
+					return err
+				}
+			}
+			if err := doRestoreChunk(ctx, batch, subNode, ptr, base, subPos); err != nil {
 				return err
 			}
 		}
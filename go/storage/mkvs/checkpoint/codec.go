@@ -0,0 +1,66 @@
+package checkpoint
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// chunkCodec is a compression codec used for checkpoint chunks.
+type chunkCodec interface {
+	// name returns the name of the codec.
+	name() string
+
+	// newWriter returns a writer that compresses everything written to it into w.
+	newWriter(w io.Writer) (io.WriteCloser, error)
+
+	// newReader returns a reader that decompresses everything read from r.
+	newReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// codecForVersion returns the chunk codec used by the given checkpoint version.
+func codecForVersion(version uint16) (chunkCodec, error) {
+	switch version {
+	case v1:
+		return snappyCodec{}, nil
+	case v2:
+		return zstdCodec{}, nil
+	default:
+		return nil, fmt.Errorf("checkpoint: unsupported version %d", version)
+	}
+}
+
+type snappyCodec struct{}
+
+func (snappyCodec) name() string {
+	return "snappy"
+}
+
+func (snappyCodec) newWriter(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+func (snappyCodec) newReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(snappy.NewReader(r)), nil
+}
+
+type zstdCodec struct{}
+
+func (zstdCodec) name() string {
+	return "zstd"
+}
+
+func (zstdCodec) newWriter(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+}
+
+func (zstdCodec) newReader(r io.Reader) (io.ReadCloser, error) {
+	// Use a single goroutine as chunks are small and restored concurrently anyway.
+	dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return dec.IOReadCloser(), nil
+}
@@ -0,0 +1,309 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// childSlot identifies a child of an internal node.
+type childSlot uint8
+
+const (
+	slotLeft childSlot = iota
+	slotRight
+)
+
+// baseTree is the base tree of a delta checkpoint.
+//
+// Nodes are matched against the base tree by their position which is given by the path from the
+// root (bits consumed by labels of all ancestors) and the slot within the parent node. A node of
+// the target tree whose hash equals the hash of the node at the same position in the base tree is
+// considered unchanged together with its whole subtree.
+type baseTree struct {
+	ndb  db.NodeDB
+	root node.Root
+
+	// isRestoredFn is an optional function that reports whether the base subtree with the given
+	// hash has already been restored by an earlier chunk.
+	isRestoredFn func(hash.Hash) bool
+	// copied are the hashes of base subtrees that have been restored by the current chunk.
+	copied []hash.Hash
+}
+
+// isRestored returns true iff the base subtree with the given hash has already been restored.
+func (bt *baseTree) isRestored(h hash.Hash) bool {
+	if bt.isRestoredFn == nil {
+		return false
+	}
+	return bt.isRestoredFn(h)
+}
+
+// baseCursor points to a node of the base tree that starts at the given bit depth. All bits of the
+// path up to that depth are known to match the path in the target tree.
+type baseCursor struct {
+	ptr   *node.Pointer
+	depth node.Depth
+}
+
+// position is the location of a node within the target tree, together with the corresponding
+// location in the base tree.
+type position struct {
+	// path is the path to the node, excluding the node's own label.
+	path    node.Key
+	pathLen node.Depth
+
+	// base is the node at the same position in the base tree (if any).
+	base *node.Pointer
+	// cursor is the base tree cursor used to look up descendants of the node.
+	cursor *baseCursor
+}
+
+// isUnchanged returns true iff the given node at this position is the same as in the base tree.
+func (p *position) isUnchanged(ptr *node.Pointer) bool {
+	if p == nil || p.base == nil || ptr == nil {
+		return false
+	}
+	return p.base.Hash.Equal(&ptr.Hash)
+}
+
+// rootPosition returns the position of the root node.
+func (bt *baseTree) rootPosition() *position {
+	ptr := &node.Pointer{
+		Clean: true,
+		Hash:  bt.root.Hash,
+	}
+	return &position{
+		base:   ptr,
+		cursor: &baseCursor{ptr: ptr},
+	}
+}
+
+// childPosition returns the position of the given child of an internal node at position pos.
+func (bt *baseTree) childPosition(pos *position, n *node.InternalNode, slot childSlot) (*position, error) {
+	merged, err := pos.path.Merge(pos.pathLen, n.Label, n.LabelBitLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive child path: %w", err)
+	}
+	pathLen := pos.pathLen + n.LabelBitLength
+	// Make sure that no bits beyond the path length remain set.
+	path, _, err := merged.Split(pathLen, pathLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive child path: %w", err)
+	}
+
+	base, cursor, err := bt.findChild(pos.cursor, path, pathLen, slot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &position{
+		path:    path,
+		pathLen: pathLen,
+		base:    base,
+		cursor:  cursor,
+	}, nil
+}
+
+// findChild looks up the base tree node at the position of the given child of an internal node
+// whose children start at the given path.
+//
+// It returns the matching base tree node pointer (if any) and the cursor that should be used to
+// look up descendants of the child.
+func (bt *baseTree) findChild(cur *baseCursor, path node.Key, pathLen node.Depth, slot childSlot) (*node.Pointer, *baseCursor, error) {
+	for cur != nil {
+		nd, err := bt.getNode(cur.ptr)
+		if err != nil {
+			return nil, nil, err
+		}
+		n, ok := nd.(*node.InternalNode)
+		if !ok {
+			// Leaf nodes and empty subtrees have no children.
+			return nil, nil, nil
+		}
+
+		end := cur.depth + n.LabelBitLength
+		if end > pathLen {
+			// No base node starts at the given position, but descendants may still match. Note
+			// that the label is verified once we look up a position below it.
+			return nil, cur, nil
+		}
+
+		// Make sure the label matches the path, otherwise the base tree has no nodes here.
+		_, suffix, err := path.Split(cur.depth, pathLen)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to split path: %w", err)
+		}
+		cpLen, err := n.Label.CommonPrefixLen(n.LabelBitLength, suffix, pathLen-cur.depth)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to compare label: %w", err)
+		}
+		if cpLen < n.LabelBitLength {
+			return nil, nil, nil
+		}
+
+		var right bool
+		switch end {
+		case pathLen:
+			right = slot == slotRight
+		default:
+			if right, err = path.GetBit(end); err != nil {
+				return nil, nil, fmt.Errorf("failed to get path bit: %w", err)
+			}
+		}
+
+		child := n.Left
+		if right {
+			child = n.Right
+		}
+		cur = &baseCursor{
+			ptr:   child,
+			depth: end,
+		}
+		if end == pathLen {
+			return child, cur, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+// getNode dereferences the given base tree pointer.
+func (bt *baseTree) getNode(ptr *node.Pointer) (node.Node, error) {
+	switch {
+	case ptr == nil:
+		return nil, nil
+	case ptr.Node != nil:
+		return ptr.Node, nil
+	case ptr.Hash.IsEmpty():
+		return nil, nil
+	}
+
+	nd, err := bt.ndb.GetNode(bt.root, ptr)
+	if err != nil {
+		return nil, fmt.Errorf("getting base node from nodedb (ptr hash: %.8s): %w", ptr.Hash, err)
+	}
+	return nd, nil
+}
+
+// restoreSubtree imports a copy of the base tree subtree rooted at basePtr into the given batch,
+// making ptr point to the imported copy.
+func (bt *baseTree) restoreSubtree(
+	ctx context.Context,
+	batch db.Batch,
+	ptr *node.Pointer,
+	basePtr *node.Pointer,
+	parent *node.Pointer,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	nd, err := bt.getNode(basePtr)
+	if err != nil {
+		return err
+	}
+
+	switch n := nd.(type) {
+	case *node.LeafNode:
+		ptr.Node = n.ExtractUnchecked()
+		if err = batch.VisitDirtyNode(ptr, parent); err != nil {
+			return err
+		}
+		return batch.PutNode(ptr)
+	case *node.InternalNode:
+		// Children must be set before visiting the node so the node database can link them with
+		// any existing nodes.
+		extract := func(p *node.Pointer) *node.Pointer {
+			if p == nil {
+				return nil
+			}
+			return &node.Pointer{
+				Clean: true,
+				Hash:  p.Hash,
+			}
+		}
+		in := &node.InternalNode{
+			Clean:          true,
+			Hash:           n.Hash,
+			Label:          n.Label,
+			LabelBitLength: n.LabelBitLength,
+			LeafNode:       extract(n.LeafNode),
+			Left:           extract(n.Left),
+			Right:          extract(n.Right),
+		}
+		ptr.Node = in
+		if err = batch.VisitDirtyNode(ptr, parent); err != nil {
+			return err
+		}
+
+		for _, child := range []struct {
+			src *node.Pointer
+			dst *node.Pointer
+		}{
+			{n.LeafNode, in.LeafNode},
+			{n.Left, in.Left},
+			{n.Right, in.Right},
+		} {
+			if child.src == nil {
+				continue
+			}
+			if err = bt.restoreSubtree(ctx, batch, child.dst, child.src, ptr); err != nil {
+				return err
+			}
+		}
+
+		if err = batch.PutNode(ptr); err != nil {
+			return err
+		}
+
+		// Children have already been stored, so there is no need to keep them in memory.
+		for _, child := range []*node.Pointer{in.Left, in.Right} {
+			if child != nil {
+				child.Node = nil
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unexpected base node for non-empty pointer (ptr hash: %.8s)", basePtr.Hash)
+	}
+}
+
+// DeltaChain returns the sequence of checkpoints that needs to be restored in order to restore
+// the given root, starting with a full checkpoint and followed by a (possibly empty) chain of
+// delta checkpoints, each based on the root of the previous one.
+//
+// In case multiple chains are possible, the one with the fewest checkpoints is returned.
+func DeltaChain(checkpoints []*Metadata, root node.Root) ([]*Metadata, error) {
+	var (
+		best  []*Metadata
+		visit func(cp *Metadata, chain []*Metadata)
+	)
+	visit = func(cp *Metadata, chain []*Metadata) {
+		chain = append([]*Metadata{cp}, chain...)
+		if best != nil && len(chain) >= len(best) {
+			return
+		}
+		if !cp.IsDelta() {
+			best = chain
+			return
+		}
+		for _, base := range checkpoints {
+			// Only follow bases with earlier versions to rule out cycles.
+			if base.Root.Version < cp.Root.Version && base.Root.Equal(cp.BaseRoot) {
+				visit(base, chain)
+			}
+		}
+	}
+	for _, cp := range checkpoints {
+		if cp.Root.Equal(&root) {
+			visit(cp, nil)
+		}
+	}
+	if best == nil {
+		return nil, ErrCheckpointNotFound
+	}
+	return best, nil
+}
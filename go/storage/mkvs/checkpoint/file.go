@@ -16,10 +16,13 @@ import (
 
 const (
 	chunksDirname = "chunks"
+	deltasDirname = "deltas"
 	metaFilename  = "meta"
 	v1            = 1
+	v2            = 2
 
-	// Versions 1 of checkpoint chunks use proofs version 0.
+	// Versions 1 and 2 of checkpoint chunks use proofs version 0. They only differ in the
+	// codec used to compress chunks (see codecForVersion).
 	//
 	// Using proof version 1 (latest), does not reduce chunk size and would require
 	// releasing new checkpoint version.
@@ -33,13 +36,59 @@ type fileCreator struct {
 	ndb     db.NodeDB
 }
 
-func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chunkSize uint64, chunkerThreads uint16) (meta *Metadata, err error) {
+func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chunkSize uint64, chunkerThreads uint16, opts ...CreateOption) (*Metadata, error) {
+	version := newCreateOptions(opts).version
+	codec, err := codecForVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	var ch chunker
+	switch {
+	case chunkerThreads > 0:
+		ch = &parallelChunker{ndb: fc.ndb, root: root, chunkSize: chunkSize, threads: chunkerThreads, codec: codec}
+	default:
+		// Deprecated.
+		ch = &seqChunker{ndb: fc.ndb, root: root, chunkSize: chunkSize, codec: codec}
+	}
+
+	meta, err := fc.createCheckpoint(ctx, fc.checkpointDir(version, root, nil), version, root, nil, ch)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to create chunks (chunker threads: %d): %w", chunkerThreads, err)
+	}
+	return meta, nil
+}
+
+func (fc *fileCreator) CreateDeltaCheckpoint(ctx context.Context, root node.Root, baseRoot node.Root, chunkSize uint64, opts ...CreateOption) (*Metadata, error) {
+	version := newCreateOptions(opts).version
+	codec, err := codecForVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	if !root.Namespace.Equal(&baseRoot.Namespace) || root.Type != baseRoot.Type {
+		return nil, fmt.Errorf("checkpoint: base root is not compatible with root")
+	}
+	if baseRoot.Version >= root.Version {
+		return nil, fmt.Errorf("checkpoint: base root version must be lower than root version")
+	}
+
+	ch := &deltaChunker{ndb: fc.ndb, root: root, baseRoot: baseRoot, chunkSize: chunkSize, codec: codec}
+	meta, err := fc.createCheckpoint(ctx, fc.checkpointDir(version, root, &baseRoot), version, root, &baseRoot, ch)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to create delta chunks: %w", err)
+	}
+	return meta, nil
+}
+
+func (fc *fileCreator) createCheckpoint(
+	ctx context.Context,
+	cpDir string,
+	version uint16,
+	root node.Root,
+	baseRoot *node.Root,
+	ch chunker,
+) (meta *Metadata, err error) {
 	// Create checkpoint directory.
-	cpDir := filepath.Join(
-		fc.dataDir,
-		strconv.FormatUint(root.Version, 10),
-		root.Hash.String(),
-	)
 	if err = common.Mkdir(cpDir); err != nil {
 		return nil, fmt.Errorf("checkpoint: failed to create checkpoint directory: %w", err)
 	}
@@ -67,23 +116,16 @@ func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chu
 	}
 	// Create chunks.
 	fp := &fileProvider{dir: chunksDir}
-	var ch chunker
-	switch {
-	case chunkerThreads > 0:
-		ch = &parallelChunker{ndb: fc.ndb, root: root, chunkSize: chunkSize, threads: chunkerThreads}
-	default:
-		// Deprecated.
-		ch = &seqChunker{ndb: fc.ndb, root: root, chunkSize: chunkSize}
-	}
 	chunks, err := ch.chunk(ctx, fp)
 	if err != nil {
-		return nil, fmt.Errorf("checkpoint: failed to create chunks (chunker threads: %d): %w", chunkerThreads, err)
+		return nil, err
 	}
 
 	meta = &Metadata{
-		Version: v1,
-		Root:    root,
-		Chunks:  chunks,
+		Version:  version,
+		Root:     root,
+		BaseRoot: baseRoot,
+		Chunks:   chunks,
 	}
 
 	if err = os.WriteFile(filepath.Join(cpDir, metaFilename), cbor.Marshal(meta), 0o600); err != nil {
@@ -92,9 +134,43 @@ func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chu
 	return meta, nil
 }
 
+// versionDir returns the directory holding checkpoints of the given version.
+//
+// Version 1 checkpoints are stored directly in the data directory for backwards compatibility,
+// checkpoints of other versions are stored in a separate subdirectory each so that checkpoints
+// of different versions for the same root do not conflict.
+func (fc *fileCreator) versionDir(version uint16) string {
+	if version == v1 {
+		return fc.dataDir
+	}
+	return filepath.Join(fc.dataDir, "v"+strconv.FormatUint(uint64(version), 10))
+}
+
+// checkpointDir returns the directory of the checkpoint of the given version for the given root.
+//
+// Delta checkpoints are stored separately from full checkpoints, keyed by both their root and
+// their base root.
+func (fc *fileCreator) checkpointDir(version uint16, root node.Root, baseRoot *node.Root) string {
+	if baseRoot == nil {
+		return filepath.Join(
+			fc.versionDir(version),
+			strconv.FormatUint(root.Version, 10),
+			root.Hash.String(),
+		)
+	}
+	return filepath.Join(
+		fc.versionDir(version),
+		deltasDirname,
+		strconv.FormatUint(root.Version, 10),
+		root.Hash.String(),
+		strconv.FormatUint(baseRoot.Version, 10),
+		baseRoot.Hash.String(),
+	)
+}
+
 func (fc *fileCreator) GetCheckpoints(_ context.Context, request *GetCheckpointsRequest) ([]*Metadata, error) {
-	// Currently we only support a single version so we report no checkpoints for other versions.
-	if request.Version != v1 {
+	// Report no checkpoints for unsupported versions.
+	if _, err := codecForVersion(request.Version); err != nil {
 		return []*Metadata{}, nil
 	}
 
@@ -104,10 +180,18 @@ func (fc *fileCreator) GetCheckpoints(_ context.Context, request *GetCheckpoints
 		versionGlob = strconv.FormatUint(*request.RootVersion, 10)
 	}
 
-	matches, err := filepath.Glob(filepath.Join(fc.dataDir, versionGlob, "*", metaFilename))
+	versionDir := fc.versionDir(request.Version)
+	matches, err := filepath.Glob(filepath.Join(versionDir, versionGlob, "*", metaFilename))
 	if err != nil {
 		return nil, fmt.Errorf("checkpoint: failed to enumerate checkpoints: %w", err)
 	}
+	if request.IncludeDeltas {
+		deltas, err := filepath.Glob(filepath.Join(versionDir, deltasDirname, versionGlob, "*", "*", "*", metaFilename))
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint: failed to enumerate delta checkpoints: %w", err)
+		}
+		matches = append(matches, deltas...)
+	}
 
 	var cps []*Metadata
 	for _, m := range matches {
@@ -120,6 +204,9 @@ func (fc *fileCreator) GetCheckpoints(_ context.Context, request *GetCheckpoints
 		if err = cbor.Unmarshal(data, &cp); err != nil {
 			return nil, fmt.Errorf("checkpoint: corrupted checkpoint metadata at %s: %w", m, err)
 		}
+		if cp.Version != request.Version {
+			continue
+		}
 
 		cps = append(cps, &cp)
 	}
@@ -127,17 +214,7 @@ func (fc *fileCreator) GetCheckpoints(_ context.Context, request *GetCheckpoints
 }
 
 func (fc *fileCreator) GetCheckpoint(_ context.Context, version uint16, root node.Root) (*Metadata, error) {
-	// Currently we only support a single version.
-	if version != v1 {
-		return nil, ErrCheckpointNotFound
-	}
-
-	metaPath := filepath.Join(
-		fc.dataDir,
-		strconv.FormatUint(root.Version, 10),
-		root.Hash.String(),
-		metaFilename,
-	)
+	metaPath := filepath.Join(fc.checkpointDir(version, root, nil), metaFilename)
 	data, err := os.ReadFile(metaPath)
 	if err != nil {
 		return nil, ErrCheckpointNotFound
@@ -147,58 +224,87 @@ func (fc *fileCreator) GetCheckpoint(_ context.Context, version uint16, root nod
 	if err = cbor.Unmarshal(data, &cp); err != nil {
 		return nil, fmt.Errorf("checkpoint: corrupted checkpoint metadata: %w", err)
 	}
+	if cp.Version != version {
+		return nil, ErrCheckpointNotFound
+	}
 	return &cp, nil
 }
 
 func (fc *fileCreator) DeleteCheckpoint(_ context.Context, version uint16, root node.Root) error {
-	// Currently we only support a single version.
-	if version != v1 {
+	return fc.deleteCheckpoint(fc.checkpointDir(version, root, nil), fc.versionDir(version), version)
+}
+
+func (fc *fileCreator) DeleteDeltaCheckpoint(_ context.Context, version uint16, root node.Root, baseRoot node.Root) error {
+	return fc.deleteCheckpoint(fc.checkpointDir(version, root, &baseRoot), filepath.Join(fc.versionDir(version), deltasDirname), version)
+}
+
+// deleteCheckpoint removes the given checkpoint directory together with any of its parent
+// directories up to (but excluding) topDir that are left empty.
+func (fc *fileCreator) deleteCheckpoint(cpDir string, topDir string, version uint16) error {
+	metaPath := filepath.Join(cpDir, metaFilename)
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		return ErrCheckpointNotFound
+	}
+	var cp Metadata
+	if err = cbor.Unmarshal(data, &cp); err != nil {
+		return fmt.Errorf("checkpoint: corrupted checkpoint metadata: %w", err)
+	}
+	if cp.Version != version {
 		return ErrCheckpointNotFound
 	}
 
-	versionDir := filepath.Join(fc.dataDir, strconv.FormatUint(root.Version, 10))
-	cpDir := filepath.Join(versionDir, root.Hash.String())
-	metaPath := filepath.Join(cpDir, metaFilename)
-	if err := os.Remove(metaPath); err != nil {
+	if err = os.Remove(metaPath); err != nil {
 		return ErrCheckpointNotFound
 	}
 
-	if err := os.RemoveAll(cpDir); err != nil {
+	if err = os.RemoveAll(cpDir); err != nil {
 		return fmt.Errorf("checkpoint: failed to remove checkpoint directory: %w", err)
 	}
 
-	// If there are no more roots for the given version, remove the version directory as well.
-	f, err := os.Open(versionDir)
+	// If there are no more roots for the given version, remove the parent directories as well.
+	for dir := filepath.Dir(cpDir); dir != topDir && dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		empty, err := isEmptyDir(dir)
+		if err != nil {
+			return err
+		}
+		if !empty {
+			break
+		}
+		if err = os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("checkpoint: failed to remove directory %s: %w", dir, err)
+		}
+	}
+
+	return nil
+}
+
+func isEmptyDir(dir string) (bool, error) {
+	f, err := os.Open(dir)
 	if err != nil {
-		return fmt.Errorf("checkpoint: failed to open directory: %w", err)
+		return false, fmt.Errorf("checkpoint: failed to open directory: %w", err)
 	}
 	defer f.Close()
 
 	switch _, err = f.Readdir(1); err {
 	case nil:
 		// Non-empty directory.
+		return false, nil
 	case io.EOF:
-		// Directory is empty, we can remove it.
-		if err = os.RemoveAll(versionDir); err != nil {
-			return fmt.Errorf("checkpoint: failed to remove version %d directory: %w", root.Version, err)
-		}
+		// Directory is empty.
+		return true, nil
 	default:
-		return fmt.Errorf("checkpoint: failed to read directory: %w", err)
+		return false, fmt.Errorf("checkpoint: failed to read directory: %w", err)
 	}
-
-	return nil
 }
 
 func (fc *fileCreator) GetCheckpointChunk(_ context.Context, chunk *ChunkMetadata, w io.Writer) error {
-	// Currently we only support a single version.
-	if chunk.Version != v1 {
+	if _, err := codecForVersion(chunk.Version); err != nil {
 		return ErrChunkNotFound
 	}
 
 	chunkPath := filepath.Join(
-		fc.dataDir,
-		strconv.FormatUint(chunk.Root.Version, 10),
-		chunk.Root.Hash.String(),
+		fc.checkpointDir(chunk.Version, chunk.Root, chunk.BaseRoot),
 		chunksDirname,
 		strconv.FormatUint(chunk.Index, 10),
 	)
@@ -13,6 +13,10 @@ type ChunkMetadata struct {
 	Root    node.Root `json:"root"`
 	Index   uint64    `json:"index"`
 	Digest  hash.Hash `json:"digest"`
+
+	// BaseRoot is the base root of the delta checkpoint this chunk belongs to. It is nil for
+	// chunks of full checkpoints.
+	BaseRoot *node.Root `json:"base_root,omitempty"`
 }
 
 // Metadata is checkpoint metadata.
@@ -20,6 +24,11 @@ type Metadata struct {
 	Version uint16      `json:"version"`
 	Root    node.Root   `json:"root"`
 	Chunks  []hash.Hash `json:"chunks"`
+
+	// BaseRoot is the root of the base checkpoint in case this is a delta checkpoint. Delta
+	// checkpoints only contain nodes that are new since the base root and can only be restored
+	// into a node database that already contains the base root.
+	BaseRoot *node.Root `json:"base_root,omitempty"`
 }
 
 // Validate checks that the metadata is structurally valid.
@@ -33,9 +42,25 @@ func (m *Metadata) Validate() error {
 	if len(m.Chunks) == 0 {
 		return fmt.Errorf("zero chunks")
 	}
+	if m.BaseRoot != nil {
+		if m.BaseRoot.Type != m.Root.Type {
+			return fmt.Errorf("base root type mismatch (expected: %s got: %s)", m.Root.Type, m.BaseRoot.Type)
+		}
+		if !m.BaseRoot.Namespace.Equal(&m.Root.Namespace) {
+			return fmt.Errorf("base root namespace mismatch")
+		}
+		if m.BaseRoot.Version >= m.Root.Version {
+			return fmt.Errorf("base root version %d not before root version %d", m.BaseRoot.Version, m.Root.Version)
+		}
+	}
 	return nil
 }
 
+// IsDelta returns true iff this is a delta checkpoint.
+func (m *Metadata) IsDelta() bool {
+	return m.BaseRoot != nil
+}
+
 // EncodedHash returns the encoded cryptographic hash of the checkpoint metadata.
 func (m *Metadata) EncodedHash() hash.Hash {
 	return hash.NewFrom(m)
@@ -48,9 +73,10 @@ func (m Metadata) GetChunkMetadata(idx uint64) (*ChunkMetadata, error) {
 	}
 
 	return &ChunkMetadata{
-		Version: m.Version,
-		Root:    m.Root,
-		Index:   idx,
-		Digest:  m.Chunks[int(idx)],
+		Version:  m.Version,
+		Root:     m.Root,
+		Index:    idx,
+		Digest:   m.Chunks[int(idx)],
+		BaseRoot: m.BaseRoot,
 	}, nil
 }
@@ -25,6 +25,8 @@ func TestMetadataValidate(t *testing.T) {
 		{name: "nil", meta: nil, wantErr: "nil"},
 		{name: "zero chunks", meta: &Metadata{Root: validRoot}, wantErr: "zero chunks"},
 		{name: "invalid root type", meta: &Metadata{Root: invalidRoot, Chunks: validChunks}, wantErr: "invalid root type"},
+		{name: "base root type mismatch", meta: &Metadata{Root: validRoot, BaseRoot: &node.Root{Namespace: testNs, Type: node.RootTypeState}, Chunks: validChunks}, wantErr: "base root type mismatch"},
+		{name: "base root not before root", meta: &Metadata{Root: validRoot, BaseRoot: &validRoot, Chunks: validChunks}, wantErr: "not before root version"},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -33,6 +35,35 @@ func TestMetadataValidate(t *testing.T) {
 	}
 }
 
+func TestDeltaChain(t *testing.T) {
+	require := require.New(t)
+
+	roots := make([]node.Root, 4)
+	for i := range roots {
+		roots[i] = node.Root{Namespace: testNs, Version: uint64(i + 1), Type: node.RootTypeState}
+		roots[i].Hash.FromBytes([]byte{byte(i)})
+	}
+	full := func(i int) *Metadata {
+		return &Metadata{Version: 1, Root: roots[i], Chunks: []hash.Hash{{}}}
+	}
+	delta := func(i, base int) *Metadata {
+		return &Metadata{Version: 1, Root: roots[i], BaseRoot: &roots[base], Chunks: []hash.Hash{{}}}
+	}
+
+	full0, delta01, delta12, delta02, delta23 := full(0), delta(1, 0), delta(2, 1), delta(2, 0), delta(3, 2)
+
+	chain, err := DeltaChain([]*Metadata{full0, delta01, delta12, delta23}, roots[3])
+	require.NoError(err, "DeltaChain")
+	require.Equal([]*Metadata{full0, delta01, delta12, delta23}, chain)
+
+	chain, err = DeltaChain([]*Metadata{full0, delta01, delta12, delta02, delta23}, roots[3])
+	require.NoError(err, "DeltaChain")
+	require.Equal([]*Metadata{full0, delta02, delta23}, chain, "shortest chain should be used")
+
+	_, err = DeltaChain([]*Metadata{delta01, delta12}, roots[2])
+	require.ErrorIs(err, ErrCheckpointNotFound, "chain without a full checkpoint should fail")
+}
+
 // TestMetadataValidateCpForEmptyState is a regression test asserting that a checkpoint created for
 // an empty root produces at least one chunk, so that Validate passes.
 func TestMetadataValidateCpForEmptyState(t *testing.T) {
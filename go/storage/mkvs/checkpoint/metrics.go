@@ -0,0 +1,49 @@
+package checkpoint
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// labelCodec is the label for the chunk compression codec.
+const labelCodec = "codec"
+
+var (
+	chunkSize = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name: "oasis_storage_checkpoint_chunk_size",
+			Help: "Size of created checkpoint chunks after compression (bytes).",
+		},
+		[]string{labelCodec},
+	)
+	chunkCompressionRatio = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name: "oasis_storage_checkpoint_chunk_compression_ratio",
+			Help: "Ratio between the uncompressed and compressed size of created checkpoint chunks.",
+		},
+		[]string{labelCodec},
+	)
+
+	checkpointCollectors = []prometheus.Collector{
+		chunkSize,
+		chunkCompressionRatio,
+	}
+
+	metricsOnce sync.Once
+)
+
+func initMetrics() {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(checkpointCollectors...)
+	})
+}
+
+// observeChunk records metrics for a created chunk.
+func observeChunk(codec chunkCodec, uncompressed, compressed uint64) {
+	labels := prometheus.Labels{labelCodec: codec.name()}
+	chunkSize.With(labels).Observe(float64(compressed))
+	if compressed > 0 {
+		chunkCompressionRatio.With(labels).Observe(float64(uncompressed) / float64(compressed))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"sync"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 )
 
@@ -20,6 +21,9 @@ type restorer struct {
 	currentCheckpoint *Metadata
 	// pendingChunks is a set of pending chunks.
 	pendingChunks map[uint64]bool
+	// restoredBase is a set of base subtrees that have already been restored in case the
+	// checkpoint that is being restored is a delta checkpoint.
+	restoredBase map[hash.Hash]struct{}
 }
 
 // Implements Restorer.
@@ -31,7 +35,17 @@ func (rs *restorer) StartRestore(_ context.Context, checkpoint *Metadata) error
 		return ErrRestoreAlreadyInProgress
 	}
 
+	if _, err := codecForVersion(checkpoint.Version); err != nil {
+		return err
+	}
+
+	// Delta checkpoints can only be restored on top of their base root.
+	if checkpoint.IsDelta() && !rs.ndb.HasRoot(*checkpoint.BaseRoot) {
+		return ErrBaseRootNotFound
+	}
+
 	rs.currentCheckpoint = checkpoint
+	rs.restoredBase = make(map[hash.Hash]struct{})
 	rs.pendingChunks = make(map[uint64]bool)
 	for idx := range checkpoint.Chunks {
 		rs.pendingChunks[uint64(idx)] = true
@@ -46,6 +60,7 @@ func (rs *restorer) AbortRestore(context.Context) error {
 
 	rs.pendingChunks = nil
 	rs.currentCheckpoint = nil
+	rs.restoredBase = nil
 
 	return nil
 }
@@ -83,7 +98,16 @@ func (rs *restorer) RestoreChunk(ctx context.Context, idx uint64, r io.Reader) (
 		return false, err
 	}
 
-	err = restoreChunk(ctx, rs.ndb, chunk, r)
+	var base *baseTree
+	if chunk.BaseRoot != nil {
+		base = &baseTree{
+			ndb:          rs.ndb,
+			root:         *chunk.BaseRoot,
+			isRestoredFn: rs.isBaseRestored,
+		}
+	}
+
+	err = restoreChunk(ctx, rs.ndb, chunk, r, base)
 	switch {
 	case err == nil:
 	case errors.Is(err, ErrChunkProofVerificationFailed):
@@ -100,17 +124,31 @@ func (rs *restorer) RestoreChunk(ctx context.Context, idx uint64, r io.Reader) (
 
 	// Mark the given chunk as restored.
 	delete(rs.pendingChunks, idx)
+	if base != nil && rs.restoredBase != nil {
+		for _, h := range base.copied {
+			rs.restoredBase[h] = struct{}{}
+		}
+	}
 
 	// If there are no more pending chunks, restore is done.
 	if len(rs.pendingChunks) == 0 {
 		rs.pendingChunks = nil
 		rs.currentCheckpoint = nil
+		rs.restoredBase = nil
 		return true, nil
 	}
 
 	return false, nil
 }
 
+func (rs *restorer) isBaseRestored(h hash.Hash) bool {
+	rs.Lock()
+	defer rs.Unlock()
+
+	_, ok := rs.restoredBase[h]
+	return ok
+}
+
 // NewRestorer creates a new checkpoint restorer.
 func NewRestorer(ndb db.NodeDB) (Restorer, error) {
 	return &restorer{ndb: ndb}, nil
@@ -28,6 +28,10 @@ const (
 type pathAtom struct {
 	nd         node.Node
 	visitState visitState
+
+	// pos is the position of the node within the tree. It is only tracked when chunking a delta
+	// against a base tree.
+	pos *position
 }
 
 // subtree is a subtree that is being chunked.
@@ -35,6 +39,10 @@ type subtree struct {
 	ndb  db.NodeDB
 	root node.Root
 
+	// base is the optional base tree. If set, subtrees that are unchanged since the base tree
+	// are omitted from the chunks.
+	base *baseTree
+
 	// path is a path from root to subroot (exclusive).
 	//
 	// Invariant 1: path can only contain:
@@ -56,6 +64,12 @@ type subtree struct {
 }
 
 func newSubtree(ndb db.NodeDB, root node.Root) (*subtree, error) {
+	return newDeltaSubtree(ndb, root, nil)
+}
+
+// newDeltaSubtree creates a new subtree that omits all subtrees that are unchanged since the given
+// base tree. If the base tree is nil, all nodes are included.
+func newDeltaSubtree(ndb db.NodeDB, root node.Root, base *baseTree) (*subtree, error) {
 	rootPtr := node.Pointer{
 		Clean: true,
 		Hash:  root.Hash,
@@ -64,10 +78,17 @@ func newSubtree(ndb db.NodeDB, root node.Root) (*subtree, error) {
 	rootSubtree := subtree{
 		ndb:  ndb,
 		root: root,
+		base: base,
+	}
+
+	var rootPos *position
+	if base != nil {
+		rootPos = base.rootPosition()
 	}
+
 	// If root is empty we should still return non-empty state
 	// that will produce an empty proof.
-	if err := rootSubtree.visitNext(&rootPtr); err != nil {
+	if err := rootSubtree.visitNext(&rootPtr, rootPos); err != nil {
 		return nil, err
 	}
 
@@ -75,13 +96,18 @@ func newSubtree(ndb db.NodeDB, root node.Root) (*subtree, error) {
 }
 
 // visitNext marks a node that the ptr is pointing to as pending.
-func (s *subtree) visitNext(ptr *node.Pointer) error {
+func (s *subtree) visitNext(ptr *node.Pointer, pos *position) error {
 	if ptr == nil {
 		return nil
 	}
 
 	if ptr.Hash.IsEmpty() {
-		s.pending = append(s.pending, pathAtom{nil, visitBefore})
+		s.pending = append(s.pending, pathAtom{nil, visitBefore, nil})
+		return nil
+	}
+
+	if pos.isUnchanged(ptr) {
+		// The subtree is unchanged since the base tree, so there is no need to include it.
 		return nil
 	}
 
@@ -90,14 +116,34 @@ func (s *subtree) visitNext(ptr *node.Pointer) error {
 		return fmt.Errorf("getting node from nodedb (ptr hash: %.8s): %w", ptr.Hash, err)
 	}
 
-	s.pending = append(s.pending, pathAtom{nd, visitBefore})
+	s.pending = append(s.pending, pathAtom{nd, visitBefore, pos})
 	return nil
 }
 
+// visitChild marks the given child of an internal node as pending.
+func (s *subtree) visitChild(parent pathAtom, nd *node.InternalNode, slot childSlot) error {
+	child := nd.Left
+	if slot == slotRight {
+		child = nd.Right
+	}
+	if child == nil {
+		return nil
+	}
+
+	var pos *position
+	if s.base != nil {
+		var err error
+		if pos, err = s.base.childPosition(parent.pos, nd, slot); err != nil {
+			return err
+		}
+	}
+	return s.visitNext(child, pos)
+}
+
 // nextChunk creates a next chunk, taking previous chunking state into account.
 //
 // Calling this on finished subtree produces empty chunk (proof).
-func (s *subtree) nextChunk(ctx context.Context, w io.WriteCloser, chunkSize uint64) (hash.Hash, error) {
+func (s *subtree) nextChunk(ctx context.Context, w io.WriteCloser, chunkSize uint64, codec chunkCodec) (hash.Hash, error) {
 	defer func() {
 		w.Close()
 		s.trim()
@@ -137,18 +183,18 @@ func (s *subtree) nextChunk(ctx context.Context, w io.WriteCloser, chunkSize uin
 			switch last.visitState {
 			case visitBefore:
 				lastIsLeaf = false
-				s.pending = append(s.pending, pathAtom{nd, visitAt})
+				s.pending = append(s.pending, pathAtom{nd, visitAt, last.pos})
 				if nd.LeafNode != nil {
-					s.pending = append(s.pending, pathAtom{nd.LeafNode.Node, visitBefore})
+					s.pending = append(s.pending, pathAtom{nd.LeafNode.Node, visitBefore, nil})
 				}
 			case visitAt:
-				s.pending = append(s.pending, pathAtom{nd, visitAtLeft})
-				if err := s.visitNext(nd.Left); err != nil {
+				s.pending = append(s.pending, pathAtom{nd, visitAtLeft, last.pos})
+				if err := s.visitChild(last, nd, slotLeft); err != nil {
 					return hash.Hash{}, err
 				}
 			case visitAtLeft:
-				s.pending = append(s.pending, pathAtom{nd, visitAtRight})
-				if err := s.visitNext(nd.Right); err != nil {
+				s.pending = append(s.pending, pathAtom{nd, visitAtRight, last.pos})
+				if err := s.visitChild(last, nd, slotRight); err != nil {
 					return hash.Hash{}, err
 				}
 			case visitAtRight:
@@ -166,7 +212,7 @@ func (s *subtree) nextChunk(ctx context.Context, w io.WriteCloser, chunkSize uin
 		return hash.Hash{}, err
 	}
 
-	return writeChunk(proof, w)
+	return writeChunk(proof, w, codec)
 }
 
 // trim removes fully visited path atoms from the pending path, thus ensuring
@@ -214,6 +260,10 @@ func (s *subtree) split() ([]*subtree, error) {
 	if s.hasNext() {
 		return nil, nil
 	}
+	if s.base != nil {
+		// Splitting delta subtrees is not supported.
+		return []*subtree{s}, nil
+	}
 
 	subroot := s.pending[0]
 	nd, ok := subroot.nd.(*node.InternalNode)
@@ -0,0 +1,187 @@
+package mkvs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// DefaultStatsPrefixLength is the default length of key prefixes used for aggregating per-prefix
+// tree statistics.
+const DefaultStatsPrefixLength = 1
+
+// TreeStats are statistics about the structure and contents of a tree.
+type TreeStats struct {
+	// InternalNodes is the number of internal nodes in the tree.
+	InternalNodes uint64 `json:"internal_nodes"`
+	// LeafNodes is the number of leaf nodes (keys) in the tree.
+	LeafNodes uint64 `json:"leaf_nodes"`
+
+	// KeyBytes is the total size of all keys (bytes).
+	KeyBytes uint64 `json:"key_bytes"`
+	// ValueBytes is the total size of all values (bytes).
+	ValueBytes uint64 `json:"value_bytes"`
+
+	// MaxDepth is the maximum depth (in internal nodes) at which a leaf node is located.
+	MaxDepth uint64 `json:"max_depth"`
+	// LeafDepths is a histogram of leaf node depths, indexed by depth.
+	LeafDepths []uint64 `json:"leaf_depths"`
+
+	// Prefixes are the per-key-prefix statistics, ordered by total size (largest first).
+	Prefixes []*PrefixStats `json:"prefixes"`
+}
+
+// PrefixStats are statistics about all keys sharing the same prefix.
+type PrefixStats struct {
+	// Prefix is the key prefix.
+	Prefix []byte `json:"prefix"`
+	// Keys is the number of keys with the given prefix.
+	Keys uint64 `json:"keys"`
+	// KeyBytes is the total size of keys with the given prefix (bytes).
+	KeyBytes uint64 `json:"key_bytes"`
+	// ValueBytes is the total size of values of keys with the given prefix (bytes).
+	ValueBytes uint64 `json:"value_bytes"`
+}
+
+// Size returns the total size of keys and values with the given prefix.
+func (ps *PrefixStats) Size() uint64 {
+	return ps.KeyBytes + ps.ValueBytes
+}
+
+type statsOptions struct {
+	prefixLength int
+}
+
+// StatsOption is a tree statistics option.
+type StatsOption func(o *statsOptions)
+
+// WithStatsPrefixLength configures the length of key prefixes used for aggregating per-prefix
+// statistics. Keys shorter than the prefix length are aggregated under the full key.
+func WithStatsPrefixLength(length int) StatsOption {
+	return func(o *statsOptions) {
+		o.prefixLength = length
+	}
+}
+
+// ComputeStats walks the whole tree and computes its statistics.
+//
+// Note that this requires all nodes of the tree to be fetched which may be expensive for large
+// trees.
+func ComputeStats(ctx context.Context, t Tree, options ...StatsOption) (*TreeStats, error) {
+	tt, ok := t.(*tree)
+	if !ok {
+		return nil, fmt.Errorf("mkvs: unsupported tree type %T", t)
+	}
+
+	opts := statsOptions{
+		prefixLength: DefaultStatsPrefixLength,
+	}
+	for _, o := range options {
+		o(&opts)
+	}
+	if opts.prefixLength < 0 {
+		return nil, fmt.Errorf("mkvs: invalid stats prefix length %d", opts.prefixLength)
+	}
+
+	tt.cache.Lock()
+	defer tt.cache.Unlock()
+
+	if tt.cache.isClosed() {
+		return nil, ErrClosed
+	}
+
+	sc := statsCollector{
+		tree:     tt,
+		opts:     &opts,
+		prefixes: make(map[string]*PrefixStats),
+	}
+	if err := sc.walk(ctx, tt.cache.pendingRoot, 0); err != nil {
+		return nil, err
+	}
+
+	stats := &sc.stats
+	for _, ps := range sc.prefixes {
+		stats.Prefixes = append(stats.Prefixes, ps)
+	}
+	slices.SortFunc(stats.Prefixes, func(a, b *PrefixStats) int {
+		switch {
+		case a.Size() > b.Size():
+			return -1
+		case a.Size() < b.Size():
+			return 1
+		default:
+			return slices.Compare(a.Prefix, b.Prefix)
+		}
+	})
+
+	return stats, nil
+}
+
+type statsCollector struct {
+	tree *tree
+	opts *statsOptions
+
+	stats    TreeStats
+	prefixes map[string]*PrefixStats
+}
+
+func (sc *statsCollector) walk(ctx context.Context, ptr *node.Pointer, depth uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	nd, err := sc.tree.cache.derefNodePtr(ctx, ptr, sc.tree.newFetcherSyncIterate(nil, 0))
+	if err != nil {
+		return err
+	}
+
+	switch n := nd.(type) {
+	case nil:
+		return nil
+	case *node.InternalNode:
+		sc.stats.InternalNodes++
+
+		// The leaf node of an internal node is at the same depth as the internal node.
+		if err = sc.walk(ctx, n.LeafNode, depth); err != nil {
+			return err
+		}
+		if err = sc.walk(ctx, n.Left, depth+1); err != nil {
+			return err
+		}
+		return sc.walk(ctx, n.Right, depth+1)
+	case *node.LeafNode:
+		sc.recordLeaf(n, depth)
+		return nil
+	default:
+		return fmt.Errorf("mkvs: unknown node type %T", n)
+	}
+}
+
+func (sc *statsCollector) recordLeaf(n *node.LeafNode, depth uint64) {
+	keySize := uint64(len(n.Key))
+	valueSize := uint64(len(n.Value))
+
+	sc.stats.LeafNodes++
+	sc.stats.KeyBytes += keySize
+	sc.stats.ValueBytes += valueSize
+
+	sc.stats.MaxDepth = max(sc.stats.MaxDepth, depth)
+	for uint64(len(sc.stats.LeafDepths)) <= depth {
+		sc.stats.LeafDepths = append(sc.stats.LeafDepths, 0)
+	}
+	sc.stats.LeafDepths[depth]++
+
+	prefix := n.Key[:min(len(n.Key), sc.opts.prefixLength)]
+	ps := sc.prefixes[string(prefix)]
+	if ps == nil {
+		ps = &PrefixStats{
+			Prefix: slices.Clone(prefix),
+		}
+		sc.prefixes[string(prefix)] = ps
+	}
+	ps.Keys++
+	ps.KeyBytes += keySize
+	ps.ValueBytes += valueSize
+}
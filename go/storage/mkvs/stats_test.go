@@ -0,0 +1,82 @@
+package mkvs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeStats(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	tree := New(nil, nil, 0)
+	defer tree.Close()
+
+	// Test with an empty tree.
+	stats, err := ComputeStats(ctx, tree)
+	require.NoError(err, "ComputeStats")
+	require.Zero(stats.InternalNodes)
+	require.Zero(stats.LeafNodes)
+	require.Empty(stats.Prefixes)
+
+	// Test with one item.
+	err = tree.Insert(ctx, []byte("a"), []byte("value"))
+	require.NoError(err, "Insert")
+
+	stats, err = ComputeStats(ctx, tree)
+	require.NoError(err, "ComputeStats")
+	require.Zero(stats.InternalNodes)
+	require.EqualValues(1, stats.LeafNodes)
+	require.EqualValues(1, stats.KeyBytes)
+	require.EqualValues(5, stats.ValueBytes)
+	require.EqualValues(0, stats.MaxDepth)
+	require.Equal([]uint64{1}, stats.LeafDepths)
+
+	// Insert items under different prefixes.
+	for i := range 100 {
+		err = tree.Insert(ctx, []byte(fmt.Sprintf("b%03d", i)), []byte("long value"))
+		require.NoError(err, "Insert")
+	}
+	for i := range 10 {
+		err = tree.Insert(ctx, []byte(fmt.Sprintf("c%03d", i)), []byte("v"))
+		require.NoError(err, "Insert")
+	}
+
+	stats, err = ComputeStats(ctx, tree)
+	require.NoError(err, "ComputeStats")
+	require.EqualValues(111, stats.LeafNodes)
+	require.EqualValues(1+100*4+10*4, stats.KeyBytes)
+	require.EqualValues(5+100*10+10, stats.ValueBytes)
+	require.Positive(stats.InternalNodes)
+	require.Len(stats.LeafDepths, int(stats.MaxDepth)+1)
+
+	var numLeaves uint64
+	for _, n := range stats.LeafDepths {
+		numLeaves += n
+	}
+	require.Equal(stats.LeafNodes, numLeaves, "depth histogram should include all leaves")
+
+	require.Len(stats.Prefixes, 3)
+	require.Equal([]byte("b"), stats.Prefixes[0].Prefix, "prefixes should be ordered by size")
+	require.EqualValues(100, stats.Prefixes[0].Keys)
+	require.EqualValues(400, stats.Prefixes[0].KeyBytes)
+	require.EqualValues(1000, stats.Prefixes[0].ValueBytes)
+	require.Equal([]byte("c"), stats.Prefixes[1].Prefix)
+	require.EqualValues(10, stats.Prefixes[1].Keys)
+	require.Equal([]byte("a"), stats.Prefixes[2].Prefix)
+
+	// Test with a longer prefix.
+	stats, err = ComputeStats(ctx, tree, WithStatsPrefixLength(3))
+	require.NoError(err, "ComputeStats")
+	require.Len(stats.Prefixes, 1+10+1)
+
+	_, err = ComputeStats(ctx, tree, WithStatsPrefixLength(-1))
+	require.Error(err, "ComputeStats should fail with an invalid prefix length")
+
+	// Test with a closed tree.
+	tree.Close()
+	_, err = ComputeStats(ctx, tree)
+	require.ErrorIs(err, ErrClosed)
+}
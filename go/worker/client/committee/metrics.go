@@ -0,0 +1,58 @@
+package committee
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/metrics"
+)
+
+const (
+	// labelRuntime is the label for the runtime identifier.
+	labelRuntime = "runtime"
+	// labelMethod is the label for the query method.
+	labelMethod = "method"
+)
+
+var (
+	queryCacheHits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_client_query_cache_hits",
+			Help: "Number of runtime queries served from the query result cache.",
+		},
+		[]string{labelRuntime, labelMethod},
+	)
+	queryCacheMisses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_client_query_cache_misses",
+			Help: "Number of cacheable runtime queries not found in the query result cache.",
+		},
+		[]string{labelRuntime, labelMethod},
+	)
+	queryCacheSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_client_query_cache_size",
+			Help: "Total size of cached runtime query results (bytes).",
+		},
+		[]string{labelRuntime},
+	)
+	nodeCollectors = []prometheus.Collector{
+		queryCacheHits,
+		queryCacheMisses,
+		queryCacheSize,
+	}
+
+	metricsOnce sync.Once
+)
+
+// initMetrics registers the metrics collectors if metrics are enabled.
+func initMetrics() {
+	if !metrics.Enabled() {
+		return
+	}
+
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(nodeCollectors...)
+	})
+}
@@ -2,6 +2,7 @@ package committee
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -12,6 +13,7 @@ import (
 	cmnBackoff "github.com/oasisprotocol/oasis-core/go/common/backoff"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
@@ -47,6 +49,11 @@ type Node struct {
 
 	txCh *channels.InfiniteChannel
 
+	txStatus *txStatusTracker
+
+	// queryCache is the optional query result cache.
+	queryCache *queryCache
+
 	logger *logging.Logger
 }
 
@@ -95,7 +102,7 @@ func (n *Node) HandleRuntimeHostEvent(ev *host.Event) {
 	switch {
 	case ev.Started != nil:
 		n.roleProvider.SetAvailable(n.commonNode.RegisterNodeRuntime)
-	case ev.FailedToStart != nil, ev.Stopped != nil:
+	case ev.FailedToStart != nil, ev.Stopped != nil, ev.CrashLooping != nil:
 		n.roleProvider.SetUnavailable()
 	default:
 	}
@@ -140,16 +147,28 @@ func (n *Node) SubmitTx(ctx context.Context, tx []byte) (*SubmitTxSubscription,
 		return nil, nil, api.ErrNotSynced
 	}
 
+	txHash := hash.NewFromBytes(tx)
+	n.txStatus.track(txHash, api.TransactionStatePending)
+
 	// Submit transaction to the pool and wait for it to get checked.
 	result, err := n.commonNode.TxPool.SubmitTx(ctx, tx, true, false)
 	if err != nil {
+		n.txStatus.untrack(txHash, api.TransactionStatePending)
 		return nil, nil, err
 	}
 	if !result.IsSuccess() {
+		n.txStatus.update(&api.TransactionStatus{
+			Hash:   txHash,
+			State:  api.TransactionStateEvicted,
+			Reason: fmt.Sprintf("check failed: %s", result.Error.String()),
+		})
 		return nil, &result.Error, nil
 	}
+	n.txStatus.update(&api.TransactionStatus{
+		Hash:  txHash,
+		State: api.TransactionStateChecked,
+	})
 
-	txHash := hash.NewFromBytes(tx)
 	ch := make(chan *api.SubmitTxResult, 1)
 	n.txCh.In() <- &wantTx{
 		txHash: txHash,
@@ -164,6 +183,131 @@ func (n *Node) SubmitTx(ctx context.Context, tx []byte) (*SubmitTxSubscription,
 	return sub, nil, nil
 }
 
+// GetTransactionStatus returns the current status of the given transaction.
+//
+// Transactions that are not tracked are reported as queued in case they are in the transaction pool.
+// Otherwise they are looked up in the I/O trees of the last txHistoryLookupRounds blocks.
+func (n *Node) GetTransactionStatus(ctx context.Context, txHash hash.Hash) (*api.TransactionStatus, error) {
+	if st, ok := n.txStatus.get(txHash); ok {
+		return st, nil
+	}
+	if n.commonNode.TxPool.Has(txHash) {
+		return &api.TransactionStatus{
+			Hash:  txHash,
+			State: api.TransactionStateQueued,
+		}, nil
+	}
+	return n.lookupTransaction(ctx, txHash)
+}
+
+// lookupTransaction looks up the given transaction in the I/O trees of recent blocks.
+func (n *Node) lookupTransaction(ctx context.Context, txHash hash.Hash) (*api.TransactionStatus, error) {
+	history := n.commonNode.Runtime.History()
+	latest, err := history.GetBlock(ctx, roothash.RoundLatest)
+	switch {
+	case err == nil:
+	case errors.Is(err, roothash.ErrNotFound):
+		return nil, api.ErrNotFound
+	default:
+		return nil, fmt.Errorf("client: failed to fetch latest block from history: %w", err)
+	}
+
+	for i := uint64(0); i < txHistoryLookupRounds && i <= latest.Header.Round; i++ {
+		blk := latest
+		if i > 0 {
+			blk, err = history.GetBlock(ctx, latest.Header.Round-i)
+			switch {
+			case err == nil:
+			case errors.Is(err, roothash.ErrNotFound):
+				// Block has been pruned or the round has been skipped.
+				continue
+			default:
+				return nil, fmt.Errorf("client: failed to fetch block from history: %w", err)
+			}
+		}
+		if blk.Header.IORoot.IsEmpty() {
+			continue
+		}
+
+		tree := transaction.NewTree(n.commonNode.Runtime.Storage(), blk.Header.StorageRootIO())
+		tx, err := tree.GetTransaction(ctx, txHash)
+		tree.Close()
+		switch {
+		case err == nil:
+			return &api.TransactionStatus{
+				Hash:       txHash,
+				State:      api.TransactionStateIncluded,
+				Round:      blk.Header.Round,
+				BatchOrder: tx.BatchOrder,
+				Output:     tx.Output,
+			}, nil
+		case errors.Is(err, transaction.ErrNotFound):
+		default:
+			return nil, fmt.Errorf("client: failed to get block I/O from storage: %w", err)
+		}
+	}
+	return nil, api.ErrNotFound
+}
+
+// WatchTransactionStatus subscribes to status updates of the given transaction.
+//
+// The current status is sent first and the returned channel is closed once the transaction reaches
+// a terminal state.
+func (n *Node) WatchTransactionStatus(ctx context.Context, txHash hash.Hash) (<-chan *api.TransactionStatus, pubsub.ClosableSubscription, error) {
+	// Start tracking transactions that are only known to the transaction pool so that further
+	// updates can be reported.
+	if _, ok := n.txStatus.get(txHash); !ok && n.commonNode.TxPool.Has(txHash) {
+		n.txStatus.track(txHash, api.TransactionStateQueued)
+	}
+
+	// Subscribe before fetching the current status so no updates are missed.
+	updateCh, updateSub := n.txStatus.watch()
+	current, err := n.GetTransactionStatus(ctx, txHash)
+	if err != nil {
+		updateSub.Close()
+		return nil, nil, err
+	}
+
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+	ch := make(chan *api.TransactionStatus)
+	go func() {
+		defer close(ch)
+		defer updateSub.Close()
+
+		st := current
+		for {
+			select {
+			case ch <- st:
+			case <-ctx.Done():
+				return
+			}
+			if st.State.IsTerminal() {
+				return
+			}
+
+			// Wait for the next update, skipping updates for other transactions and stale updates
+			// broadcast before subscribing.
+			var next *api.TransactionStatus
+			for next == nil {
+				select {
+				case update, ok := <-updateCh:
+					if !ok {
+						return
+					}
+					if update.Hash == txHash && isNewerTxStatus(update, st) {
+						next = update
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+			st = next
+		}
+	}()
+
+	return ch, sub, nil
+}
+
 func (n *Node) CheckTx(ctx context.Context, tx []byte) (*protocol.CheckTxResult, error) {
 	return n.commonNode.TxPool.SubmitTx(ctx, tx, true, true)
 }
@@ -185,6 +329,25 @@ func (n *Node) Query(ctx context.Context, round uint64, method string, args []by
 		return nil, fmt.Errorf("client: failed to fetch annotated block from history: %w", err)
 	}
 
+	// Route to correct component -- an empty component implies RONL.
+	if comp == nil {
+		comp = &component.ID_RONL
+	}
+
+	// Serve cacheable queries from the cache when possible.
+	var cacheKey *queryCacheKey
+	if n.queryCache != nil && n.queryCache.isCacheable(method) {
+		cacheKey = &queryCacheKey{
+			round:     annBlk.Block.Header.Round,
+			component: *comp,
+			method:    method,
+			args:      hash.NewFromBytes(args),
+		}
+		if data, ok := n.queryCache.get(*cacheKey); ok {
+			return data, nil
+		}
+	}
+
 	lb, err := n.commonNode.Consensus.Core().GetLightBlock(ctx, annBlk.Height)
 	if err != nil {
 		return nil, fmt.Errorf("client: failed to get light block at height %d: %w", annBlk.Height, err)
@@ -194,38 +357,47 @@ func (n *Node) Query(ctx context.Context, round uint64, method string, args []by
 		return nil, fmt.Errorf("client: failed to get epoch at height %d: %w", annBlk.Height, err)
 	}
 
-	// Route to correct component -- an empty component implies RONL.
-	if comp == nil {
-		comp = &component.ID_RONL
-	}
 	rt, ok := hrt.Component(*comp)
 	if !ok {
 		return nil, fmt.Errorf("component '%s' not found", comp)
 	}
 	dst := host.NewRichRuntime(rt)
 
-	return dst.Query(ctx, annBlk.Block, lb, epoch, maxMessages, method, args)
+	data, err := dst.Query(ctx, annBlk.Block, lb, epoch, maxMessages, method, args)
+	if err != nil {
+		return nil, err
+	}
+	if cacheKey != nil {
+		n.queryCache.put(*cacheKey, data)
+	}
+	return data, nil
 }
 
 func (n *Node) checkBlock(ctx context.Context, blk *block.Block, pending map[hash.Hash]*pendingTx) error {
+	// Stop tracking transactions that have been in flight for too long so that the number of
+	// lookups per block stays bounded.
+	n.txStatus.expire(time.Now())
+
 	if blk.Header.IORoot.IsEmpty() {
 		return nil
 	}
 
+	// Check if there's anything interesting in this block.
+	txHashes := n.txStatus.pending()
+	for txHash := range pending {
+		if !n.txStatus.isPending(txHash) {
+			txHashes = append(txHashes, txHash)
+		}
+	}
+
 	// If there's no pending transactions, we can skip the check.
-	if len(pending) == 0 {
+	if len(txHashes) == 0 {
 		return nil
 	}
 
 	tree := transaction.NewTree(n.commonNode.Runtime.Storage(), blk.Header.StorageRootIO())
 	defer tree.Close()
 
-	// Check if there's anything interesting in this block.
-	var txHashes []hash.Hash
-	for txHash := range pending {
-		txHashes = append(txHashes, txHash)
-	}
-
 	matches, err := tree.GetTransactionMultiple(ctx, txHashes)
 	if err != nil {
 		return fmt.Errorf("error getting block I/O from storage: %w", err)
@@ -233,7 +405,20 @@ func (n *Node) checkBlock(ctx context.Context, blk *block.Block, pending map[has
 
 	var processed []hash.Hash
 	for txHash, tx := range matches {
-		pTx := pending[txHash]
+		n.txStatus.update(&api.TransactionStatus{
+			Hash:       txHash,
+			State:      api.TransactionStateIncluded,
+			Round:      blk.Header.Round,
+			BatchOrder: tx.BatchOrder,
+			Output:     tx.Output,
+		})
+
+		processed = append(processed, txHash)
+
+		pTx, ok := pending[txHash]
+		if !ok {
+			continue
+		}
 		for ch := range pTx.chs {
 			ch <- &api.SubmitTxResult{
 				Result: &api.SubmitTxMetaResponse{
@@ -245,7 +430,6 @@ func (n *Node) checkBlock(ctx context.Context, blk *block.Block, pending map[has
 			close(ch)
 		}
 		delete(pending, txHash)
-		processed = append(processed, txHash)
 	}
 
 	// Remove processed transactions from pool.
@@ -281,6 +465,10 @@ func (n *Node) worker() {
 	}
 	defer blkSub.Close()
 
+	// Subscribe to transaction pool events to track the status of submitted transactions.
+	txEvCh, txEvSub := n.commonNode.TxPool.WatchTxEvents()
+	defer txEvSub.Close()
+
 	// We are initialized.
 	close(n.initCh)
 
@@ -326,7 +514,13 @@ func (n *Node) worker() {
 				}
 			}
 			continue
+		case evs := <-txEvCh:
+			n.txStatus.handleTxEvents(evs)
+			continue
 		case blk := <-blkCh:
+			if n.queryCache != nil {
+				n.queryCache.prune(blk.Block.Header.Round)
+			}
 			blocks = append(blocks, blk.Block)
 		case <-recheckCh:
 		}
@@ -362,7 +556,15 @@ func (n *Node) worker() {
 }
 
 // NewNode creates a new client node.
-func NewNode(commonNode *committee.Node, roleProvider registration.RoleProvider) (*Node, error) {
+//
+// In case the query cache is enabled in the given configuration, results of the allowed query
+// methods are cached.
+func NewNode(commonNode *committee.Node, roleProvider registration.RoleProvider, queryCacheCfg *QueryCacheConfig) (*Node, error) {
+	var qc *queryCache
+	if queryCacheCfg != nil && queryCacheCfg.Enabled {
+		qc = newQueryCache(commonNode.Runtime.ID(), queryCacheCfg.MaxSize, queryCacheCfg.Methods)
+	}
+
 	n := &Node{
 		commonNode:   commonNode,
 		roleProvider: roleProvider,
@@ -370,6 +572,8 @@ func NewNode(commonNode *committee.Node, roleProvider registration.RoleProvider)
 		quitCh:       make(chan struct{}),
 		initCh:       make(chan struct{}),
 		txCh:         channels.NewInfiniteChannel(),
+		txStatus:     newTxStatusTracker(),
+		queryCache:   qc,
 		logger:       logging.GetLogger("worker/client/committee").With("runtime_id", commonNode.Runtime.ID()),
 	}
 	return n, nil
@@ -0,0 +1,103 @@
+package committee
+
+import (
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cache/lru"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+// defaultQueryCacheSize is the default maximum total size of cached query results.
+const defaultQueryCacheSize = 64 * 1024 * 1024
+
+// QueryCacheConfig is the query result cache configuration.
+type QueryCacheConfig struct {
+	// Enabled specifies whether query results should be cached.
+	Enabled bool
+	// MaxSize is the maximum total size of cached query results in bytes. Zero uses a default.
+	MaxSize uint64
+	// Methods is the list of query methods whose results can be cached.
+	Methods []string
+}
+
+// queryCacheKey identifies a cached query result.
+type queryCacheKey struct {
+	round     uint64
+	component component.ID
+	method    string
+	args      hash.Hash
+}
+
+// queryCacheEntry is a cached query result.
+type queryCacheEntry struct {
+	data []byte
+}
+
+// Size implements lru.Sizeable.
+func (e *queryCacheEntry) Size() uint64 {
+	return uint64(len(e.data))
+}
+
+// queryCache is a size-bounded cache of runtime query results.
+//
+// Query results are deterministic for a given round so cached results never become stale, but
+// results for rounds older than the latest block are dropped as they are rarely queried again.
+type queryCache struct {
+	methods map[string]struct{}
+	cache   *lru.Cache
+
+	runtimeID string
+}
+
+func newQueryCache(runtimeID common.Namespace, maxSize uint64, methods []string) *queryCache {
+	if maxSize == 0 {
+		maxSize = defaultQueryCacheSize
+	}
+
+	qc := &queryCache{
+		methods:   make(map[string]struct{}, len(methods)),
+		cache:     lru.New(lru.Capacity(maxSize, true)),
+		runtimeID: runtimeID.String(),
+	}
+	for _, method := range methods {
+		qc.methods[method] = struct{}{}
+	}
+
+	initMetrics()
+
+	return qc
+}
+
+// isCacheable returns true iff results of the given query method can be cached.
+func (qc *queryCache) isCacheable(method string) bool {
+	_, ok := qc.methods[method]
+	return ok
+}
+
+// get returns the cached result of the given query.
+func (qc *queryCache) get(key queryCacheKey) ([]byte, bool) {
+	v, ok := qc.cache.Get(key)
+	if !ok {
+		queryCacheMisses.WithLabelValues(qc.runtimeID, key.method).Inc()
+		return nil, false
+	}
+	queryCacheHits.WithLabelValues(qc.runtimeID, key.method).Inc()
+	return v.(*queryCacheEntry).data, true
+}
+
+// put caches the result of the given query.
+func (qc *queryCache) put(key queryCacheKey, data []byte) {
+	// Results larger than the cache capacity are not cached.
+	_ = qc.cache.Put(key, &queryCacheEntry{data: data})
+	queryCacheSize.WithLabelValues(qc.runtimeID).Set(float64(qc.cache.Size()))
+}
+
+// prune removes cached results for all rounds before the given round.
+func (qc *queryCache) prune(round uint64) {
+	for _, k := range qc.cache.Keys() {
+		if k.(queryCacheKey).round < round {
+			qc.cache.Remove(k)
+		}
+	}
+	queryCacheSize.WithLabelValues(qc.runtimeID).Set(float64(qc.cache.Size()))
+}
@@ -0,0 +1,67 @@
+package committee
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+func TestQueryCache(t *testing.T) {
+	require := require.New(t)
+
+	qc := newQueryCache(common.Namespace{}, 16, []string{"balance"})
+	require.True(qc.isCacheable("balance"))
+	require.False(qc.isCacheable("nonce"))
+
+	key := func(round uint64, args string) queryCacheKey {
+		return queryCacheKey{
+			round:     round,
+			component: component.ID_RONL,
+			method:    "balance",
+			args:      hash.NewFromBytes([]byte(args)),
+		}
+	}
+
+	_, ok := qc.get(key(1, "alice"))
+	require.False(ok)
+
+	qc.put(key(1, "alice"), []byte("10"))
+	qc.put(key(2, "alice"), []byte("20"))
+	qc.put(key(2, "bob"), []byte("30"))
+
+	data, ok := qc.get(key(1, "alice"))
+	require.True(ok)
+	require.Equal([]byte("10"), data)
+	data, ok = qc.get(key(2, "bob"))
+	require.True(ok)
+	require.Equal([]byte("30"), data)
+
+	// Results for other components are cached separately.
+	rofl := key(2, "bob")
+	rofl.component = component.ID{Kind: component.ROFL}
+	_, ok = qc.get(rofl)
+	require.False(ok)
+
+	// Results larger than the capacity are not cached.
+	qc.put(key(2, "carol"), make([]byte, 17))
+	_, ok = qc.get(key(2, "carol"))
+	require.False(ok)
+
+	// Exceeding the capacity evicts the least recently used results.
+	qc.put(key(2, "dave"), make([]byte, 12))
+	_, ok = qc.get(key(2, "alice"))
+	require.False(ok)
+	_, ok = qc.get(key(2, "dave"))
+	require.True(ok)
+
+	// New blocks invalidate results for earlier rounds.
+	qc.put(key(2, "alice"), []byte("20"))
+	qc.prune(3)
+	_, ok = qc.get(key(2, "alice"))
+	require.False(ok)
+	require.Zero(qc.cache.Size())
+}
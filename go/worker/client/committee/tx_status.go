@@ -0,0 +1,240 @@
+package committee
+
+import (
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cache/lru"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
+)
+
+const (
+	// maxTrackedTxs is the maximum number of transactions for which the status is tracked.
+	maxTrackedTxs = 10_000
+	// maxInFlightTxs is the maximum number of tracked transactions that have not yet reached a
+	// terminal state. These are looked up in the I/O tree of every new block.
+	maxInFlightTxs = 1_000
+	// inFlightTxExpiry is the time after which tracking of transactions that have not yet reached
+	// a terminal state is stopped.
+	inFlightTxExpiry = 10 * time.Minute
+	// txHistoryLookupRounds is the number of most recent blocks in which transactions that are
+	// not tracked are looked up.
+	txHistoryLookupRounds = 100
+)
+
+// txStatusTracker tracks the status of transactions through their lifecycle.
+type txStatusTracker struct {
+	mu sync.Mutex
+
+	// statuses maps transaction hashes to their current status.
+	statuses *lru.Cache
+	// inFlight maps tracked transactions that have not yet reached a terminal state to the time
+	// they started being tracked.
+	inFlight map[hash.Hash]time.Time
+
+	notifier *pubsub.Broker
+}
+
+func newTxStatusTracker() *txStatusTracker {
+	t := &txStatusTracker{
+		inFlight: make(map[hash.Hash]time.Time),
+		notifier: pubsub.NewBroker(false),
+	}
+	t.statuses = lru.New(
+		lru.Capacity(maxTrackedTxs, false),
+		lru.OnEvict(func(key, _ any) {
+			// Called with the tracker lock held as entries are only evicted on insertion.
+			delete(t.inFlight, key.(hash.Hash))
+		}),
+	)
+	return t
+}
+
+// track starts tracking the given transaction in the given state unless the transaction is already
+// being tracked. Transactions that were previously expired or evicted are tracked again as they
+// may have been resubmitted.
+func (t *txStatusTracker) track(txHash hash.Hash, state api.TransactionState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if v, ok := t.statuses.Peek(txHash); ok {
+		st := v.(*api.TransactionStatus)
+		if !st.State.IsTerminal() || st.State == api.TransactionStateIncluded {
+			return
+		}
+	}
+
+	t.setLocked(&api.TransactionStatus{
+		Hash:  txHash,
+		State: state,
+	})
+}
+
+// untrack stops tracking the given transaction in case it is still in the given state.
+func (t *txStatusTracker) untrack(txHash hash.Hash, state api.TransactionState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	v, ok := t.statuses.Peek(txHash)
+	if !ok || v.(*api.TransactionStatus).State != state {
+		return
+	}
+	t.statuses.Remove(txHash)
+	delete(t.inFlight, txHash)
+}
+
+// update updates the status of a tracked transaction. Updates for untracked transactions and
+// updates that would move the transaction to an earlier state are ignored. Terminal states are
+// final, except for inclusion in a block which always takes precedence.
+func (t *txStatusTracker) update(st *api.TransactionStatus) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	v, ok := t.statuses.Peek(st.Hash)
+	if !ok {
+		return
+	}
+	if !isNewerTxStatus(st, v.(*api.TransactionStatus)) {
+		return
+	}
+
+	t.setLocked(st)
+}
+
+// isNewerTxStatus returns true iff the given status update supersedes the current status.
+func isNewerTxStatus(st, current *api.TransactionStatus) bool {
+	switch {
+	case current.State == api.TransactionStateIncluded:
+		return false
+	case st.State == api.TransactionStateIncluded:
+		return true
+	case current.State.IsTerminal():
+		return false
+	case st.State != current.State:
+		return st.State > current.State
+	default:
+		return st.Round > current.Round
+	}
+}
+
+func (t *txStatusTracker) setLocked(st *api.TransactionStatus) {
+	// Put cannot fail as the LRU capacity is not in bytes.
+	_ = t.statuses.Put(st.Hash, st)
+	switch _, ok := t.inFlight[st.Hash]; {
+	case st.State.IsTerminal():
+		delete(t.inFlight, st.Hash)
+	case !ok:
+		if len(t.inFlight) >= maxInFlightTxs {
+			t.removeOldestInFlightLocked()
+		}
+		t.inFlight[st.Hash] = time.Now()
+	}
+
+	t.notifier.Broadcast(st)
+}
+
+// removeOldestInFlightLocked stops tracking the transaction that has been in flight the longest.
+func (t *txStatusTracker) removeOldestInFlightLocked() {
+	var (
+		oldest     hash.Hash
+		oldestTime time.Time
+	)
+	for txHash, ts := range t.inFlight {
+		if oldestTime.IsZero() || ts.Before(oldestTime) {
+			oldest, oldestTime = txHash, ts
+		}
+	}
+	t.statuses.Remove(oldest)
+	delete(t.inFlight, oldest)
+}
+
+// expire stops tracking transactions that have been in flight for longer than inFlightTxExpiry.
+//
+// The status of such transactions is no longer known to the tracker and needs to be looked up
+// from the transaction pool or the block history instead.
+func (t *txStatusTracker) expire(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for txHash, ts := range t.inFlight {
+		if now.Sub(ts) < inFlightTxExpiry {
+			continue
+		}
+		t.statuses.Remove(txHash)
+		delete(t.inFlight, txHash)
+	}
+}
+
+// handleTxEvents updates the status of tracked transactions based on transaction pool events.
+func (t *txStatusTracker) handleTxEvents(events []*txpool.TxEvent) {
+	for _, ev := range events {
+		st := &api.TransactionStatus{
+			Hash: ev.Hash,
+		}
+
+		switch ev.Kind {
+		case txpool.TxEventQueued:
+			st.State = api.TransactionStateQueued
+		case txpool.TxEventScheduled:
+			st.State = api.TransactionStateScheduled
+			st.Round = ev.Round
+		case txpool.TxEventExpired:
+			st.State = api.TransactionStateExpired
+			st.Reason = ev.Reason
+		case txpool.TxEventEvicted:
+			st.State = api.TransactionStateEvicted
+			st.Reason = ev.Reason
+		default:
+			continue
+		}
+
+		t.update(st)
+	}
+}
+
+// get returns the status of a tracked transaction.
+func (t *txStatusTracker) get(txHash hash.Hash) (*api.TransactionStatus, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	v, ok := t.statuses.Peek(txHash)
+	if !ok {
+		return nil, false
+	}
+	st := *v.(*api.TransactionStatus)
+	return &st, true
+}
+
+// pending returns the hashes of all tracked transactions that have not yet reached a terminal
+// state.
+func (t *txStatusTracker) pending() []hash.Hash {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	txHashes := make([]hash.Hash, 0, len(t.inFlight))
+	for txHash := range t.inFlight {
+		txHashes = append(txHashes, txHash)
+	}
+	return txHashes
+}
+
+// isPending returns true iff the given transaction is tracked and has not yet reached a terminal
+// state.
+func (t *txStatusTracker) isPending(txHash hash.Hash) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok := t.inFlight[txHash]
+	return ok
+}
+
+// watch subscribes to status updates of all tracked transactions.
+func (t *txStatusTracker) watch() (<-chan *api.TransactionStatus, *pubsub.Subscription) {
+	sub := t.notifier.Subscribe()
+	ch := make(chan *api.TransactionStatus)
+	sub.Unwrap(ch)
+	return ch, sub
+}
@@ -0,0 +1,133 @@
+package committee
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
+)
+
+func TestTxStatusTracker(t *testing.T) {
+	require := require.New(t)
+
+	tr := newTxStatusTracker()
+	txA := hash.NewFromBytes([]byte("tx a"))
+	txB := hash.NewFromBytes([]byte("tx b"))
+
+	// Updates for untracked transactions should be ignored.
+	tr.update(&api.TransactionStatus{Hash: txA, State: api.TransactionStateChecked})
+	_, ok := tr.get(txA)
+	require.False(ok)
+
+	tr.track(txA, api.TransactionStatePending)
+	tr.track(txB, api.TransactionStatePending)
+	require.ElementsMatch([]hash.Hash{txA, txB}, tr.pending())
+
+	// Submission failure before the check should stop tracking.
+	tr.untrack(txB, api.TransactionStatePending)
+	_, ok = tr.get(txB)
+	require.False(ok)
+
+	// Events may arrive before the submitter marks the transaction as checked.
+	tr.handleTxEvents([]*txpool.TxEvent{{Kind: txpool.TxEventQueued, Hash: txA}})
+	tr.update(&api.TransactionStatus{Hash: txA, State: api.TransactionStateChecked})
+	st, ok := tr.get(txA)
+	require.True(ok)
+	require.Equal(api.TransactionStateQueued, st.State)
+
+	// Rescheduling in a later round should be reported.
+	tr.handleTxEvents([]*txpool.TxEvent{
+		{Kind: txpool.TxEventScheduled, Hash: txA, Round: 10},
+		{Kind: txpool.TxEventScheduled, Hash: txA, Round: 12},
+		{Kind: txpool.TxEventQueued, Hash: txA},
+	})
+	st, _ = tr.get(txA)
+	require.Equal(api.TransactionStateScheduled, st.State)
+	require.EqualValues(12, st.Round)
+
+	ch, sub := tr.watch()
+	defer sub.Close()
+
+	// Eviction is terminal.
+	tr.handleTxEvents([]*txpool.TxEvent{{Kind: txpool.TxEventEvicted, Hash: txA, Reason: "rejected"}})
+	st, _ = tr.get(txA)
+	require.Equal(api.TransactionStateEvicted, st.State)
+	require.Equal("rejected", st.Reason)
+	require.Empty(tr.pending())
+
+	tr.handleTxEvents([]*txpool.TxEvent{{Kind: txpool.TxEventQueued, Hash: txA}})
+	st, _ = tr.get(txA)
+	require.Equal(api.TransactionStateEvicted, st.State)
+
+	// Inclusion in a block always takes precedence.
+	tr.update(&api.TransactionStatus{
+		Hash:       txA,
+		State:      api.TransactionStateIncluded,
+		Round:      13,
+		BatchOrder: 2,
+		Output:     []byte("output"),
+	})
+	st, _ = tr.get(txA)
+	require.Equal(api.TransactionStateIncluded, st.State)
+	require.EqualValues(13, st.Round)
+	require.EqualValues(2, st.BatchOrder)
+	require.Equal([]byte("output"), st.Output)
+
+	// Included transactions are not tracked again.
+	tr.track(txA, api.TransactionStatePending)
+	st, _ = tr.get(txA)
+	require.Equal(api.TransactionStateIncluded, st.State)
+
+	// Updates broadcast before subscribing may still be delivered.
+	var states []api.TransactionState
+	for len(states) == 0 || states[len(states)-1] != api.TransactionStateIncluded {
+		update := <-ch
+		if update.Hash != txA {
+			continue
+		}
+		states = append(states, update.State)
+	}
+	require.Subset(states, []api.TransactionState{api.TransactionStateEvicted, api.TransactionStateIncluded})
+}
+
+func TestTxStatusTrackerInFlightLimits(t *testing.T) {
+	require := require.New(t)
+
+	tr := newTxStatusTracker()
+	txHash := func(i int) hash.Hash {
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], uint64(i))
+		return hash.NewFromBytes(b[:])
+	}
+
+	// The number of in-flight transactions should be bounded, dropping the oldest ones.
+	for i := 0; i < maxInFlightTxs+1; i++ {
+		tr.track(txHash(i), api.TransactionStatePending)
+		tr.inFlight[txHash(i)] = time.Unix(int64(i), 0)
+	}
+	require.Len(tr.pending(), maxInFlightTxs)
+	require.False(tr.isPending(txHash(0)))
+	_, ok := tr.get(txHash(0))
+	require.False(ok, "dropped transactions should no longer be tracked")
+	require.True(tr.isPending(txHash(maxInFlightTxs)))
+
+	// Transactions in a terminal state should not be affected by expiry.
+	tr.update(&api.TransactionStatus{Hash: txHash(1), State: api.TransactionStateIncluded, Round: 1})
+
+	// In-flight transactions should expire.
+	tr.expire(time.Unix(maxInFlightTxs/2, 0).Add(inFlightTxExpiry))
+	require.Len(tr.pending(), maxInFlightTxs/2)
+	_, ok = tr.get(txHash(2))
+	require.False(ok, "expired transactions should no longer be tracked")
+	st, ok := tr.get(txHash(1))
+	require.True(ok)
+	require.Equal(api.TransactionStateIncluded, st.State)
+
+	tr.expire(time.Now().Add(inFlightTxExpiry))
+	require.Empty(tr.pending())
+}
@@ -107,6 +107,26 @@ func (s *service) CheckTx(ctx context.Context, request *api.CheckTxRequest) erro
 	return nil
 }
 
+// Implements api.RuntimeClient.
+func (s *service) GetTransactionStatus(ctx context.Context, request *api.GetTransactionStatusRequest) (*api.TransactionStatus, error) {
+	rt := s.w.runtimes[request.RuntimeID]
+	if rt == nil {
+		return nil, api.ErrNoHostedRuntime
+	}
+
+	return rt.GetTransactionStatus(ctx, request.TxHash)
+}
+
+// Implements api.RuntimeClient.
+func (s *service) WatchTransactionStatus(ctx context.Context, request *api.GetTransactionStatusRequest) (<-chan *api.TransactionStatus, pubsub.ClosableSubscription, error) {
+	rt := s.w.runtimes[request.RuntimeID]
+	if rt == nil {
+		return nil, nil, api.ErrNoHostedRuntime
+	}
+
+	return rt.WatchTransactionStatus(ctx, request.TxHash)
+}
+
 // Implements api.RuntimeClient.
 func (s *service) WatchBlocks(_ context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
 	rt, err := s.w.commonWorker.RuntimeRegistry.GetRuntime(runtimeID)
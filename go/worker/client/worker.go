@@ -150,7 +150,12 @@ func (w *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 	}
 
 	// Create committee node for the given runtime.
-	node, err := committee.NewNode(commonNode, rp)
+	qcCfg := config.GlobalConfig.Runtime.GetQueryCacheConfig(id)
+	node, err := committee.NewNode(commonNode, rp, &committee.QueryCacheConfig{
+		Enabled: qcCfg.Enabled,
+		MaxSize: uint64(config.ParseSizeInBytes(qcCfg.MaxSize)),
+		Methods: qcCfg.Methods,
+	})
 	if err != nil {
 		return err
 	}
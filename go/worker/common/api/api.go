@@ -27,6 +27,8 @@ const (
 	StatusStateWaitingWorkersInit StatusState = 6
 	// StatusStateRuntimeSuspended is the runtime suspended status state.
 	StatusStateRuntimeSuspended StatusState = 7
+	// StatusStateRuntimeCrashLooping is the runtime crash looping status state.
+	StatusStateRuntimeCrashLooping StatusState = 8
 )
 
 // String returns a string representation of a status state.
@@ -48,6 +50,8 @@ func (s StatusState) String() string {
 		return "waiting for workers to initialize"
 	case StatusStateRuntimeSuspended:
 		return "runtime suspended"
+	case StatusStateRuntimeCrashLooping:
+		return "runtime crash looping"
 	default:
 		return "[invalid status state]"
 	}
@@ -72,6 +76,8 @@ func (s StatusState) MarshalText() ([]byte, error) {
 		return []byte(StatusStateWaitingWorkersInit.String()), nil
 	case StatusStateRuntimeSuspended:
 		return []byte(StatusStateRuntimeSuspended.String()), nil
+	case StatusStateRuntimeCrashLooping:
+		return []byte(StatusStateRuntimeCrashLooping.String()), nil
 	default:
 		return nil, fmt.Errorf("invalid StatusState: %d", s)
 	}
@@ -96,6 +102,8 @@ func (s *StatusState) UnmarshalText(text []byte) error {
 		*s = StatusStateWaitingWorkersInit
 	case StatusStateRuntimeSuspended.String():
 		*s = StatusStateRuntimeSuspended
+	case StatusStateRuntimeCrashLooping.String():
+		*s = StatusStateRuntimeCrashLooping
 	default:
 		return fmt.Errorf("invalid StatusState: %s", string(text))
 	}
@@ -134,6 +142,13 @@ type Status struct {
 type HostStatus struct {
 	// Versions are the locally supported versions.
 	Versions []version.Version `json:"versions"`
+
+	// Crashes is the number of times the runtime has terminated unexpectedly or failed to start.
+	Crashes uint64 `json:"crashes,omitempty"`
+	// LastCrashError is the error that caused the last crash.
+	LastCrashError string `json:"last_crash_error,omitempty"`
+	// CrashLooping is true iff the runtime is no longer being restarted after repeatedly crashing.
+	CrashLooping bool `json:"crash_looping,omitempty"`
 }
 
 // LivenessStatus is the liveness status for the current epoch.
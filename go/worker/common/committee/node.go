@@ -93,11 +93,15 @@ type Node struct {
 	historyReindexingDone     uint32
 	workersInitialized        uint32
 	runtimeSuspended          uint32
+	runtimeCrashLooping       uint32
 
 	mu           sync.Mutex
 	latestRound  uint64
 	latestHeight int64
 
+	runtimeCrashes        uint64
+	runtimeLastCrashError string
+
 	committeeRound   uint64
 	lastBlockInfo    *runtime.BlockInfo
 	dispatchInfoCh   chan struct{}
@@ -122,6 +126,9 @@ func (n *Node) getStatusState() api.StatusState {
 	if atomic.LoadUint32(&n.workersInitialized) == 0 {
 		return api.StatusStateWaitingWorkersInit
 	}
+	if atomic.LoadUint32(&n.runtimeCrashLooping) == 1 {
+		return api.StatusStateRuntimeCrashLooping
+	}
 	if atomic.LoadUint32(&n.hostedRuntimeProvisioned) == 0 {
 		return api.StatusStateWaitingHostedRuntime
 	}
@@ -186,6 +193,9 @@ func (n *Node) GetStatus() (*api.Status, error) {
 		LatestHeight:  n.latestHeight,
 		SchedulerRank: math.MaxUint64,
 	}
+	status.Host.Crashes = n.runtimeCrashes
+	status.Host.LastCrashError = n.runtimeLastCrashError
+	status.Host.CrashLooping = atomic.LoadUint32(&n.runtimeCrashLooping) == 1
 	n.mu.Unlock()
 
 	switch activeVersion, err := n.GetHostedRuntime().GetActiveVersion(); err {
@@ -292,8 +302,20 @@ func (n *Node) handleRuntimeHostEvent(ev *host.Event) {
 	switch {
 	case ev.Started != nil:
 		atomic.StoreUint32(&n.hostedRuntimeProvisioned, 1)
-	case ev.FailedToStart != nil, ev.Stopped != nil:
+		atomic.StoreUint32(&n.runtimeCrashLooping, 0)
+	case ev.FailedToStart != nil:
 		atomic.StoreUint32(&n.hostedRuntimeProvisioned, 0)
+		n.recordRuntimeCrash(ev.FailedToStart.Error)
+	case ev.Stopped != nil:
+		atomic.StoreUint32(&n.hostedRuntimeProvisioned, 0)
+		if ev.Stopped.Error != nil {
+			n.recordRuntimeCrash(ev.Stopped.Error)
+		}
+	case ev.CrashLooping != nil:
+		// The runtime will not be restarted until an operator intervenes, so make sure the node
+		// does not register for the runtime until then.
+		atomic.StoreUint32(&n.hostedRuntimeProvisioned, 0)
+		atomic.StoreUint32(&n.runtimeCrashLooping, 1)
 	}
 
 	for _, hooks := range n.hooks {
@@ -301,6 +323,16 @@ func (n *Node) handleRuntimeHostEvent(ev *host.Event) {
 	}
 }
 
+func (n *Node) recordRuntimeCrash(err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.runtimeCrashes++
+	if err != nil {
+		n.runtimeLastCrashError = err.Error()
+	}
+}
+
 func (n *Node) worker() { //nolint: gocyclo
 	n.logger.Info("starting committee node")
 
@@ -703,7 +735,10 @@ func NewNode(
 	n.services = service.NewGroup(notifier, lbNotifier, kmNotifier, n.roflNotifier)
 
 	// Prepare transaction pool.
-	n.TxPool = txpool.New(runtime.ID(), cfg.TxPool, rhn.GetHostedRuntime(), runtime.History(), n)
+	n.TxPool, err = txpool.New(runtime.ID(), runtime.DataDir(), cfg.TxPool, rhn.GetHostedRuntime(), runtime.History(), n)
+	if err != nil {
+		return nil, err
+	}
 
 	// Register transaction message handler as that is something that all workers must handle.
 	p2pHost.RegisterHandler(txTopic, &txMsgHandler{n})
@@ -3,6 +3,7 @@ package committee
 import (
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/composite"
 	runtimeKeymanager "github.com/oasisprotocol/oasis-core/go/runtime/keymanager/api"
 	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
 	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
@@ -41,3 +42,8 @@ func (env *nodeEnvironment) GetRuntimeRegistry() runtimeRegistry.Registry {
 func (env *nodeEnvironment) GetROFLNotifier() (*runtimeRegistry.ROFLNotifier, error) {
 	return env.n.roflNotifier, nil
 }
+
+// GetHostedRuntime implements RuntimeHostHandlerEnvironment.
+func (env *nodeEnvironment) GetHostedRuntime() *composite.Host {
+	return env.n.GetHostedRuntime()
+}
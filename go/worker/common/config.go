@@ -1,10 +1,15 @@
 package common
 
 import (
+	"github.com/oasisprotocol/oasis-core/go/common"
 	tpConfig "github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
 )
 
 // Config contains common worker config.
 type Config struct {
 	TxPool tpConfig.Config
+
+	// RuntimeTxPool contains per-runtime transaction pool configuration which takes precedence
+	// over the common transaction pool configuration.
+	RuntimeTxPool map[common.Namespace]tpConfig.Config
 }
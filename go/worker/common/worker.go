@@ -146,10 +146,15 @@ func (w *Worker) registerRuntime(runtime runtimeRegistry.Runtime) error {
 		"runtime_id", id,
 	)
 
+	txPoolCfg, ok := w.cfg.RuntimeTxPool[id]
+	if !ok {
+		txPoolCfg = w.cfg.TxPool
+	}
+
 	cfg := committee.Config{
 		ChainContext: w.ChainContext,
 		Identity:     w.Identity,
-		TxPool:       w.cfg.TxPool,
+		TxPool:       txPoolCfg,
 	}
 
 	node, err := committee.NewNode(
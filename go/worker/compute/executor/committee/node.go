@@ -1041,7 +1041,7 @@ func (n *Node) HandleRuntimeHostEvent(ev *host.Event) {
 	case ev.Updated != nil:
 		// Update runtime capabilities.
 		n.runtimeReady = true
-	case ev.FailedToStart != nil, ev.Stopped != nil:
+	case ev.FailedToStart != nil, ev.Stopped != nil, ev.CrashLooping != nil:
 		// Runtime failed to start or was stopped -- we can no longer service requests.
 		n.runtimeReady = false
 
@@ -5,6 +5,7 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/composite"
 	runtimeKeymanager "github.com/oasisprotocol/oasis-core/go/runtime/keymanager/api"
 	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
 	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
@@ -43,3 +44,8 @@ func (env *workerEnvironment) GetRuntimeRegistry() runtimeRegistry.Registry {
 func (env *workerEnvironment) GetROFLNotifier() (*runtimeRegistry.ROFLNotifier, error) {
 	return nil, fmt.Errorf("method not supported")
 }
+
+// GetHostedRuntime implements RuntimeHostHandlerEnvironment.
+func (env *workerEnvironment) GetHostedRuntime() *composite.Host {
+	return env.w.GetHostedRuntime()
+}
@@ -371,7 +371,7 @@ func (w *Worker) handleRuntimeHostEvent(ev *host.Event) {
 			)
 			return nil
 		})
-	case ev.FailedToStart != nil, ev.Stopped != nil:
+	case ev.FailedToStart != nil, ev.Stopped != nil, ev.CrashLooping != nil:
 		// We can no longer service requests.
 		w.roleProvider.SetUnavailable()
 	default:
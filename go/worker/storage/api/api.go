@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
@@ -66,4 +67,24 @@ type Status struct {
 
 	// LastFinalizedRound is the last synced and finalized round.
 	LastFinalizedRound uint64 `json:"last_finalized_round"`
+
+	// CheckpointSync is the progress of the checkpoint currently being restored, in case the
+	// storage worker is syncing checkpoints.
+	CheckpointSync *CheckpointSyncProgress `json:"checkpoint_sync,omitempty"`
+}
+
+// CheckpointSyncProgress is the progress of a checkpoint restore.
+type CheckpointSyncProgress struct {
+	// Root is the root of the checkpoint being restored.
+	Root storage.Root `json:"root"`
+
+	// ChunksDone is the number of restored chunks.
+	ChunksDone uint64 `json:"chunks_done"`
+	// ChunksTotal is the total number of chunks in the checkpoint.
+	ChunksTotal uint64 `json:"chunks_total"`
+
+	// BytesPerSecond is the average chunk download rate.
+	BytesPerSecond uint64 `json:"bytes_per_second"`
+	// ETA is the estimated time remaining until the checkpoint is restored.
+	ETA time.Duration `json:"eta"`
 }
@@ -87,6 +87,7 @@ func (w *Worker) checkpointChunkFetcher(
 	chunkDispatchCh chan *chunk,
 	chunkReturnCh chan *chunk,
 	errorCh chan int,
+	progress *checkpointSyncProgress,
 ) {
 	for {
 		var chunk *chunk
@@ -104,7 +105,7 @@ func (w *Worker) checkpointChunkFetcher(
 		defer cancel()
 
 		// Fetch chunk from peers.
-		rsp, pf, err := w.fetchChunk(chunkCtx, chunk)
+		rsp, pf, err := w.fetchChunkHedged(chunkCtx, chunk)
 		if err != nil {
 			w.logger.Error("failed to fetch chunk from peers",
 				"err", err,
@@ -118,6 +119,10 @@ func (w *Worker) checkpointChunkFetcher(
 		done, err := w.localStorage.Checkpointer().RestoreChunk(chunkCtx, chunk.Index, bytes.NewBuffer(rsp))
 		cancel()
 
+		if err == nil {
+			progress.chunkRestored(len(rsp))
+		}
+
 		switch {
 		case done:
 			pf.RecordSuccess()
@@ -155,25 +160,32 @@ func (w *Worker) checkpointChunkFetcher(
 	}
 }
 
-// fetchChunk fetches chunk using checkpoint sync p2p protocol client.
+// fetchChunkHedged fetches chunk from the peers that advertised its checkpoint, preferring
+// low-latency peers and hedging slow requests by also requesting the chunk from another peer.
+//
+// In case none of the peers are usable or all of them fail, it fallbacks to fetching the chunk
+// from any peer, including via the legacy storage sync protocol.
+func (w *Worker) fetchChunkHedged(ctx context.Context, chunk *chunk) ([]byte, rpc.PeerFeedback, error) {
+	rsp, pf, err := w.chunkPeers.fetchHedged(ctx, chunk.checkpoint.Peers, func(ctx context.Context, peer rpc.PeerFeedback) ([]byte, rpc.PeerFeedback, error) {
+		return w.fetchChunkFromPeers(ctx, chunk, []rpc.PeerFeedback{peer})
+	})
+	if err == nil || ctx.Err() != nil {
+		return rsp, pf, err
+	}
+	return w.fetchChunk(ctx, chunk, chunk.checkpoint.Peers)
+}
+
+// fetchChunk fetches chunk from the given peers using checkpoint sync p2p protocol client.
 //
 // In case of no peers or error, it fallbacks to the legacy storage sync protocol.
-func (w *Worker) fetchChunk(ctx context.Context, chunk *chunk) ([]byte, rpc.PeerFeedback, error) {
-	rsp1, pf, err := w.checkpointSync.GetCheckpointChunk(
-		ctx,
-		&checkpointsync.GetCheckpointChunkRequest{
-			Version: chunk.Version,
-			Root:    chunk.Root,
-			Index:   chunk.Index,
-			Digest:  chunk.Digest,
-		},
-		&checkpointsync.Checkpoint{
-			Metadata: chunk.checkpoint.Metadata,
-			Peers:    chunk.checkpoint.Peers,
-		},
-	)
+func (w *Worker) fetchChunk(ctx context.Context, chunk *chunk, peers []rpc.PeerFeedback) ([]byte, rpc.PeerFeedback, error) {
+	rsp, pf, err := w.fetchChunkFromPeers(ctx, chunk, peers)
 	if err == nil { // if NO error
-		return rsp1.Chunk, pf, nil
+		return rsp, pf, nil
+	}
+	if chunk.BaseRoot != nil {
+		// The legacy protocol does not support delta checkpoints.
+		return nil, nil, err
 	}
 
 	rsp2, pf, err := w.legacyStorageSync.GetCheckpointChunk(
@@ -186,7 +198,7 @@ func (w *Worker) fetchChunk(ctx context.Context, chunk *chunk) ([]byte, rpc.Peer
 		},
 		&synclegacy.Checkpoint{
 			Metadata: chunk.checkpoint.Metadata,
-			Peers:    chunk.checkpoint.Peers,
+			Peers:    peers,
 		},
 	)
 	if err != nil {
@@ -195,6 +207,29 @@ func (w *Worker) fetchChunk(ctx context.Context, chunk *chunk) ([]byte, rpc.Peer
 	return rsp2.Chunk, pf, nil
 }
 
+// fetchChunkFromPeers fetches chunk from the given peers using only the checkpoint sync p2p
+// protocol client.
+func (w *Worker) fetchChunkFromPeers(ctx context.Context, chunk *chunk, peers []rpc.PeerFeedback) ([]byte, rpc.PeerFeedback, error) {
+	rsp, pf, err := w.checkpointSync.GetCheckpointChunk(
+		ctx,
+		&checkpointsync.GetCheckpointChunkRequest{
+			Version:  chunk.Version,
+			Root:     chunk.Root,
+			Index:    chunk.Index,
+			Digest:   chunk.Digest,
+			BaseRoot: chunk.BaseRoot,
+		},
+		&checkpointsync.Checkpoint{
+			Metadata: chunk.checkpoint.Metadata,
+			Peers:    peers,
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rsp.Chunk, pf, nil
+}
+
 func (w *Worker) handleCheckpoint(ctx context.Context, check *checkpointsync.Checkpoint, maxParallelRequests uint) (cpStatus int, rerr error) {
 	if err := w.localStorage.Checkpointer().StartRestore(ctx, check.Metadata); err != nil {
 		// Any previous restores were already aborted by the driver up the call stack, so
@@ -223,6 +258,11 @@ func (w *Worker) handleCheckpoint(ctx context.Context, check *checkpointsync.Che
 	chunkReturnCh := make(chan *chunk, maxParallelRequests)
 	errorCh := make(chan int, maxParallelRequests)
 
+	progress := newCheckpointSyncProgress(check.Root, uint64(len(check.Chunks)))
+	w.statusLock.Lock()
+	w.checkpointSyncProgress = progress
+	w.statusLock.Unlock()
+
 	chunkCtx, cancel := context.WithCancel(ctx)
 
 	// Spawn the worker group to fetch and restore checkpoint chunks.
@@ -230,7 +270,7 @@ func (w *Worker) handleCheckpoint(ctx context.Context, check *checkpointsync.Che
 	doneCh := make(chan any)
 	for range maxParallelRequests {
 		workerGroup.Go(func() {
-			w.checkpointChunkFetcher(chunkCtx, chunkDispatchCh, chunkReturnCh, errorCh)
+			w.checkpointChunkFetcher(chunkCtx, chunkDispatchCh, chunkReturnCh, errorCh, progress)
 		})
 	}
 	go func() {
@@ -255,10 +295,11 @@ func (w *Worker) handleCheckpoint(ctx context.Context, check *checkpointsync.Che
 	for i, c := range check.Chunks {
 		heap.Push(chunks, &chunk{
 			ChunkMetadata: &checkpoint.ChunkMetadata{
-				Version: check.Version,
-				Index:   uint64(i),
-				Digest:  c,
-				Root:    check.Root,
+				Version:  check.Version,
+				Index:    uint64(i),
+				Digest:   c,
+				Root:     check.Root,
+				BaseRoot: check.BaseRoot,
 			},
 			checkpoint: check,
 		})
@@ -332,12 +373,21 @@ func (w *Worker) getCheckpointList(ctx context.Context) ([]*checkpointsync.Check
 
 // fetchCheckpoints fetches checkpoints using checkpoint sync p2p protocol client.
 //
-// In case of no peers, error or no checkpoints, it fallbacks to the legacy storage sync protocol.
+// Checkpoints of all supported versions are requested, including delta checkpoints. In case of
+// no peers, error or no checkpoints, it fallbacks to the legacy storage sync protocol.
 func (w *Worker) fetchCheckpoints(ctx context.Context) ([]*checkpointsync.Checkpoint, error) {
-	list1, err := w.checkpointSync.GetCheckpoints(ctx, &checkpointsync.GetCheckpointsRequest{
-		Version: 1,
-	})
-	if err == nil && len(list1) > 0 { // if NO error and at least one checkpoint
+	var list1 []*checkpointsync.Checkpoint
+	for _, version := range checkpoint.SupportedVersions {
+		cps, err := w.checkpointSync.GetCheckpoints(ctx, &checkpointsync.GetCheckpointsRequest{
+			Version:       version,
+			IncludeDeltas: true,
+		})
+		if err != nil {
+			continue
+		}
+		list1 = append(list1, cps...)
+	}
+	if len(list1) > 0 { // if at least one checkpoint
 		return list1, nil
 	}
 
@@ -357,11 +407,19 @@ func (w *Worker) fetchCheckpoints(ctx context.Context) ([]*checkpointsync.Checkp
 	return cps, nil
 }
 
-// sortCheckpoints sorts the slice in-place (descending by version, peers, hash).
+// sortCheckpoints sorts the slice in-place (descending by version, delta checkpoints with the
+// most recent base first, then descending by peers, hash).
 func sortCheckpoints(s []*checkpointsync.Checkpoint) {
+	baseVersion := func(cp *checkpointsync.Checkpoint) int64 {
+		if !cp.IsDelta() {
+			return -1
+		}
+		return int64(cp.BaseRoot.Version)
+	}
 	slices.SortFunc(s, func(a, b *checkpointsync.Checkpoint) int {
 		return cmp.Or(
 			cmp.Compare(b.Root.Version, a.Root.Version),
+			cmp.Compare(baseVersion(b), baseVersion(a)),
 			cmp.Compare(len(b.Peers), len(a.Peers)),
 			bytes.Compare(b.Root.Hash[:], a.Root.Hash[:]),
 		)
@@ -391,7 +449,17 @@ func (w *Worker) checkCheckpointUsable(cp *checkpointsync.Checkpoint, remainingM
 		lastVersion = lastStateRoot.Version
 	}
 
-	return lastVersion < cp.Root.Version && remainingMask.contains(cp.Root.Type)
+	if lastVersion >= cp.Root.Version || !remainingMask.contains(cp.Root.Type) {
+		return false
+	}
+
+	// Delta checkpoints can only be restored on top of a local base root. Chains of multiple
+	// delta checkpoints are not followed since each intermediate version would need to be
+	// finalized without the accompanying roots, in which case a full checkpoint is used instead.
+	if cp.IsDelta() {
+		return w.localStorage.NodeDB().HasRoot(*cp.BaseRoot)
+	}
+	return true
 }
 
 func (w *Worker) syncCheckpoints(ctx context.Context, genesisRound uint64, wantOnlyGenesis bool) (*blockSummary, error) {
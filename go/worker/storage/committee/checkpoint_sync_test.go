@@ -1,15 +1,27 @@
 package committee
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/p2p/rpc"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	runtimeHistory "github.com/oasisprotocol/oasis-core/go/runtime/history"
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/database"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/committee"
 	"github.com/oasisprotocol/oasis-core/go/worker/storage/p2p/checkpointsync"
 )
 
@@ -102,3 +114,191 @@ func TestValidateCheckpoint(t *testing.T) {
 		})
 	}
 }
+
+type testRuntime struct {
+	runtimeRegistry.Runtime
+
+	history runtimeHistory.History
+}
+
+func (r *testRuntime) History() runtimeHistory.History {
+	return r.history
+}
+
+type testHistory struct {
+	runtimeHistory.History
+
+	blocks map[uint64]*block.Block
+}
+
+func (h *testHistory) GetCommittedBlock(_ context.Context, round uint64) (*block.Block, error) {
+	blk, ok := h.blocks[round]
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", round)
+	}
+	return blk, nil
+}
+
+// testCheckpointSyncClient serves checkpoints directly from the given storage backend and
+// records all chunk requests.
+type testCheckpointSyncClient struct {
+	checkpointsync.Client
+
+	backend storageApi.LocalBackend
+
+	mu            sync.Mutex
+	chunkRequests []*checkpointsync.GetCheckpointChunkRequest
+}
+
+func (c *testCheckpointSyncClient) GetCheckpoints(ctx context.Context, request *checkpointsync.GetCheckpointsRequest) ([]*checkpointsync.Checkpoint, error) {
+	metas, err := c.backend.Checkpointer().GetCheckpoints(ctx, &checkpoint.GetCheckpointsRequest{
+		Version:       request.Version,
+		IncludeDeltas: request.IncludeDeltas,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var cps []*checkpointsync.Checkpoint
+	for _, meta := range metas {
+		cps = append(cps, &checkpointsync.Checkpoint{
+			Metadata: meta,
+			Peers:    []rpc.PeerFeedback{rpc.NewNopPeerFeedback()},
+		})
+	}
+	return cps, nil
+}
+
+func (c *testCheckpointSyncClient) GetCheckpointChunk(
+	ctx context.Context,
+	request *checkpointsync.GetCheckpointChunkRequest,
+	_ *checkpointsync.Checkpoint,
+) (*checkpointsync.GetCheckpointChunkResponse, rpc.PeerFeedback, error) {
+	c.mu.Lock()
+	c.chunkRequests = append(c.chunkRequests, request)
+	c.mu.Unlock()
+
+	var buf bytes.Buffer
+	err := c.backend.Checkpointer().GetCheckpointChunk(ctx, &checkpoint.ChunkMetadata{
+		Version:  request.Version,
+		Root:     request.Root,
+		Index:    request.Index,
+		Digest:   request.Digest,
+		BaseRoot: request.BaseRoot,
+	}, &buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &checkpointsync.GetCheckpointChunkResponse{Chunk: buf.Bytes()}, rpc.NewNopPeerFeedback(), nil
+}
+
+func TestSyncCheckpointsDelta(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("checkpoint sync delta"), 0)
+
+	newBackend := func() storageApi.LocalBackend {
+		backend, err := database.New(&storageApi.Config{
+			Backend:      database.BackendNamePathBadger,
+			DB:           t.TempDir(),
+			Namespace:    runtimeID,
+			MaxCacheSize: 16 * 1024 * 1024,
+		})
+		require.NoError(err, "database.New")
+		t.Cleanup(backend.Cleanup)
+		return backend
+	}
+	commit := func(tree mkvs.Tree, rootType node.RootType, version uint64) node.Root {
+		_, rootHash, err := tree.Commit(ctx, runtimeID, version)
+		require.NoError(err, "Commit")
+		return node.Root{Namespace: runtimeID, Version: version, Type: rootType, Hash: rootHash}
+	}
+	insertBase := func(tree mkvs.Tree) {
+		for i := range 1000 {
+			err := tree.Insert(ctx, []byte(fmt.Sprintf("key %d", i)), []byte(fmt.Sprintf("value %d", i)))
+			require.NoError(err, "Insert")
+		}
+	}
+
+	// The server has both rounds, while the client only has the state of the first round.
+	server, client := newBackend(), newBackend()
+
+	serverTree := mkvs.New(nil, server.NodeDB(), node.RootTypeState)
+	insertBase(serverTree)
+	baseRoot := commit(serverTree, node.RootTypeState, 1)
+	require.NoError(server.NodeDB().Finalize([]node.Root{baseRoot}), "Finalize")
+
+	for i := 0; i < 1000; i += 100 {
+		err := serverTree.Insert(ctx, []byte(fmt.Sprintf("key %d", i)), []byte("updated"))
+		require.NoError(err, "Insert")
+	}
+	stateRoot := commit(serverTree, node.RootTypeState, 2)
+	ioTree := mkvs.New(nil, server.NodeDB(), node.RootTypeIO)
+	require.NoError(ioTree.Insert(ctx, []byte("tx"), []byte("output")), "Insert")
+	ioRoot := commit(ioTree, node.RootTypeIO, 2)
+	require.NoError(server.NodeDB().Finalize([]node.Root{stateRoot, ioRoot}), "Finalize")
+
+	clientTree := mkvs.New(nil, client.NodeDB(), node.RootTypeState)
+	insertBase(clientTree)
+	require.Equal(baseRoot, commit(clientTree, node.RootTypeState, 1))
+	require.NoError(client.NodeDB().Finalize([]node.Root{baseRoot}), "Finalize")
+
+	fullCp, err := server.Checkpointer().CreateCheckpoint(ctx, stateRoot, 1024, 0)
+	require.NoError(err, "CreateCheckpoint")
+	deltaCp, err := server.Checkpointer().CreateDeltaCheckpoint(ctx, stateRoot, baseRoot, 1024)
+	require.NoError(err, "CreateDeltaCheckpoint")
+	require.Less(len(deltaCp.Chunks), len(fullCp.Chunks), "delta checkpoint should be smaller")
+	_, err = server.Checkpointer().CreateCheckpoint(ctx, ioRoot, 1024, 0)
+	require.NoError(err, "CreateCheckpoint")
+
+	blk := block.NewGenesisBlock(runtimeID, 0)
+	blk.Header.Round = 2
+	blk.Header.StateRoot = stateRoot.Hash
+	blk.Header.IORoot = ioRoot.Hash
+
+	cpSync := &testCheckpointSyncClient{backend: server}
+	w := &Worker{
+		commonNode: &committee.Node{
+			Runtime: &testRuntime{
+				history: &testHistory{blocks: map[uint64]*block.Block{2: blk}},
+			},
+		},
+		logger:            logging.GetLogger("worker/storage/committee/test"),
+		localStorage:      client,
+		checkpointSync:    cpSync,
+		chunkPeers:        newChunkPeers(),
+		checkpointSyncCfg: &CheckpointSyncConfig{ChunkFetcherCount: 2},
+	}
+	w.syncedState.Roots = []storageApi.Root{baseRoot}
+
+	summary, err := w.syncCheckpoints(ctx, 0, false)
+	require.NoError(err, "syncCheckpoints")
+	require.EqualValues(2, summary.Round)
+	require.ElementsMatch([]storageApi.Root{stateRoot, ioRoot}, summary.Roots)
+
+	// State chunks should have only been fetched from the delta checkpoint.
+	var stateChunks int
+	for _, req := range cpSync.chunkRequests {
+		if req.Root.Type != node.RootTypeState {
+			continue
+		}
+		stateChunks++
+		require.NotNil(req.BaseRoot, "state chunks should be fetched from the delta checkpoint")
+		require.EqualValues(baseRoot, *req.BaseRoot)
+	}
+	require.Equal(len(deltaCp.Chunks), stateChunks)
+
+	// The restored state should match the server's.
+	require.True(client.NodeDB().HasRoot(stateRoot), "state root should exist after sync")
+	require.True(client.NodeDB().HasRoot(ioRoot), "i/o root should exist after sync")
+	tree := mkvs.NewWithRoot(nil, client.NodeDB(), stateRoot)
+	defer tree.Close()
+	for _, i := range []int{0, 1, 500, 999} {
+		value, err := tree.Get(ctx, []byte(fmt.Sprintf("key %d", i)))
+		require.NoError(err, "Get")
+		expected := fmt.Sprintf("value %d", i)
+		if i%100 == 0 {
+			expected = "updated"
+		}
+		require.EqualValues(expected, value)
+	}
+}
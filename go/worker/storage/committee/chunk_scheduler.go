@@ -0,0 +1,294 @@
+package committee
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core"
+
+	"github.com/oasisprotocol/oasis-core/go/p2p/rpc"
+	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+)
+
+const (
+	// hedgeMinDelay is the minimum delay before a chunk request is hedged by requesting the same
+	// chunk from another peer.
+	hedgeMinDelay = 500 * time.Millisecond
+	// hedgeDefaultDelay is the hedge delay used for peers without any latency measurements.
+	hedgeDefaultDelay = 5 * time.Second
+	// hedgeLatencyMultiplier is the multiple of the average peer latency after which a chunk
+	// request is hedged.
+	hedgeLatencyMultiplier = 3
+
+	// unknownPeerLatency is the latency assumed for peers without any latency measurements.
+	unknownPeerLatency = time.Second
+	// peerLatencyInvAlpha is the inverse alpha (1/alpha) value for computing the exponential
+	// moving average of chunk peer latencies.
+	peerLatencyInvAlpha = 5
+
+	// badPeerExpiry is the duration for which a peer marked as bad is not used for fetching
+	// chunks.
+	badPeerExpiry = 10 * time.Minute
+)
+
+// errNoChunkPeers is the error returned when there are no usable peers to fetch a chunk from.
+var errNoChunkPeers = errors.New("storage: no usable peers to fetch chunk from")
+
+type chunkPeerStats struct {
+	successes  int
+	failures   int
+	avgLatency time.Duration
+	inFlight   int
+	badUntil   time.Time
+}
+
+// score returns the peer score (lower is better).
+//
+// Peers with low latency and few failures are preferred, while peers that are already serving
+// other chunk requests are penalized in order to spread the load across many peers.
+func (ps *chunkPeerStats) score() float64 {
+	latency := ps.avgLatency
+	if latency == 0 {
+		latency = unknownPeerLatency
+	}
+
+	var failRate float64
+	if ps.successes+ps.failures > 0 {
+		failRate = float64(ps.failures) / float64(ps.successes+ps.failures)
+	}
+
+	return float64(latency) * (1 + failRate) * float64(1+ps.inFlight)
+}
+
+func (ps *chunkPeerStats) recordLatency(latency time.Duration) {
+	if ps.avgLatency == 0 {
+		ps.avgLatency = latency
+		return
+	}
+	ps.avgLatency += (latency - ps.avgLatency) / peerLatencyInvAlpha
+}
+
+// chunkPeers keeps track of per-peer statistics used for scheduling checkpoint chunk requests.
+type chunkPeers struct {
+	sync.Mutex
+
+	peers map[core.PeerID]*chunkPeerStats
+}
+
+func newChunkPeers() *chunkPeers {
+	return &chunkPeers{
+		peers: make(map[core.PeerID]*chunkPeerStats),
+	}
+}
+
+func (cp *chunkPeers) getLocked(peerID core.PeerID) *chunkPeerStats {
+	ps := cp.peers[peerID]
+	if ps == nil {
+		ps = &chunkPeerStats{}
+		cp.peers[peerID] = ps
+	}
+	return ps
+}
+
+// Implements rpc.ClientListener.
+func (cp *chunkPeers) RecordSuccess(peerID core.PeerID, latency time.Duration) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	ps := cp.getLocked(peerID)
+	ps.successes++
+	ps.recordLatency(latency)
+}
+
+// Implements rpc.ClientListener.
+func (cp *chunkPeers) RecordFailure(peerID core.PeerID, latency time.Duration) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	ps := cp.getLocked(peerID)
+	ps.failures++
+	ps.recordLatency(latency)
+}
+
+// Implements rpc.ClientListener.
+func (cp *chunkPeers) RecordBadPeer(peerID core.PeerID) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	cp.getLocked(peerID).badUntil = time.Now().Add(badPeerExpiry)
+}
+
+// rank returns the given peers, excluding peers recently marked as bad, ordered by their score
+// (best first).
+func (cp *chunkPeers) rank(peers []rpc.PeerFeedback) []rpc.PeerFeedback {
+	cp.Lock()
+	defer cp.Unlock()
+
+	now := time.Now()
+	scores := make(map[core.PeerID]float64, len(peers))
+	ranked := make([]rpc.PeerFeedback, 0, len(peers))
+	for _, pf := range peers {
+		ps := cp.getLocked(pf.PeerID())
+		if now.Before(ps.badUntil) {
+			continue
+		}
+		scores[pf.PeerID()] = ps.score()
+		ranked = append(ranked, pf)
+	}
+	slices.SortStableFunc(ranked, func(a, b rpc.PeerFeedback) int {
+		sa, sb := scores[a.PeerID()], scores[b.PeerID()]
+		switch {
+		case sa < sb:
+			return -1
+		case sa > sb:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return ranked
+}
+
+// hedgeDelay returns the time to wait for a response from the given peer before requesting the
+// same chunk from another peer.
+func (cp *chunkPeers) hedgeDelay(peerID core.PeerID) time.Duration {
+	cp.Lock()
+	defer cp.Unlock()
+
+	ps := cp.getLocked(peerID)
+	if ps.avgLatency == 0 {
+		return hedgeDefaultDelay
+	}
+	return max(hedgeMinDelay, hedgeLatencyMultiplier*ps.avgLatency)
+}
+
+func (cp *chunkPeers) acquire(peerID core.PeerID) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	cp.getLocked(peerID).inFlight++
+}
+
+func (cp *chunkPeers) release(peerID core.PeerID) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	cp.getLocked(peerID).inFlight--
+}
+
+// chunkFetchFunc fetches a chunk from the given peer.
+type chunkFetchFunc func(ctx context.Context, peer rpc.PeerFeedback) ([]byte, rpc.PeerFeedback, error)
+
+// fetchHedged fetches a chunk from the best of the given peers. In case the peer does not respond
+// within its hedge delay, the same chunk is additionally requested from the next best peer and
+// the first successful response is used. On failure, the remaining peers are tried in order.
+func (cp *chunkPeers) fetchHedged(ctx context.Context, peers []rpc.PeerFeedback, fetch chunkFetchFunc) ([]byte, rpc.PeerFeedback, error) {
+	peers = cp.rank(peers)
+	if len(peers) == 0 {
+		return nil, nil, errNoChunkPeers
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		data []byte
+		pf   rpc.PeerFeedback
+		err  error
+	}
+	// Buffered so that requests still in flight on return never block.
+	resultCh := make(chan result, len(peers))
+
+	var next, pending int
+	request := func() {
+		peer := peers[next]
+		next++
+		pending++
+
+		cp.acquire(peer.PeerID())
+		go func() {
+			defer cp.release(peer.PeerID())
+
+			data, pf, err := fetch(ctx, peer)
+			resultCh <- result{data, pf, err}
+		}()
+	}
+
+	request()
+	hedgeTimer := time.NewTimer(cp.hedgeDelay(peers[0].PeerID()))
+	defer hedgeTimer.Stop()
+
+	var err error
+	for pending > 0 {
+		select {
+		case <-hedgeTimer.C:
+			if next < len(peers) {
+				request()
+			}
+		case res := <-resultCh:
+			pending--
+			if res.err == nil {
+				return res.data, res.pf, nil
+			}
+			err = res.err
+
+			if pending == 0 && next < len(peers) && ctx.Err() == nil {
+				request()
+				hedgeTimer.Reset(cp.hedgeDelay(peers[next-1].PeerID()))
+			}
+		}
+	}
+	return nil, nil, err
+}
+
+// checkpointSyncProgress tracks the progress of a checkpoint restore.
+type checkpointSyncProgress struct {
+	sync.Mutex
+
+	root        storageApi.Root
+	chunksDone  uint64
+	chunksTotal uint64
+	bytes       uint64
+	started     time.Time
+}
+
+func newCheckpointSyncProgress(root storageApi.Root, chunksTotal uint64) *checkpointSyncProgress {
+	return &checkpointSyncProgress{
+		root:        root,
+		chunksTotal: chunksTotal,
+		started:     time.Now(),
+	}
+}
+
+func (p *checkpointSyncProgress) chunkRestored(size int) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.chunksDone++
+	p.bytes += uint64(size)
+}
+
+func (p *checkpointSyncProgress) status() *api.CheckpointSyncProgress {
+	p.Lock()
+	defer p.Unlock()
+
+	status := api.CheckpointSyncProgress{
+		Root:        p.root,
+		ChunksDone:  p.chunksDone,
+		ChunksTotal: p.chunksTotal,
+	}
+
+	elapsed := time.Since(p.started)
+	if elapsed > 0 {
+		status.BytesPerSecond = uint64(float64(p.bytes) / elapsed.Seconds())
+	}
+	if p.chunksDone > 0 && p.chunksDone < p.chunksTotal {
+		remaining := p.chunksTotal - p.chunksDone
+		status.ETA = time.Duration(float64(elapsed) * float64(remaining) / float64(p.chunksDone))
+	}
+	return &status
+}
@@ -0,0 +1,166 @@
+package committee
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/p2p/rpc"
+	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
+)
+
+type testPeerFeedback struct {
+	rpc.PeerFeedback
+
+	id core.PeerID
+}
+
+func (pf *testPeerFeedback) PeerID() core.PeerID {
+	return pf.id
+}
+
+func newTestPeers(ids ...string) []rpc.PeerFeedback {
+	peers := make([]rpc.PeerFeedback, 0, len(ids))
+	for _, id := range ids {
+		peers = append(peers, &testPeerFeedback{
+			PeerFeedback: rpc.NewNopPeerFeedback(),
+			id:           core.PeerID(id),
+		})
+	}
+	return peers
+}
+
+func peerIDs(peers []rpc.PeerFeedback) []core.PeerID {
+	ids := make([]core.PeerID, 0, len(peers))
+	for _, pf := range peers {
+		ids = append(ids, pf.PeerID())
+	}
+	return ids
+}
+
+func TestChunkPeersRank(t *testing.T) {
+	require := require.New(t)
+
+	cp := newChunkPeers()
+	peers := newTestPeers("slow", "fast", "unknown", "bad", "flaky")
+
+	cp.RecordSuccess("slow", 3*time.Second)
+	cp.RecordSuccess("fast", 100*time.Millisecond)
+	cp.RecordSuccess("bad", 10*time.Millisecond)
+	cp.RecordBadPeer("bad")
+	cp.RecordSuccess("flaky", 600*time.Millisecond)
+	cp.RecordFailure("flaky", 600*time.Millisecond)
+
+	require.Equal([]core.PeerID{"fast", "flaky", "unknown", "slow"}, peerIDs(cp.rank(peers)))
+
+	// Peers serving other requests should be penalized.
+	for range 20 {
+		cp.acquire("fast")
+	}
+	require.Equal([]core.PeerID{"flaky", "unknown", "fast", "slow"}, peerIDs(cp.rank(peers)))
+	for range 20 {
+		cp.release("fast")
+	}
+	require.Equal([]core.PeerID{"fast", "flaky", "unknown", "slow"}, peerIDs(cp.rank(peers)))
+
+	// Bad peers should be used again once the bad mark expires.
+	cp.peers["bad"].badUntil = time.Now().Add(-time.Second)
+	require.Equal([]core.PeerID{"bad", "fast", "flaky", "unknown", "slow"}, peerIDs(cp.rank(peers)))
+}
+
+func TestChunkPeersHedgeDelay(t *testing.T) {
+	require := require.New(t)
+
+	cp := newChunkPeers()
+	cp.RecordSuccess("fast", 10*time.Millisecond)
+	cp.RecordSuccess("slow", time.Second)
+
+	require.Equal(hedgeDefaultDelay, cp.hedgeDelay("unknown"))
+	require.Equal(hedgeMinDelay, cp.hedgeDelay("fast"))
+	require.Equal(hedgeLatencyMultiplier*time.Second, cp.hedgeDelay("slow"))
+}
+
+func TestChunkPeersFetchHedged(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("NoPeers", func(t *testing.T) {
+		require := require.New(t)
+
+		cp := newChunkPeers()
+		cp.RecordBadPeer("bad")
+		_, _, err := cp.fetchHedged(ctx, newTestPeers("bad"), nil)
+		require.ErrorIs(err, errNoChunkPeers)
+	})
+
+	t.Run("Hedged", func(t *testing.T) {
+		require := require.New(t)
+
+		cp := newChunkPeers()
+		cp.RecordSuccess("slow", time.Millisecond)
+		cp.RecordSuccess("fast", 2*time.Millisecond)
+
+		data, pf, err := cp.fetchHedged(ctx, newTestPeers("slow", "fast"), func(ctx context.Context, peer rpc.PeerFeedback) ([]byte, rpc.PeerFeedback, error) {
+			if peer.PeerID() == "slow" {
+				// Never respond.
+				<-ctx.Done()
+				return nil, nil, ctx.Err()
+			}
+			return []byte("chunk"), peer, nil
+		})
+		require.NoError(err, "fetchHedged")
+		require.Equal([]byte("chunk"), data)
+		require.EqualValues("fast", pf.PeerID(), "slow request should be hedged")
+	})
+
+	t.Run("Failover", func(t *testing.T) {
+		require := require.New(t)
+
+		cp := newChunkPeers()
+		var tried []core.PeerID
+		data, pf, err := cp.fetchHedged(ctx, newTestPeers("a", "b", "c"), func(_ context.Context, peer rpc.PeerFeedback) ([]byte, rpc.PeerFeedback, error) {
+			tried = append(tried, peer.PeerID())
+			if peer.PeerID() != "c" {
+				return nil, nil, fmt.Errorf("failed")
+			}
+			return []byte("chunk"), peer, nil
+		})
+		require.NoError(err, "fetchHedged")
+		require.Equal([]byte("chunk"), data)
+		require.EqualValues("c", pf.PeerID())
+		require.Equal([]core.PeerID{"a", "b", "c"}, tried, "peers should be tried in order")
+	})
+
+	t.Run("AllFailed", func(t *testing.T) {
+		require := require.New(t)
+
+		cp := newChunkPeers()
+		_, _, err := cp.fetchHedged(ctx, newTestPeers("a", "b"), func(context.Context, rpc.PeerFeedback) ([]byte, rpc.PeerFeedback, error) {
+			return nil, nil, fmt.Errorf("failed")
+		})
+		require.Error(err, "fetchHedged should fail when all peers fail")
+	})
+}
+
+func TestCheckpointSyncProgress(t *testing.T) {
+	require := require.New(t)
+
+	root := storageApi.Root{Version: 42}
+	p := newCheckpointSyncProgress(root, 4)
+	p.started = time.Now().Add(-time.Second)
+
+	status := p.status()
+	require.Equal(root, status.Root)
+	require.EqualValues(0, status.ChunksDone)
+	require.EqualValues(4, status.ChunksTotal)
+	require.Zero(status.ETA, "ETA should be unknown before any chunks are restored")
+
+	p.chunkRestored(1000)
+	status = p.status()
+	require.EqualValues(1, status.ChunksDone)
+	require.InDelta(1000, status.BytesPerSecond, 100)
+	require.InDelta(3*time.Second, status.ETA, float64(300*time.Millisecond))
+}
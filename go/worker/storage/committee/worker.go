@@ -145,6 +145,8 @@ type Worker struct {
 	checkpointSync    checkpointsync.Client
 	legacyStorageSync synclegacy.Client
 
+	chunkPeers *chunkPeers
+
 	undefinedRound uint64
 
 	checkpointer         checkpoint.Checkpointer
@@ -154,8 +156,9 @@ type Worker struct {
 	syncedLock  sync.RWMutex
 	syncedState blockSummary
 
-	statusLock sync.RWMutex
-	status     api.StorageWorkerStatus
+	statusLock             sync.RWMutex
+	status                 api.StorageWorkerStatus
+	checkpointSyncProgress *checkpointSyncProgress
 
 	diffCh     chan *fetchedDiff
 	finalizeCh chan finalizeResult
@@ -197,6 +200,7 @@ func New(
 		localStorage: localStorage,
 
 		checkpointSyncCfg: checkpointSyncCfg,
+		chunkPeers:        newChunkPeers(),
 
 		status: api.StatusInitializing,
 
@@ -245,6 +249,7 @@ func New(
 	w.legacyStorageSync = synclegacy.NewClient(commonNode.P2P, commonNode.ChainContext, commonNode.Runtime.ID())
 	w.diffSync = diffsync.NewClient(commonNode.P2P, commonNode.ChainContext, commonNode.Runtime.ID())
 	w.checkpointSync = checkpointsync.NewClient(commonNode.P2P, commonNode.ChainContext, commonNode.Runtime.ID())
+	w.checkpointSync.RegisterListener(w.chunkPeers)
 
 	return w, nil
 }
@@ -273,6 +278,11 @@ func (w *Worker) newCheckpointer(commonNode *committee.Node, localStorage storag
 				return nil, fmt.Errorf("failed to retrieve genesis block: %w", rerr)
 			}
 
+			version, rerr := config.GlobalConfig.Storage.Checkpointer.CheckpointVersion()
+			if rerr != nil {
+				return nil, rerr
+			}
+
 			var threads uint16
 			if config.GlobalConfig.Storage.Checkpointer.ParallelChunker {
 				threads = chunkerThreads
@@ -284,6 +294,8 @@ func (w *Worker) newCheckpointer(commonNode *committee.Node, localStorage storag
 				ChunkSize:      rt.Storage.CheckpointChunkSize,
 				InitialVersion: blk.Header.Round,
 				ChunkerThreads: threads,
+				Version:        version,
+				Deltas:         config.GlobalConfig.Storage.Checkpointer.DeltaCheckpoints,
 			}, nil
 		},
 		GetRoots: func(ctx context.Context, version uint64) ([]storageApi.Root, error) {
@@ -316,10 +328,14 @@ func (w *Worker) GetStatus(context.Context) (*api.Status, error) {
 	w.statusLock.RLock()
 	defer w.statusLock.RUnlock()
 
-	return &api.Status{
+	status := &api.Status{
 		LastFinalizedRound: w.syncedState.Round,
 		Status:             w.status,
-	}, nil
+	}
+	if w.status == api.StatusSyncingCheckpoints && w.checkpointSyncProgress != nil {
+		status.CheckpointSync = w.checkpointSyncProgress.status()
+	}
+	return status, nil
 }
 
 func (w *Worker) PauseCheckpointer(pause bool) error {
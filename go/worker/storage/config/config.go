@@ -2,8 +2,10 @@
 package config
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db"
 )
 
@@ -33,12 +35,34 @@ type CheckpointerConfig struct {
 	CheckInterval time.Duration `yaml:"check_interval"`
 	// ParallelChunker specifies if the new parallel chunking algorithm is used.
 	ParallelChunker bool `yaml:"parallel_chunker"`
+	// DeltaCheckpoints specifies whether delta checkpoints against the previous checkpoint should
+	// be created in addition to full checkpoints.
+	DeltaCheckpoints bool `yaml:"delta_checkpoints,omitempty"`
+	// ChunkCompression is the compression algorithm used for checkpoint chunks (snappy or zstd).
+	ChunkCompression string `yaml:"chunk_compression,omitempty"`
+}
+
+// CheckpointVersion returns the checkpoint version corresponding to the configured chunk
+// compression algorithm.
+func (c *CheckpointerConfig) CheckpointVersion() (uint16, error) {
+	switch c.ChunkCompression {
+	case "", "snappy":
+		return checkpoint.VersionSnappy, nil
+	case "zstd":
+		return checkpoint.VersionZstd, nil
+	default:
+		return 0, fmt.Errorf("unsupported checkpoint chunk compression: %s", c.ChunkCompression)
+	}
 }
 
 // Validate validates the configuration settings.
 func (c *Config) Validate() error {
 	if c.Backend != "auto" {
-		_, err := db.GetBackendByName(c.Backend)
+		if _, err := db.GetBackendByName(c.Backend); err != nil {
+			return err
+		}
+	}
+	if _, err := c.Checkpointer.CheckpointVersion(); err != nil {
 		return err
 	}
 	return nil
@@ -53,9 +77,11 @@ func DefaultConfig() Config {
 		PublicRPCEnabled:       false,
 		CheckpointSyncDisabled: false,
 		Checkpointer: CheckpointerConfig{
-			Enabled:         false,
-			CheckInterval:   1 * time.Minute,
-			ParallelChunker: false,
+			Enabled:          false,
+			CheckInterval:    1 * time.Minute,
+			ParallelChunker:  false,
+			DeltaCheckpoints: false,
+			ChunkCompression: "snappy",
 		},
 	}
 }
@@ -32,6 +32,9 @@ type Client interface {
 		request *GetCheckpointChunkRequest,
 		cp *Checkpoint,
 	) (*GetCheckpointChunkResponse, rpc.PeerFeedback, error)
+
+	// RegisterListener subscribes the listener to the underlying RPC client notification events.
+	RegisterListener(l rpc.ClientListener)
 }
 
 // Checkpoint contains checkpoint metadata together with peer information.
@@ -112,6 +115,10 @@ func (c *client) GetCheckpointChunk(
 	return &rsp, pf, nil
 }
 
+func (c *client) RegisterListener(l rpc.ClientListener) {
+	c.rc.RegisterListener(l)
+}
+
 // NewClient creates a new checkpoint sync protocol client.
 //
 // Moreover, it ensures underlying p2p service starts tracking protocol peers.
@@ -35,7 +35,8 @@ const (
 
 // GetCheckpointsRequest is a GetCheckpoints request.
 type GetCheckpointsRequest struct {
-	Version uint16 `json:"version"`
+	Version       uint16 `json:"version"`
+	IncludeDeltas bool   `json:"include_deltas,omitempty"`
 }
 
 // GetCheckpointsResponse is a response to a GetCheckpoints request.
@@ -55,6 +56,9 @@ type GetCheckpointChunkRequest struct {
 	Root    api.Root  `json:"root"`
 	Index   uint64    `json:"index"`
 	Digest  hash.Hash `json:"digest"`
+
+	// BaseRoot is the base root in case the chunk belongs to a delta checkpoint.
+	BaseRoot *api.Root `json:"base_root,omitempty"`
 }
 
 // GetCheckpointChunkResponse is a response to a GetCheckpointChunk request.
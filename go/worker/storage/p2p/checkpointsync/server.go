@@ -38,7 +38,8 @@ func (s *service) HandleRequest(ctx context.Context, method string, body cbor.Ra
 
 func (s *service) handleGetCheckpoints(ctx context.Context, request *GetCheckpointsRequest) (*GetCheckpointsResponse, error) {
 	cps, err := s.backend.GetCheckpoints(ctx, &checkpoint.GetCheckpointsRequest{
-		Version: request.Version,
+		Version:       request.Version,
+		IncludeDeltas: request.IncludeDeltas,
 	})
 	if err != nil {
 		return nil, err
@@ -53,10 +54,11 @@ func (s *service) handleGetCheckpointChunk(ctx context.Context, request *GetChec
 	// Consider using stream resource manager to track buffer use.
 	var buf bytes.Buffer
 	err := s.backend.GetCheckpointChunk(ctx, &checkpoint.ChunkMetadata{
-		Version: request.Version,
-		Root:    request.Root,
-		Index:   request.Index,
-		Digest:  request.Digest,
+		Version:  request.Version,
+		Root:     request.Root,
+		Index:    request.Index,
+		Digest:   request.Digest,
+		BaseRoot: request.BaseRoot,
 	}, &buf)
 	if err != nil {
 		return nil, err
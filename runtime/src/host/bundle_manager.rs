@@ -2,7 +2,10 @@ use std::collections::BTreeMap;
 
 use async_trait::async_trait;
 
-use crate::{common::crypto::hash::Hash, protocol::Protocol};
+use crate::{
+    common::crypto::{hash::Hash, signature::PublicKey},
+    protocol::Protocol,
+};
 
 use super::{host_rpc_call, Error};
 
@@ -188,4 +191,32 @@ pub struct BundleInfo {
 pub struct ComponentInfo {
     /// Component name.
     pub name: String,
+    /// Status of the component in case it is hosted.
+    #[cbor(optional)]
+    pub status: Option<ComponentStatus>,
+}
+
+/// Status of a hosted component.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct ComponentStatus {
+    /// Component state. One of `starting`, `running`, `stopped` or `crash_looping`.
+    pub state: String,
+    /// Number of times the component terminated unexpectedly or failed to start.
+    #[cbor(optional)]
+    pub crashes: u64,
+    /// Number of seconds the component has been running for.
+    #[cbor(optional)]
+    pub uptime: u64,
+    /// CPU time in milliseconds consumed by the component since it was last started.
+    #[cbor(optional)]
+    pub cpu_time: u64,
+    /// Amount of resident memory used by the component in bytes.
+    #[cbor(optional)]
+    pub memory: u64,
+    /// UNIX timestamp of the last successful attestation of the component.
+    #[cbor(optional)]
+    pub last_attestation: u64,
+    /// Runtime attestation key of the component.
+    #[cbor(optional)]
+    pub rak: Option<PublicKey>,
 }
@@ -0,0 +1,47 @@
+use async_trait::async_trait;
+
+use crate::protocol::Protocol;
+
+use super::{host_rpc_call, Error};
+
+/// Name of the local RPC endpoint for metrics.
+pub const LOCAL_RPC_ENDPOINT_METRICS: &str = "metrics";
+
+/// Name of the MetricsPush method.
+pub const METHOD_METRICS_PUSH: &str = "MetricsPush";
+
+/// Metrics interface.
+#[allow(clippy::double_must_use)]
+#[async_trait]
+pub trait Metrics: Send + Sync {
+    /// Request to host to publish metrics of the component.
+    ///
+    /// The `PermissionMetricsPush` permission is required to call this method.
+    async fn metrics_push(&self, args: MetricsPushRequest) -> Result<MetricsPushResponse, Error>;
+}
+
+#[allow(clippy::double_must_use)]
+#[async_trait]
+impl Metrics for Protocol {
+    async fn metrics_push(&self, args: MetricsPushRequest) -> Result<MetricsPushResponse, Error> {
+        host_rpc_call(self, LOCAL_RPC_ENDPOINT_METRICS, METHOD_METRICS_PUSH, args).await
+    }
+}
+
+/// Request to publish metrics of the component.
+///
+/// The pushed metrics replace any metrics previously pushed by the component. They are re-exported
+/// by the node with names prefixed by `oasis_rofl_<component-name>_` and with the `runtime` label
+/// set to the runtime identifier. Metrics with duplicate series, inconsistent label names or
+/// conflicting with metrics pushed by other components are rejected.
+///
+/// The `PermissionMetricsPush` permission is required to call this method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct MetricsPushRequest {
+    /// Counters, gauges and histograms in the Prometheus text exposition format.
+    pub metrics: String,
+}
+
+/// Response from the MetricsPush method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct MetricsPushResponse {}
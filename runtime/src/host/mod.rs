@@ -13,6 +13,7 @@ use crate::{
 pub mod attestation;
 pub mod bundle_manager;
 pub mod log_manager;
+pub mod metrics;
 pub mod volume_manager;
 
 /// Errors.
@@ -72,6 +73,9 @@ pub trait Host: Send + Sync {
     /// Log manager interface.
     fn log_manager(&self) -> &dyn log_manager::LogManager;
 
+    /// Metrics interface.
+    fn metrics(&self) -> &dyn metrics::Metrics;
+
     /// Attestation interface.
     fn attestation(&self) -> &dyn attestation::Attestation;
 }
@@ -134,6 +138,10 @@ impl Host for Protocol {
         self
     }
 
+    fn metrics(&self) -> &dyn metrics::Metrics {
+        self
+    }
+
     fn attestation(&self) -> &dyn attestation::Attestation {
         self
     }
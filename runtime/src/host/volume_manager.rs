@@ -15,6 +15,12 @@ pub const METHOD_VOLUME_ADD: &str = "VolumeAdd";
 pub const METHOD_VOLUME_REMOVE: &str = "VolumeRemove";
 /// Name of the VolumeList method.
 pub const METHOD_VOLUME_LIST: &str = "VolumeList";
+/// Name of the VolumeSnapshot method.
+pub const METHOD_VOLUME_SNAPSHOT: &str = "VolumeSnapshot";
+/// Name of the VolumeRestore method.
+pub const METHOD_VOLUME_RESTORE: &str = "VolumeRestore";
+/// Name of the VolumeSnapshotRemove method.
+pub const METHOD_VOLUME_SNAPSHOT_REMOVE: &str = "VolumeSnapshotRemove";
 
 /// Volume manager interface.
 #[allow(clippy::double_must_use)]
@@ -35,6 +41,30 @@ pub trait VolumeManager: Send + Sync {
     ///
     /// The `PermissionVolumeAdd` permission is required to call this method.
     async fn volume_list(&self, args: VolumeListRequest) -> Result<VolumeListResponse, Error>;
+
+    /// Request to host to create a point-in-time snapshot of a volume.
+    ///
+    /// The `PermissionVolumeSnapshot` permission is required to call this method.
+    async fn volume_snapshot(
+        &self,
+        args: VolumeSnapshotRequest,
+    ) -> Result<VolumeSnapshotResponse, Error>;
+
+    /// Request to host to restore a volume from a snapshot.
+    ///
+    /// The `PermissionVolumeSnapshot` permission is required to call this method.
+    async fn volume_restore(
+        &self,
+        args: VolumeRestoreRequest,
+    ) -> Result<VolumeRestoreResponse, Error>;
+
+    /// Request to host to remove a volume snapshot.
+    ///
+    /// The `PermissionVolumeSnapshot` permission is required to call this method.
+    async fn volume_snapshot_remove(
+        &self,
+        args: VolumeSnapshotRemoveRequest,
+    ) -> Result<VolumeSnapshotRemoveResponse, Error>;
 }
 
 #[allow(clippy::double_must_use)]
@@ -72,6 +102,45 @@ impl VolumeManager for Protocol {
         )
         .await
     }
+
+    async fn volume_snapshot(
+        &self,
+        args: VolumeSnapshotRequest,
+    ) -> Result<VolumeSnapshotResponse, Error> {
+        host_rpc_call(
+            self,
+            LOCAL_RPC_ENDPOINT_VOLUME_MANAGER,
+            METHOD_VOLUME_SNAPSHOT,
+            args,
+        )
+        .await
+    }
+
+    async fn volume_restore(
+        &self,
+        args: VolumeRestoreRequest,
+    ) -> Result<VolumeRestoreResponse, Error> {
+        host_rpc_call(
+            self,
+            LOCAL_RPC_ENDPOINT_VOLUME_MANAGER,
+            METHOD_VOLUME_RESTORE,
+            args,
+        )
+        .await
+    }
+
+    async fn volume_snapshot_remove(
+        &self,
+        args: VolumeSnapshotRemoveRequest,
+    ) -> Result<VolumeSnapshotRemoveResponse, Error> {
+        host_rpc_call(
+            self,
+            LOCAL_RPC_ENDPOINT_VOLUME_MANAGER,
+            METHOD_VOLUME_SNAPSHOT_REMOVE,
+            args,
+        )
+        .await
+    }
 }
 
 /// Request to add a volume.
@@ -126,4 +195,71 @@ pub struct VolumeInfo {
     pub id: String,
     /// Labels assigned to this volume.
     pub labels: BTreeMap<String, String>,
+    /// Disk space used by the volume in bytes, including its snapshots.
+    #[cbor(optional)]
+    pub size: u64,
+    /// Snapshots of this volume.
+    #[cbor(optional)]
+    pub snapshots: Vec<VolumeSnapshotInfo>,
+}
+
+/// Volume snapshot information.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeSnapshotInfo {
+    /// Name of the snapshot.
+    pub name: String,
+    /// UNIX timestamp when the snapshot was created.
+    pub created_at: u64,
+    /// Size of the snapshot in bytes.
+    #[cbor(optional)]
+    pub size: u64,
+}
+
+/// Request to create a point-in-time snapshot of a volume.
+///
+/// To obtain a consistent snapshot, the volume should not be in use by any component.
+///
+/// The `PermissionVolumeSnapshot` permission is required to call this method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeSnapshotRequest {
+    /// Unique volume identifier.
+    pub id: String,
+    /// Name of the snapshot.
+    pub name: String,
+}
+
+/// Response from the VolumeSnapshot method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeSnapshotResponse {}
+
+/// Request to restore a volume from a snapshot.
+///
+/// The volume must not be in use by any component while it is being restored.
+///
+/// The `PermissionVolumeSnapshot` permission is required to call this method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeRestoreRequest {
+    /// Unique volume identifier.
+    pub id: String,
+    /// Name of the snapshot.
+    pub name: String,
 }
+
+/// Response from the VolumeRestore method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeRestoreResponse {}
+
+/// Request to remove a volume snapshot.
+///
+/// The `PermissionVolumeSnapshot` permission is required to call this method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeSnapshotRemoveRequest {
+    /// Unique volume identifier.
+    pub id: String,
+    /// Name of the snapshot.
+    pub name: String,
+}
+
+/// Response from the VolumeSnapshotRemove method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeSnapshotRemoveResponse {}